@@ -11,40 +11,65 @@ import (
 	"github.com/paysuper/paysuper-management-api/internal/casbin"
 	"github.com/spf13/cobra"
 	"os"
+	"path/filepath"
 )
 
 var (
-	Cmd = &cobra.Command{
+	policyPath string
+	Cmd        = &cobra.Command{
 		Use:           "casbin",
 		Short:         "Casbin policy migration",
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Run: func(_ *cobra.Command, _ []string) {
-			var (
-				srv *casbin.Casbin
-				c   func()
-				e   error
-			)
-			defer func() {
-				if c != nil {
-					c()
-				}
-			}()
-			cmd.Slave.Executor(func(ctx context.Context) error {
-				initial, _ := entrypoint.CtxExtractInitial(ctx)
-				srv, c, e = casbin.Build(ctx, initial, cmd.Observer)
-				if e != nil {
-					return e
-				}
-				return nil
-			}, func(ctx context.Context) error {
-				e := srv.ImportPolicy(cmd.Slave.WorkDir() + "/assets/policy.conf")
-				if e != nil {
-					srv.L().Error("import policy failed: %v", logger.Args(e.Error()))
-					os.Exit(1)
-				}
-				return nil
-			})
+			execute(importPolicy)
 		},
 	}
 )
+
+// execute builds the casbin service and runs the command with it
+func execute(fn func(srv *casbin.Casbin)) {
+	var (
+		srv *casbin.Casbin
+		c   func()
+		e   error
+	)
+	defer func() {
+		if c != nil {
+			c()
+		}
+	}()
+	cmd.Slave.Executor(func(ctx context.Context) error {
+		initial, _ := entrypoint.CtxExtractInitial(ctx)
+		srv, c, e = casbin.Build(ctx, initial, cmd.Observer)
+		if e != nil {
+			return e
+		}
+		return nil
+	}, func(ctx context.Context) error {
+		fn(srv)
+		return nil
+	})
+}
+
+func importPolicy(srv *casbin.Casbin) {
+	e := srv.ImportPolicy(policyFilePath())
+	if e != nil {
+		srv.L().Error("import policy failed: %v", logger.Args(e.Error()))
+		os.Exit(1)
+	}
+}
+
+// policyFilePath returns the policy file path, relative paths are resolved against the work directory
+func policyFilePath() string {
+	if filepath.IsAbs(policyPath) {
+		return policyPath
+	}
+	return filepath.Join(cmd.Slave.WorkDir(), policyPath)
+}
+
+func init() {
+	// pflags
+	Cmd.PersistentFlags().StringVar(&policyPath, "policy", "assets/policy.conf", "policy file path")
+	Cmd.AddCommand(lintCmd, importCmd, diffCmd, exportCmd)
+}
@@ -0,0 +1,139 @@
+package casbin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/alexeyco/simpletable"
+	"github.com/fatih/color"
+	"github.com/paysuper/paysuper-management-api/internal/casbin"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+const (
+	formatTable = "table"
+	formatJson  = "json"
+)
+
+var (
+	dryRun     bool
+	format     string
+	exportPath string
+
+	importCmd = &cobra.Command{
+		Use:           "import",
+		Short:         "Import policy file to casbin server",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(_ *cobra.Command, _ []string) {
+			execute(func(srv *casbin.Casbin) {
+				if dryRun {
+					diffPolicy(srv)
+					return
+				}
+				importPolicy(srv)
+			})
+		},
+	}
+	diffCmd = &cobra.Command{
+		Use:           "diff",
+		Short:         "Show changes the policy file import makes to casbin server policy",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(_ *cobra.Command, _ []string) {
+			execute(diffPolicy)
+		},
+	}
+	exportCmd = &cobra.Command{
+		Use:           "export",
+		Short:         "Export casbin server policy to the file",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(_ *cobra.Command, _ []string) {
+			execute(func(srv *casbin.Casbin) {
+				if e := srv.ExportPolicy(exportPath); e != nil {
+					srv.L().Error("export policy failed: %v", logger.Args(e.Error()))
+					os.Exit(1)
+				}
+				srv.L().Info("casbin policy exported to %s", logger.Args(exportPath))
+			})
+		},
+	}
+)
+
+func diffPolicy(srv *casbin.Casbin) {
+	diff, e := srv.DiffPolicy(policyFilePath())
+	if e != nil {
+		srv.L().Error("diff policy failed: %v", logger.Args(e.Error()))
+		os.Exit(1)
+	}
+
+	if format == formatJson {
+		b, e := json.MarshalIndent(diff, "", "  ")
+		if e != nil {
+			srv.L().Error("diff policy failed: %v", logger.Args(e.Error()))
+			os.Exit(1)
+		}
+		fmt.Println(string(b))
+		return
+	}
+
+	printPolicyDiff(diff)
+}
+
+func printPolicyDiff(diff *casbin.PolicyDiff) {
+	if diff.Empty() {
+		fmt.Println(color.GreenString("casbin policy is up to date"))
+		printUserGrants(diff)
+		return
+	}
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "Change"},
+			{Align: simpletable.AlignCenter, Text: "Kind"},
+			{Align: simpletable.AlignCenter, Text: "Rule"},
+		},
+	}
+
+	add := func(change string, rules []casbin.Rule) {
+		for _, rule := range rules {
+			table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+				{Align: simpletable.AlignLeft, Text: change},
+				{Align: simpletable.AlignLeft, Text: ruleKind(rule)},
+				{Align: simpletable.AlignLeft, Text: strings.Join(rule[1:], ", ")},
+			})
+		}
+	}
+	add(color.GreenString("+ added"), diff.Added)
+	add(color.RedString("- removed"), diff.Removed)
+
+	table.SetStyle(simpletable.StyleMarkdown)
+	fmt.Println(table.String())
+	fmt.Printf("\n%d added, %d removed\n", len(diff.Added), len(diff.Removed))
+	printUserGrants(diff)
+}
+
+func printUserGrants(diff *casbin.PolicyDiff) {
+	if len(diff.UserGrants) > 0 {
+		fmt.Printf("%d user role grants aren't managed by the policy file\n", len(diff.UserGrants))
+	}
+}
+
+func ruleKind(rule casbin.Rule) string {
+	if rule.Type() == casbin.RuleTypeGrouping {
+		return "role grant"
+	}
+	return "policy"
+}
+
+func init() {
+	// pflags
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show changes without applying them")
+	importCmd.Flags().StringVar(&format, "format", formatTable, "dry run output format: table or json")
+	diffCmd.Flags().StringVar(&format, "format", formatTable, "output format: table or json")
+	exportCmd.Flags().StringVarP(&exportPath, "output", "o", "policy.export.conf", "export file path")
+}
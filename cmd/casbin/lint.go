@@ -0,0 +1,132 @@
+package casbin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alexeyco/simpletable"
+	"github.com/fatih/color"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/cmd"
+	"github.com/paysuper/paysuper-management-api/internal/casbin"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/handlers"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+var (
+	lintCmd = &cobra.Command{
+		Use:           "lint",
+		Short:         "Check casbin policy against the API routes",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			routes, c, e := buildRoutes(context.Background())
+			if e != nil {
+				return fmt.Errorf("build routes failed: %v", e)
+			}
+			defer c()
+
+			rules, e := casbin.ReadPolicyFile(policyFilePath())
+			if e != nil {
+				return fmt.Errorf("read policy failed: %v", e)
+			}
+			report := casbin.Lint(routes, rules)
+			printLintReport(report)
+			if !report.Ok() {
+				return errors.New("casbin policy doesn't match the routes")
+			}
+			return nil
+		},
+	}
+)
+
+// buildRoutes registers the API routes with the test providers,
+// neither the storages nor the services are required to list them
+func buildRoutes(ctx context.Context) ([]*echo.Route, func(), error) {
+	// the test providers read the work directory with the templates and the static files from the env
+	if e := os.Setenv("WD", cmd.Slave.WorkDir()); e != nil {
+		return nil, nil, e
+	}
+
+	settings := test.DefaultSettings()
+	set, c, e := test.BuildTestSet(ctx, settings, common.Services{}, nil)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	routes := handlers.ProviderRouteHandlers(set.Initial, set.HandlerSet, set.GlobalConfig)
+	d, c2, e := test.BuildDispatcher(ctx, settings, common.Services{}, routes, set.ApprovalStore, nil)
+	if e != nil {
+		c()
+		return nil, nil, e
+	}
+
+	cleanup := func() {
+		c2()
+		c()
+	}
+	echoHttp := echo.New()
+	if e := d.Dispatch(echoHttp); e != nil {
+		cleanup()
+		return nil, nil, e
+	}
+	return echoHttp.Routes(), cleanup, nil
+}
+
+func printLintReport(report *casbin.LintReport) {
+	if report.Ok() && len(report.ParamMismatches) == 0 {
+		fmt.Println(color.GreenString("casbin policy is ok"))
+		return
+	}
+
+	var rows [][]string
+
+	for _, route := range report.UncoveredRoutes {
+		rows = append(rows, []string{"uncovered route", route.Method + " " + route.Path, "no policy allows the route"})
+	}
+	for _, rule := range report.OrphanPolicies {
+		rows = append(rows, []string{"orphan policy", rule.String(), "no route matches the policy"})
+	}
+	for _, rule := range report.Duplicates {
+		rows = append(rows, []string{"duplicate", rule.String(), "rule is listed more than once"})
+	}
+	for _, cycle := range report.Cycles {
+		rows = append(rows, []string{"role cycle", strings.Join(cycle, " -> "), "roles inherit each other"})
+	}
+
+	problems := len(rows)
+
+	for _, m := range report.ParamMismatches {
+		rows = append(rows, []string{"parameter mismatch (warning)", m.Policy.String(), "route is " + m.Route.Method + " " + m.Route.Path})
+	}
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "Problem"},
+			{Align: simpletable.AlignCenter, Text: "Rule"},
+			{Align: simpletable.AlignCenter, Text: "Details"},
+		},
+	}
+
+	for _, row := range rows {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: row[0]},
+			{Align: simpletable.AlignLeft, Text: row[1]},
+			{Align: simpletable.AlignLeft, Text: row[2]},
+		})
+	}
+
+	table.SetStyle(simpletable.StyleMarkdown)
+	fmt.Println(table.String())
+	if problems > 0 {
+		fmt.Println(color.RedString("\n%d problems found", problems))
+	}
+	if len(report.ParamMismatches) > 0 {
+		fmt.Println(color.YellowString("%d warnings found", len(report.ParamMismatches)))
+	}
+}
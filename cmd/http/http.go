@@ -52,7 +52,11 @@ var (
 				return nil
 			}, func(ctx context.Context) error {
 				if casbinFlag {
-					e := sCabin.ImportPolicy(cmd.Slave.WorkDir() + "/assets/policy.conf")
+					policy := cmd.Slave.WorkDir() + "/assets/policy.conf"
+					if diff, e := sCabin.DiffPolicy(policy); e == nil {
+						sCabin.L().Info("casbin policy changes: %d added, %d removed", logger.Args(len(diff.Added), len(diff.Removed)))
+					}
+					e := sCabin.ImportPolicy(policy)
 					if e != nil {
 						sCabin.L().Error("import policy failed: %v", logger.Args(e.Error()))
 						return e
@@ -78,5 +82,6 @@ var (
 func init() {
 	// pflags
 	Cmd.PersistentFlags().StringP(http.UnmarshalKeyBind, "b", ":0000", "bind address")
+	Cmd.PersistentFlags().StringP(http.UnmarshalKeyMetricsBind, "m", "", "metrics bind address")
 	Cmd.PersistentFlags().BoolVar(&casbinFlag, "casbin", false, "apply policy to casbin server")
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/paysuper/paysuper-proto/go/casbinpb"
 	"github.com/pkg/errors"
 	"io/ioutil"
+	"strings"
 )
 
 type Casbin struct {
@@ -33,6 +34,36 @@ func (c *Casbin) ImportPolicy(path string) error {
 	return e
 }
 
+// DiffPolicy compares the policy file with the live policy of the casbin service
+func (c *Casbin) DiffPolicy(path string) (*PolicyDiff, error) {
+	desired, e := ReadPolicyFile(path)
+	if e != nil {
+		return nil, e
+	}
+	current, e := FetchPolicy(c.ctx, c.appSet.CasbinService)
+	if e != nil {
+		return nil, e
+	}
+	return NewPolicyDiff(current, desired), nil
+}
+
+// ExportPolicy writes the live policy of the casbin service to the file in the policy file format
+func (c *Casbin) ExportPolicy(path string) error {
+	rules, e := FetchPolicy(c.ctx, c.appSet.CasbinService)
+	if e != nil {
+		return e
+	}
+	var b strings.Builder
+	for _, rule := range rules {
+		b.WriteString(rule.String())
+		b.WriteString("\n")
+	}
+	if e = ioutil.WriteFile(path, []byte(b.String()), 0644); e != nil {
+		return errors.WithMessage(e, Prefix)
+	}
+	return nil
+}
+
 // Config
 type Config struct {
 	Debug   bool `fallback:"shared.debug"`
@@ -0,0 +1,156 @@
+package casbin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	casbinEnforcer "github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/paysuper/paysuper-proto/go/casbinpb"
+	"github.com/pkg/errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LocalEnforcer evaluates the policy in process. The policy is built from the policy file
+// and the rules of the casbin service, so the role grants of the users are known too.
+type LocalEnforcer struct {
+	mx         sync.RWMutex
+	enforcer   *casbinEnforcer.Enforcer
+	hash       string
+	modelPath  string
+	policyPath string
+	srv        casbinpb.CasbinService
+	log        logger.Logger
+}
+
+// NewLocalEnforcer
+func NewLocalEnforcer(modelPath, policyPath string, srv casbinpb.CasbinService, log logger.Logger) *LocalEnforcer {
+	return &LocalEnforcer{
+		modelPath:  modelPath,
+		policyPath: policyPath,
+		srv:        srv,
+		log:        log,
+	}
+}
+
+// Enforce returns true if the subject is allowed to call the method of the path
+func (e *LocalEnforcer) Enforce(sub, path, method string) (bool, error) {
+	e.mx.RLock()
+	enforcer := e.enforcer
+	e.mx.RUnlock()
+
+	if enforcer == nil {
+		return false, errors.New(Prefix + ": policy isn't loaded")
+	}
+
+	return enforcer.Enforce(sub, path, method)
+}
+
+// Reload rebuilds the enforcer if the policy file or the remote policy changed. The current
+// enforcer is kept if the policy can't be loaded, the remote policy is optional for the first load only.
+func (e *LocalEnforcer) Reload(ctx context.Context) error {
+	rules, e1 := ReadPolicyFile(e.policyPath)
+	if e1 != nil {
+		return e1
+	}
+
+	remote, e1 := FetchPolicy(ctx, e.srv)
+	if e1 != nil {
+		e.mx.RLock()
+		loaded := e.enforcer != nil
+		e.mx.RUnlock()
+
+		if loaded {
+			return e1
+		}
+
+		e.log.Error("remote casbin policy isn't loaded, the policy file is used only", logger.PairArgs("err", e1.Error()))
+	}
+
+	rules = append(rules, remote...)
+	hash := rulesHash(rules)
+
+	e.mx.RLock()
+	unchanged := e.enforcer != nil && e.hash == hash
+	e.mx.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	m, e1 := model.NewModelFromFile(e.modelPath)
+	if e1 != nil {
+		return errors.WithMessage(e1, Prefix)
+	}
+	enforcer, e1 := casbinEnforcer.NewEnforcer(m)
+	if e1 != nil {
+		return errors.WithMessage(e1, Prefix)
+	}
+
+	for _, rule := range rules {
+		if len(rule) < 2 {
+			continue
+		}
+
+		switch rule.Type() {
+		case RuleTypePolicy:
+			_, e1 = enforcer.AddPolicy(rule[1:])
+		case RuleTypeGrouping:
+			_, e1 = enforcer.AddGroupingPolicy(rule[1:])
+		}
+
+		if e1 != nil {
+			return errors.WithMessage(e1, Prefix)
+		}
+	}
+
+	e.mx.Lock()
+	e.enforcer = enforcer
+	e.hash = hash
+	e.mx.Unlock()
+
+	e.log.Info("casbin policy loaded", logger.PairArgs("rules", len(rules)))
+	return nil
+}
+
+// Watch reloads the policy every interval until the context is done
+func (e *LocalEnforcer) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Reload(ctx); err != nil {
+				e.log.Error("casbin policy reload failed", logger.PairArgs("err", err.Error()))
+			}
+		}
+	}
+}
+
+func rulesHash(rules []Rule) string {
+	lines := make([]string, 0, len(rules))
+
+	for _, rule := range rules {
+		lines = append(lines, rule.String())
+	}
+
+	sort.Strings(lines)
+	h := sha256.New()
+
+	for _, line := range lines {
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,211 @@
+package casbin
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"sort"
+	"strings"
+)
+
+// LintReport lists the problems of the policy found against the routes of the API
+type LintReport struct {
+	// System routes without any policy, these routes are denied for everybody
+	UncoveredRoutes []*echo.Route
+	// Policies which match no route
+	OrphanPolicies []Rule
+	// Policies which match the route only if the path parameter names are ignored. keyMatch2 accepts any
+	// value for the parameter, so the route is allowed and the mismatches are the warnings.
+	ParamMismatches []ParamMismatch
+	// Rules which are listed more than once
+	Duplicates []Rule
+	// Role inheritance cycles, each cycle starts and ends with the same role
+	Cycles [][]string
+}
+
+// ParamMismatch is the policy path which differs from the route path by the parameter names only
+type ParamMismatch struct {
+	Policy Rule
+	Route  *echo.Route
+}
+
+// Ok returns true if no problems are found, the warnings are allowed
+func (r *LintReport) Ok() bool {
+	return len(r.UncoveredRoutes) == 0 && len(r.OrphanPolicies) == 0 && len(r.Duplicates) == 0 && len(r.Cycles) == 0
+}
+
+// Lint checks the policy rules against the routes of the API. Only the routes of the groups
+// protected by casbin are taken into account.
+func Lint(routes []*echo.Route, rules []Rule) *LintReport {
+	report := &LintReport{}
+	var protected []*echo.Route
+
+	for _, route := range routes {
+		if isProtectedPath(route.Path) {
+			protected = append(protected, route)
+		}
+	}
+
+	covered := make(map[*echo.Route]bool)
+
+	for _, rule := range policyRules(rules) {
+		path, method := rule[2], rule[3]
+		matched := false
+		var mismatch *echo.Route
+
+		for _, route := range protected {
+			if method != "*" && method != route.Method {
+				continue
+			}
+
+			if route.Path == path {
+				covered[route] = true
+				matched = true
+			} else if normalizePath(route.Path) == normalizePath(path) {
+				covered[route] = true
+				mismatch = route
+			}
+		}
+
+		switch {
+		case matched:
+		case mismatch != nil:
+			report.ParamMismatches = append(report.ParamMismatches, ParamMismatch{Policy: rule, Route: mismatch})
+		default:
+			report.OrphanPolicies = append(report.OrphanPolicies, rule)
+		}
+	}
+
+	for _, route := range protected {
+		if !covered[route] && strings.HasPrefix(route.Path, common.SystemUserGroupPath) {
+			report.UncoveredRoutes = append(report.UncoveredRoutes, route)
+		}
+	}
+
+	sort.Slice(report.UncoveredRoutes, func(i, j int) bool {
+		a, b := report.UncoveredRoutes[i], report.UncoveredRoutes[j]
+		return a.Path < b.Path || (a.Path == b.Path && a.Method < b.Method)
+	})
+
+	report.Duplicates = duplicateRules(rules)
+	report.Cycles = roleCycles(rules)
+
+	return report
+}
+
+func isProtectedPath(path string) bool {
+	for _, prefix := range []string{common.SystemUserGroupPath, common.AuthUserGroupPath} {
+		// echo adds the catch-all routes of the group to run its middlewares for the unknown paths
+		if path == prefix || path == prefix+"/*" {
+			return false
+		}
+
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// policyRules returns the p rules with the subject, path and method
+func policyRules(rules []Rule) []Rule {
+	var policies []Rule
+
+	for _, rule := range rules {
+		if rule.Type() == RuleTypePolicy && len(rule) >= 4 {
+			policies = append(policies, rule)
+		}
+	}
+
+	return policies
+}
+
+// normalizePath replaces the names of the path parameters, keyMatch2 accepts any value for them
+func normalizePath(path string) string {
+	parts := strings.Split(path, "/")
+
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = ":"
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func duplicateRules(rules []Rule) []Rule {
+	var duplicates []Rule
+	seen := make(map[string]int)
+
+	for _, rule := range rules {
+		key := rule.String()
+		seen[key]++
+
+		if seen[key] == 2 {
+			duplicates = append(duplicates, rule)
+		}
+	}
+
+	return duplicates
+}
+
+// roleCycles finds the cycles in the g rules, the rule g,a,b means the role a inherits the role b
+func roleCycles(rules []Rule) [][]string {
+	graph := make(map[string][]string)
+
+	for _, rule := range rules {
+		if rule.Type() == RuleTypeGrouping && len(rule) >= 3 {
+			graph[rule[1]] = append(graph[rule[1]], rule[2])
+		}
+	}
+
+	nodes := make([]string, 0, len(graph))
+
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+
+	sort.Strings(nodes)
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+
+	var cycles [][]string
+	state := make(map[string]int)
+	var stack []string
+	var visit func(node string)
+
+	visit = func(node string) {
+		state[node] = inProgress
+		stack = append(stack, node)
+
+		for _, next := range graph[node] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case inProgress:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						cycle := append(append([]string{}, stack[i:]...), next)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[node] = done
+	}
+
+	for _, node := range nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,124 @@
+package casbin
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestLint_Ok(t *testing.T) {
+	routes := []*echo.Route{
+		{Method: http.MethodGet, Path: "/system/api/v1/order/:order_id"},
+		{Method: http.MethodGet, Path: "/admin/api/v1/order"},
+		{Method: http.MethodGet, Path: "/api/v1/order/:id"},
+		{Method: http.MethodGet, Path: "/system/api/v1/*"},
+	}
+	rules := []Rule{
+		{"p", "systemGetOrder", "/system/api/v1/order/:order_id", "GET"},
+		{"p", "merchantListOrders", "/admin/api/v1/order", "*"},
+		{"g", "system_admin", "systemGetOrder"},
+	}
+
+	report := Lint(routes, rules)
+	assert.True(t, report.Ok())
+	assert.Empty(t, report.UncoveredRoutes)
+	assert.Empty(t, report.OrphanPolicies)
+	assert.Empty(t, report.ParamMismatches)
+}
+
+func TestLint_Problems(t *testing.T) {
+	routes := []*echo.Route{
+		{Method: http.MethodGet, Path: "/system/api/v1/order/:order_id"},
+		{Method: http.MethodGet, Path: "/system/api/v1/audit"},
+		{Method: http.MethodDelete, Path: "/admin/api/v1/user/sessions/:session_id"},
+		{Method: http.MethodGet, Path: "/admin/api/v1/user/sessions"},
+	}
+	rules := []Rule{
+		{"p", "systemGetOrder", "/system/api/v1/order/:order_id", "GET"},
+		{"p", "systemGetOrder", "/system/api/v1/order/:order_id", "GET"},
+		{"p", "merchantRevokeSession", "/admin/api/v1/user/sessions/:id", "DELETE"},
+		{"p", "merchantRemoved", "/admin/api/v1/removed", "GET"},
+	}
+
+	report := Lint(routes, rules)
+	assert.False(t, report.Ok())
+
+	// the routes of the merchant group without the policy aren't reported, the group has the default rules
+	assert.Len(t, report.UncoveredRoutes, 1)
+	assert.Equal(t, "/system/api/v1/audit", report.UncoveredRoutes[0].Path)
+
+	assert.Equal(t, []Rule{{"p", "merchantRemoved", "/admin/api/v1/removed", "GET"}}, report.OrphanPolicies)
+	assert.Equal(t, []Rule{{"p", "systemGetOrder", "/system/api/v1/order/:order_id", "GET"}}, report.Duplicates)
+
+	assert.Len(t, report.ParamMismatches, 1)
+	assert.Equal(t, "merchantRevokeSession", report.ParamMismatches[0].Policy[1])
+	assert.Equal(t, "/admin/api/v1/user/sessions/:session_id", report.ParamMismatches[0].Route.Path)
+}
+
+func TestLint_ParamMismatch_Warning(t *testing.T) {
+	routes := []*echo.Route{
+		{Method: http.MethodGet, Path: "/system/api/v1/refunds/bulk/:job_id"},
+	}
+	rules := []Rule{
+		{"p", "systemGetBulkRefund", "/system/api/v1/refunds/bulk/:id", "GET"},
+	}
+
+	report := Lint(routes, rules)
+	assert.True(t, report.Ok())
+	assert.Len(t, report.ParamMismatches, 1)
+	assert.Empty(t, report.UncoveredRoutes)
+}
+
+func TestDuplicateRules(t *testing.T) {
+	rules := []Rule{
+		{"g", "merchant_owner", "merchantListOrders"},
+		{"g", "merchant_owner", "merchantListOrders"},
+		{"g", "merchant_owner", "merchantListOrders"},
+		{"g", "merchant_view_only", "merchantListOrders"},
+	}
+
+	assert.Equal(t, []Rule{{"g", "merchant_owner", "merchantListOrders"}}, duplicateRules(rules))
+	assert.Empty(t, duplicateRules(rules[2:]))
+}
+
+func TestRoleCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  []Rule
+		cycles [][]string
+	}{
+		{
+			name: "no cycles",
+			rules: []Rule{
+				{"g", "merchant_owner", "merchant_developer"},
+				{"g", "merchant_developer", "merchantListOrders"},
+				{"g", "merchant_owner", "merchantListOrders"},
+			},
+		},
+		{
+			name: "self inheritance",
+			rules: []Rule{
+				{"g", "merchant_owner", "merchant_owner"},
+			},
+			cycles: [][]string{{"merchant_owner", "merchant_owner"}},
+		},
+		{
+			name: "cycle of three roles",
+			rules: []Rule{
+				{"p", "merchantListOrders", "/admin/api/v1/order", "GET"},
+				{"g", "a", "b"},
+				{"g", "b", "c"},
+				{"g", "c", "a"},
+				{"g", "c", "merchantListOrders"},
+			},
+			cycles: [][]string{{"a", "b", "c", "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.cycles, roleCycles(tt.rules))
+		})
+	}
+}
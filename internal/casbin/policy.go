@@ -0,0 +1,158 @@
+package casbin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"github.com/paysuper/paysuper-proto/go/casbinpb"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	RuleTypePolicy   = "p"
+	RuleTypeGrouping = "g"
+)
+
+// Rule is the policy line, the rule type followed by the values
+type Rule []string
+
+// Type returns p for the policy and g for the role grant
+func (r Rule) Type() string {
+	if len(r) == 0 {
+		return ""
+	}
+	return r[0]
+}
+
+// String returns the rule in the policy file format
+func (r Rule) String() string {
+	return strings.Join(r, ",")
+}
+
+// ParsePolicy reads the rules in the casbin CSV format, empty lines and comments are skipped
+func ParsePolicy(data []byte) []Rule {
+	var rules []Rule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := Rule(strings.Split(line, ","))
+
+		for i := range rule {
+			rule[i] = strings.TrimSpace(rule[i])
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// ReadPolicyFile
+func ReadPolicyFile(path string) ([]Rule, error) {
+	b, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, errors.WithMessage(e, Prefix)
+	}
+	return ParsePolicy(b), nil
+}
+
+// FetchPolicy returns the policy and the role grants stored by the casbin service
+func FetchPolicy(ctx context.Context, srv casbinpb.CasbinService) ([]Rule, error) {
+	policy, e := srv.GetPolicy(ctx, &casbinpb.Empty{})
+	if e != nil {
+		return nil, errors.WithMessage(e, Prefix)
+	}
+	grouping, e := srv.GetGroupingPolicy(ctx, &casbinpb.Empty{})
+	if e != nil {
+		return nil, errors.WithMessage(e, Prefix)
+	}
+
+	var rules []Rule
+
+	for _, d := range policy.D2 {
+		rules = append(rules, append(Rule{RuleTypePolicy}, d.D1...))
+	}
+	for _, d := range grouping.D2 {
+		rules = append(rules, append(Rule{RuleTypeGrouping}, d.D1...))
+	}
+
+	return rules, nil
+}
+
+// PolicyDiff lists the rules the import adds to the live policy and the live rules missing in the policy file.
+// UserGrants are the live role grants of the subjects the policy file doesn't contain, i.e. the roles
+// given to the users at runtime, they are reported separately and never counted as removed.
+type PolicyDiff struct {
+	Added      []Rule `json:"added"`
+	Removed    []Rule `json:"removed"`
+	UserGrants []Rule `json:"user_grants"`
+}
+
+// NewPolicyDiff compares the current and desired rules, the order of the rules is kept
+func NewPolicyDiff(current, desired []Rule) *PolicyDiff {
+	diff := &PolicyDiff{Added: []Rule{}, Removed: []Rule{}, UserGrants: []Rule{}}
+	currentSet := ruleSet(current)
+	desiredSet := ruleSet(desired)
+	managed := managedSubjects(desired)
+
+	for _, rule := range uniqueRules(desired) {
+		if !currentSet[rule.String()] {
+			diff.Added = append(diff.Added, rule)
+		}
+	}
+	for _, rule := range uniqueRules(current) {
+		if rule.Type() == RuleTypeGrouping && len(rule) > 1 && !managed[rule[1]] {
+			diff.UserGrants = append(diff.UserGrants, rule)
+			continue
+		}
+		if !desiredSet[rule.String()] {
+			diff.Removed = append(diff.Removed, rule)
+		}
+	}
+
+	return diff
+}
+
+// Empty returns true if the policies are equal
+func (d *PolicyDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// managedSubjects returns the permissions and the roles of the policy file
+func managedSubjects(rules []Rule) map[string]bool {
+	subjects := make(map[string]bool)
+	for _, rule := range rules {
+		for _, value := range rule[1:] {
+			subjects[value] = true
+		}
+	}
+	return subjects
+}
+
+func ruleSet(rules []Rule) map[string]bool {
+	set := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		set[rule.String()] = true
+	}
+	return set
+}
+
+func uniqueRules(rules []Rule) []Rule {
+	var unique []Rule
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if !seen[rule.String()] {
+			seen[rule.String()] = true
+			unique = append(unique, rule)
+		}
+	}
+	return unique
+}
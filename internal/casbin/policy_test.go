@@ -0,0 +1,53 @@
+package casbin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewPolicyDiff(t *testing.T) {
+	desired := ParsePolicy([]byte(`
+# permissions
+p,merchantListOrders,/admin/api/v1/order,GET
+p,merchantGetOrder,/admin/api/v1/order/:order_id,GET
+g,merchant_owner,merchantListOrders
+g,merchant_owner,merchantGetOrder
+g,merchant_view_only,merchantListOrders
+`))
+	current := []Rule{
+		{"p", "merchantListOrders", "/admin/api/v1/order", "GET"},
+		{"p", "merchantDeleteOrder", "/admin/api/v1/order/:order_id", "DELETE"},
+		{"g", "merchant_owner", "merchantListOrders"},
+		{"g", "merchant_owner", "merchantDeleteOrder"},
+		{"g", "merchant_view_only", "merchantListOrders"},
+		{"g", "5dbac6a9120a810001a8fe41", "merchant_owner"},
+		{"g", "5dbac6a9120a810001a8fe42", "merchant_view_only"},
+	}
+
+	diff := NewPolicyDiff(current, desired)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []Rule{
+		{"p", "merchantGetOrder", "/admin/api/v1/order/:order_id", "GET"},
+		{"g", "merchant_owner", "merchantGetOrder"},
+	}, diff.Added)
+	assert.Equal(t, []Rule{
+		{"p", "merchantDeleteOrder", "/admin/api/v1/order/:order_id", "DELETE"},
+		{"g", "merchant_owner", "merchantDeleteOrder"},
+	}, diff.Removed)
+	assert.Equal(t, []Rule{
+		{"g", "5dbac6a9120a810001a8fe41", "merchant_owner"},
+		{"g", "5dbac6a9120a810001a8fe42", "merchant_view_only"},
+	}, diff.UserGrants)
+}
+
+func TestNewPolicyDiff_UserGrantsOnly_Empty(t *testing.T) {
+	desired := []Rule{
+		{"p", "merchantListOrders", "/admin/api/v1/order", "GET"},
+		{"g", "merchant_owner", "merchantListOrders"},
+	}
+	current := append(desired, Rule{"g", "5dbac6a9120a810001a8fe41", "merchant_owner"})
+
+	diff := NewPolicyDiff(current, desired)
+	assert.True(t, diff.Empty())
+	assert.Len(t, diff.UserGrants, 1)
+}
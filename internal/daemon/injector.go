@@ -40,3 +40,17 @@ func BuildMicro(ctx context.Context, initial config.Initial, observer invoker.Ob
 			wire.Struct(new(provider.AwareSet), "*")),
 	)
 }
+
+// BuildDispatcher
+func BuildDispatcher(ctx context.Context, initial config.Initial, observer invoker.Observer) (*dispatcher.Dispatcher, func(), error) {
+	panic(
+		wire.Build(
+			provider.Set,
+			wire.Struct(new(provider.AwareSet), "*"),
+			micro.WireSet,
+			validators.WireSet,
+			dispatcher.WireSet,
+			handlers.ProviderHandlers,
+		),
+	)
+}
@@ -143,7 +143,7 @@ func BuildHTTP(ctx context.Context, initial config.Initial, observer invoker.Obs
 		cleanup()
 		return nil, nil, err
 	}
-	commonHandlers, cleanup13, err := handlers.ProviderHandlers(initial, services, validate, awareSet, commonConfig)
+	database, cleanup13, err := dispatcher.ProviderMongo(commonConfig)
 	if err != nil {
 		cleanup12()
 		cleanup11()
@@ -159,14 +159,180 @@ func BuildHTTP(ctx context.Context, initial config.Initial, observer invoker.Obs
 		cleanup()
 		return nil, nil, err
 	}
+	apiKeyStore, err := dispatcher.ProviderApiKeyStore(database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	authCache := dispatcher.ProviderAuthCache(commonConfig)
+	sessionStore, err := dispatcher.ProviderSessionStore(commonConfig, database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	auditStore, err := dispatcher.ProviderAuditStore(commonConfig, microMicro, database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	approvalStore, err := dispatcher.ProviderApprovalStore(database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	bulkRefundStore, err := dispatcher.ProviderBulkRefundStore(commonConfig, database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	refundStore, err := dispatcher.ProviderRefundStore(database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	backgroundTasks := dispatcher.ProviderBackgroundTasks()
+	commonHandlers, cleanup14, err := handlers.ProviderHandlers(initial, services, validate, awareSet, commonConfig, apiKeyStore, authCache, sessionStore, auditStore, approvalStore, bulkRefundStore, refundStore, backgroundTasks)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	jwtVerifier := dispatcher.ProviderJwtVerifier(commonConfig)
+	idempotencyStore, err := dispatcher.ProviderIdempotencyStore(commonConfig, database)
+	if err != nil {
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	nonceStore, err := dispatcher.ProviderNonceStore(database)
+	if err != nil {
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	appSet := dispatcher.AppSet{
-		Handlers:    commonHandlers,
-		Services:    services,
-		JwtVerifier: jwtVerifier,
+		Handlers:         commonHandlers,
+		Services:         services,
+		JwtVerifier:      jwtVerifier,
+		IdempotencyStore: idempotencyStore,
+		NonceStore:       nonceStore,
+		ApiKeyStore:      apiKeyStore,
+		AuthCache:        authCache,
+		SessionStore:     sessionStore,
+		AuditStore:       auditStore,
+		ApprovalStore:    approvalStore,
+		BackgroundTasks:  backgroundTasks,
 	}
-	dispatcherConfig, cleanup14, err := dispatcher.ProviderCfg(configurator)
+	dispatcherConfig, cleanup15, err := dispatcher.ProviderCfg(configurator)
 	if err != nil {
+		cleanup14()
 		cleanup13()
 		cleanup12()
 		cleanup11()
@@ -182,8 +348,9 @@ func BuildHTTP(ctx context.Context, initial config.Initial, observer invoker.Obs
 		cleanup()
 		return nil, nil, err
 	}
-	dispatcherDispatcher, cleanup15, err := dispatcher.ProviderDispatcher(ctx, awareSet, appSet, dispatcherConfig, commonConfig, microMicro)
+	dispatcherDispatcher, cleanup16, err := dispatcher.ProviderDispatcher(ctx, awareSet, appSet, dispatcherConfig, commonConfig, microMicro)
 	if err != nil {
+		cleanup15()
 		cleanup14()
 		cleanup13()
 		cleanup12()
@@ -200,8 +367,9 @@ func BuildHTTP(ctx context.Context, initial config.Initial, observer invoker.Obs
 		cleanup()
 		return nil, nil, err
 	}
-	httpConfig, cleanup16, err := http.Cfg(configurator)
+	httpConfig, cleanup17, err := http.Cfg(configurator)
 	if err != nil {
+		cleanup16()
 		cleanup15()
 		cleanup14()
 		cleanup13()
@@ -219,8 +387,9 @@ func BuildHTTP(ctx context.Context, initial config.Initial, observer invoker.Obs
 		cleanup()
 		return nil, nil, err
 	}
-	httpHTTP, cleanup17, err := http.Provider(ctx, awareSet, dispatcherDispatcher, httpConfig)
+	httpHTTP, cleanup18, err := http.Provider(ctx, awareSet, dispatcherDispatcher, httpConfig)
 	if err != nil {
+		cleanup17()
 		cleanup16()
 		cleanup15()
 		cleanup14()
@@ -240,6 +409,7 @@ func BuildHTTP(ctx context.Context, initial config.Initial, observer invoker.Obs
 		return nil, nil, err
 	}
 	return httpHTTP, func() {
+		cleanup18()
 		cleanup17()
 		cleanup16()
 		cleanup15()
@@ -350,3 +520,368 @@ func BuildMicro(ctx context.Context, initial config.Initial, observer invoker.Ob
 		cleanup()
 	}, nil
 }
+
+func BuildDispatcher(ctx context.Context, initial config.Initial, observer invoker.Observer) (*dispatcher.Dispatcher, func(), error) {
+	configurator, cleanup, err := config.Provider(initial, observer)
+	if err != nil {
+		return nil, nil, err
+	}
+	loggerConfig, cleanup2, err := logger.ProviderCfg(configurator)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	zap, cleanup3, err := logger.Provider(ctx, loggerConfig)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	metricConfig, cleanup4, err := metric.ProviderCfg(configurator)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	scope, cleanup5, err := metric.Provider(ctx, zap, metricConfig)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	tracingConfig, cleanup6, err := tracing.ProviderCfg(configurator)
+	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	tracer, cleanup7, err := tracing.Provider(ctx, tracingConfig, zap)
+	if err != nil {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	awareSet := provider.AwareSet{
+		Logger: zap,
+		Metric: scope,
+		Tracer: tracer,
+	}
+	microConfig, cleanup8, err := micro.Cfg(configurator)
+	if err != nil {
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	microMicro, cleanup9, err := micro.Provider(ctx, awareSet, microConfig)
+	if err != nil {
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	services := dispatcher.ProviderServices(microMicro, microConfig)
+	validatorSet, cleanup10, err := validators.Provider(services, awareSet)
+	if err != nil {
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	validate, cleanup11, err := dispatcher.ProviderValidators(validatorSet)
+	if err != nil {
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	commonConfig, cleanup12, err := dispatcher.ProviderGlobalCfg(configurator)
+	if err != nil {
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	database, cleanup13, err := dispatcher.ProviderMongo(commonConfig)
+	if err != nil {
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	apiKeyStore, err := dispatcher.ProviderApiKeyStore(database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	authCache := dispatcher.ProviderAuthCache(commonConfig)
+	sessionStore, err := dispatcher.ProviderSessionStore(commonConfig, database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	auditStore, err := dispatcher.ProviderAuditStore(commonConfig, microMicro, database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	approvalStore, err := dispatcher.ProviderApprovalStore(database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	bulkRefundStore, err := dispatcher.ProviderBulkRefundStore(commonConfig, database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	refundStore, err := dispatcher.ProviderRefundStore(database)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	backgroundTasks := dispatcher.ProviderBackgroundTasks()
+	commonHandlers, cleanup14, err := handlers.ProviderHandlers(initial, services, validate, awareSet, commonConfig, apiKeyStore, authCache, sessionStore, auditStore, approvalStore, bulkRefundStore, refundStore, backgroundTasks)
+	if err != nil {
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	jwtVerifier := dispatcher.ProviderJwtVerifier(commonConfig)
+	idempotencyStore, err := dispatcher.ProviderIdempotencyStore(commonConfig, database)
+	if err != nil {
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	nonceStore, err := dispatcher.ProviderNonceStore(database)
+	if err != nil {
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	appSet := dispatcher.AppSet{
+		Handlers:         commonHandlers,
+		Services:         services,
+		JwtVerifier:      jwtVerifier,
+		IdempotencyStore: idempotencyStore,
+		NonceStore:       nonceStore,
+		ApiKeyStore:      apiKeyStore,
+		AuthCache:        authCache,
+		SessionStore:     sessionStore,
+		AuditStore:       auditStore,
+		ApprovalStore:    approvalStore,
+		BackgroundTasks:  backgroundTasks,
+	}
+	dispatcherConfig, cleanup15, err := dispatcher.ProviderCfg(configurator)
+	if err != nil {
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	dispatcherDispatcher, cleanup16, err := dispatcher.ProviderDispatcher(ctx, awareSet, appSet, dispatcherConfig, commonConfig, microMicro)
+	if err != nil {
+		cleanup15()
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	return dispatcherDispatcher, func() {
+		cleanup16()
+		cleanup15()
+		cleanup14()
+		cleanup13()
+		cleanup12()
+		cleanup11()
+		cleanup10()
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+	}, nil
+}
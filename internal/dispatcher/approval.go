@@ -0,0 +1,92 @@
+package dispatcher
+
+import (
+	"encoding/json"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"net/http"
+)
+
+const (
+	approvalActionRefund = "OrderRoute.createRefund"
+)
+
+// ApprovalMiddleware saves the requests to the actions configured in ApprovalRoutes as pending approvals. The original
+// handler runs on behalf of the maker when the approval is executed by the approvals route, the policy is enforced
+// again by enforce for the maker, since the request has passed the group policy check with the approver credentials.
+func (d *Dispatcher) ApprovalMiddleware(enforce echo.MiddlewareFunc) echo.MiddlewareFunc {
+	actions := common.ParseApprovalRoutes(d.globalCfg.ApprovalRoutes)
+	routes := &routeNames{}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+
+			if len(actions) == 0 || !common.IsMutatingMethod(req.Method) {
+				return next(ctx)
+			}
+
+			routes.once.Do(func() {
+				routes.index(ctx.Echo())
+			})
+
+			action, _ := routes.name(req.Method, ctx.Path())
+
+			if !actions[action] && !actions[req.Method+" "+ctx.Path()] {
+				return next(ctx)
+			}
+
+			if approval := common.ExtractApproval(req.Context()); approval != nil &&
+				approval.Method == req.Method && approval.Path == req.URL.RequestURI() {
+				maker := *approval.Maker
+				common.SetUserContext(ctx, &maker)
+
+				if enforce != nil {
+					return enforce(next)(ctx)
+				}
+
+				return next(ctx)
+			}
+
+			if !d.approvalRequired(ctx, action) {
+				return next(ctx)
+			}
+
+			approval := common.NewApproval(
+				common.ExtractUserContext(ctx),
+				action,
+				req.Method,
+				ctx.Path(),
+				req.URL.RequestURI(),
+				req.Header.Get(echo.HeaderContentType),
+				common.ExtractRawBodyContext(ctx),
+			)
+			approval.MerchantId = d.auditMerchantId(ctx)
+
+			if err := d.appSet.ApprovalStore.Create(approval); err != nil {
+				common.RequestLogger(ctx, d.L()).Error("approval create failed", logger.PairArgs("err", err.Error()))
+				return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
+			}
+
+			return ctx.JSON(http.StatusAccepted, approval)
+		}
+	}
+}
+
+// approvalRequired checks the conditions of the action, only the refunds above the configured amount need the approval
+func (d *Dispatcher) approvalRequired(ctx echo.Context, action string) bool {
+	if action != approvalActionRefund || d.globalCfg.ApprovalRefundMinAmount <= 0 {
+		return true
+	}
+
+	body := struct {
+		Amount float64 `json:"amount"`
+	}{}
+
+	if err := json.Unmarshal(common.ExtractRawBodyContext(ctx), &body); err != nil {
+		return true
+	}
+
+	return body.Amount >= d.globalCfg.ApprovalRefundMinAmount
+}
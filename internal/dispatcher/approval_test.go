@@ -0,0 +1,95 @@
+package dispatcher_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	approvalTestPath = common.SystemUserGroupPath + "/approval_test"
+)
+
+type ApprovalMiddlewareTestSuite struct {
+	suite.Suite
+	dispatcher *dispatcher.Dispatcher
+	// subjects are the users the policy was enforced for
+	subjects []string
+	allowed  map[string]bool
+}
+
+func Test_ApprovalMiddleware(t *testing.T) {
+	suite.Run(t, new(ApprovalMiddlewareTestSuite))
+}
+
+func (suite *ApprovalMiddlewareTestSuite) SetupTest() {
+	suite.dispatcher = newTestDispatcher()
+	suite.dispatcher.GlobalConfigForTest().ApprovalRoutes = http.MethodPost + " " + approvalTestPath
+	suite.subjects = nil
+	suite.allowed = map[string]bool{"checker": true}
+}
+
+func (suite *ApprovalMiddlewareTestSuite) TearDownTest() {}
+
+func (suite *ApprovalMiddlewareTestSuite) enforce(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		sub := common.ExtractUserContext(ctx).Id
+		suite.subjects = append(suite.subjects, sub)
+
+		if !suite.allowed[sub] {
+			return echo.NewHTTPError(http.StatusForbidden, common.ErrorMessageAccessDenied)
+		}
+
+		return next(ctx)
+	}
+}
+
+func (suite *ApprovalMiddlewareTestSuite) execute(approval *common.Approval) (*httptest.ResponseRecorder, error) {
+	req := httptest.NewRequest(http.MethodPost, approvalTestPath, nil)
+	req = req.WithContext(common.WithApproval(req.Context(), approval))
+
+	ctx, rec := newTestContext(req)
+	ctx.SetPath(approvalTestPath)
+	common.SetUserContext(ctx, &common.AuthUser{Id: "checker"})
+
+	return rec, serveMiddleware(suite.dispatcher.ApprovalMiddleware(suite.enforce), ctx)
+}
+
+func (suite *ApprovalMiddlewareTestSuite) TestApproval_Execute_MakerDenied() {
+	approval := common.NewApproval(&common.AuthUser{Id: "maker"}, "", http.MethodPost, approvalTestPath, approvalTestPath, "", nil)
+
+	_, err := suite.execute(approval)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusForbidden, httpErr.Code)
+	assert.Equal(suite.T(), []string{"maker"}, suite.subjects)
+}
+
+func (suite *ApprovalMiddlewareTestSuite) TestApproval_Execute_MakerAllowed() {
+	suite.allowed["maker"] = true
+	approval := common.NewApproval(&common.AuthUser{Id: "maker"}, "", http.MethodPost, approvalTestPath, approvalTestPath, "", nil)
+
+	rec, err := suite.execute(approval)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Equal(suite.T(), []string{"maker"}, suite.subjects)
+}
+
+func (suite *ApprovalMiddlewareTestSuite) TestApproval_Create() {
+	req := httptest.NewRequest(http.MethodPost, approvalTestPath, nil)
+	ctx, rec := newTestContext(req)
+	ctx.SetPath(approvalTestPath)
+	common.SetUserContext(ctx, &common.AuthUser{Id: "maker"})
+
+	err := serveMiddleware(suite.dispatcher.ApprovalMiddleware(suite.enforce), ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusAccepted, rec.Code)
+	assert.Empty(suite.T(), suite.subjects)
+}
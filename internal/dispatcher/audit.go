@@ -0,0 +1,137 @@
+package dispatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// routeNames indexes the handler names by the method and path template, the routes are indexed on the first request
+type routeNames struct {
+	once  sync.Once
+	names map[string]string
+}
+
+func (r *routeNames) index(e *echo.Echo) {
+	strRepl := strings.NewReplacer("github.com/paysuper/paysuper-management-api/internal/handlers.", "", "(*", "", ")", "", "-fm", "")
+	r.names = make(map[string]string)
+
+	for _, route := range e.Routes() {
+		r.names[route.Method+" "+route.Path] = strRepl.Replace(route.Name)
+	}
+}
+
+func (r *routeNames) name(method, path string) (string, bool) {
+	name, ok := r.names[method+" "+path]
+	return name, ok
+}
+
+// AuditMiddleware records the mutating requests of the system users with the state of the resource before the action,
+// the request payload and the result. The state is read by common.AuditSnapshot of the route.
+func (d *Dispatcher) AuditMiddleware() echo.MiddlewareFunc {
+	routes := &routeNames{}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+
+			if !common.IsMutatingMethod(req.Method) {
+				return next(ctx)
+			}
+
+			routes.once.Do(func() {
+				routes.index(ctx.Echo())
+			})
+
+			user := common.ExtractUserContext(ctx)
+			record := common.NewAuditRecord()
+			record.ActorId = user.Id
+			record.ActorEmail = user.Email
+			record.MerchantId = d.auditMerchantId(ctx)
+			record.Action, _ = routes.name(req.Method, ctx.Path())
+			record.Method = req.Method
+			record.Route = ctx.Path()
+			record.Path = req.URL.Path
+			record.NewPayload = common.AuditPayload(common.ExtractRawBodyContext(ctx), d.globalCfg.AuditMaxPayloadSize)
+			record.RequestId = common.ExtractRequestId(ctx)
+			record.RemoteIp = ctx.RealIP()
+
+			resBody := new(bytes.Buffer)
+			ctx.Response().Writer = &idempotencyResponseWriter{
+				Writer:         io.MultiWriter(ctx.Response().Writer, resBody),
+				ResponseWriter: ctx.Response().Writer,
+			}
+
+			err := next(ctx)
+			d.auditOldPayload(ctx, record)
+			record.Status = ctx.Response().Status
+			record.Result = common.AuditPayload(resBody.Bytes(), d.globalCfg.AuditMaxPayloadSize)
+
+			if err != nil {
+				record.Status = http.StatusInternalServerError
+				record.Error = err.Error()
+
+				if he, ok := err.(*echo.HTTPError); ok {
+					record.Status = he.Code
+
+					if b, e := json.Marshal(he.Message); e == nil {
+						record.Result = b
+					}
+				}
+			}
+
+			if e := d.appSet.AuditStore.Add(record); e != nil {
+				common.RequestLogger(ctx, d.L()).Error("audit record write failed", logger.PairArgs("err", e.Error()), logger.WithPrettyFields(logger.Fields{"record": record}))
+			}
+
+			return err
+		}
+	}
+}
+
+// auditOldPayload saves the state captured by the snapshot of the route
+func (d *Dispatcher) auditOldPayload(ctx echo.Context, record *common.AuditRecord) {
+	snapshot := common.ExtractAuditSnapshotContext(ctx)
+
+	if snapshot == nil {
+		record.OldPayloadStatus = common.AuditOldPayloadNotCaptured
+		return
+	}
+
+	if snapshot.Err != nil {
+		record.OldPayloadStatus = common.AuditOldPayloadFailed
+		common.RequestLogger(ctx, d.L()).Error("audit snapshot failed", logger.PairArgs("action", record.Action, "err", snapshot.Err.Error()))
+		return
+	}
+
+	b, err := json.Marshal(snapshot.Payload)
+
+	if err != nil {
+		record.OldPayloadStatus = common.AuditOldPayloadFailed
+		common.RequestLogger(ctx, d.L()).Error("audit snapshot failed", logger.PairArgs("action", record.Action, "err", err.Error()))
+		return
+	}
+
+	record.OldPayload = common.AuditPayload(b, d.globalCfg.AuditMaxPayloadSize)
+	record.OldPayloadStatus = common.AuditOldPayloadCaptured
+}
+
+// auditMerchantId returns the merchant of the action from the path or the request payload
+func (d *Dispatcher) auditMerchantId(ctx echo.Context) string {
+	if id := ctx.Param(common.RequestParameterMerchantId); id != "" {
+		return id
+	}
+
+	body := struct {
+		MerchantId string `json:"merchant_id"`
+	}{}
+	_ = json.Unmarshal(common.ExtractRawBodyContext(ctx), &body)
+
+	return body.MerchantId
+}
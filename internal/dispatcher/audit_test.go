@@ -0,0 +1,161 @@
+package dispatcher_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	auditTestRoute         = common.SystemUserGroupPath + "/merchants/:merchant_id/audit_test"
+	auditTestSnapshotRoute = common.SystemUserGroupPath + "/merchants/:merchant_id/audit_snapshot_test"
+	auditTestAction        = "AuditTestRoute.update"
+	auditTestMerchantId    = "5dbac6a9120a810001a8fe41"
+	auditTestBody          = `{"status":4}`
+)
+
+type AuditMiddlewareTestSuite struct {
+	suite.Suite
+	dispatcher *dispatcher.Dispatcher
+	echo       *echo.Echo
+	// handler is called by the audited route
+	handler echo.HandlerFunc
+	// snapshot is called by the route with the audit snapshot
+	snapshot common.AuditSnapshotFunc
+}
+
+func Test_AuditMiddleware(t *testing.T) {
+	suite.Run(t, new(AuditMiddlewareTestSuite))
+}
+
+func (suite *AuditMiddlewareTestSuite) SetupTest() {
+	suite.dispatcher = newTestDispatcher()
+	suite.dispatcher.GlobalConfigForTest().AuditMaxPayloadSize = 1024
+	suite.handler = func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}
+
+	suite.snapshot = func(ctx echo.Context) (interface{}, error) {
+		return map[string]int{"status": 3}, nil
+	}
+
+	suite.echo = echo.New()
+	handler := func(ctx echo.Context) error {
+		return suite.handler(ctx)
+	}
+	snapshot := common.AuditSnapshot(func(ctx echo.Context) (interface{}, error) {
+		return suite.snapshot(ctx)
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		suite.echo.Add(method, auditTestRoute, handler, suite.authenticate, suite.dispatcher.AuditMiddleware()).Name = auditTestAction
+		suite.echo.Add(method, auditTestSnapshotRoute, handler, suite.authenticate, suite.dispatcher.AuditMiddleware(), snapshot).Name = auditTestAction
+	}
+}
+
+func (suite *AuditMiddlewareTestSuite) TearDownTest() {}
+
+// authenticate does the work of the system user group middlewares which run before the audit
+func (suite *AuditMiddlewareTestSuite) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		common.SetUserContext(ctx, &common.AuthUser{Id: "admin", Email: "admin@paysuper.com"})
+		common.SetRawBodyContext(ctx, []byte(auditTestBody))
+		return next(ctx)
+	}
+}
+
+func (suite *AuditMiddlewareTestSuite) serve(method string) *httptest.ResponseRecorder {
+	return suite.serveRoute(method, auditTestRoute)
+}
+
+func (suite *AuditMiddlewareTestSuite) serveRoute(method, route string) *httptest.ResponseRecorder {
+	path := strings.Replace(route, ":"+common.RequestParameterMerchantId, auditTestMerchantId, 1)
+	req := httptest.NewRequest(method, path, strings.NewReader(auditTestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	suite.echo.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func (suite *AuditMiddlewareTestSuite) records() []*common.AuditRecord {
+	records, _, err := suite.dispatcher.AppSetForTest().AuditStore.List(&common.AuditFilter{})
+	assert.NoError(suite.T(), err)
+	return records
+}
+
+func (suite *AuditMiddlewareTestSuite) TestAudit_Ok() {
+	rec := suite.serve(http.MethodPost)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+
+	records := suite.records()
+	assert.Len(suite.T(), records, 1)
+
+	record := records[0]
+	assert.Equal(suite.T(), "admin", record.ActorId)
+	assert.Equal(suite.T(), "admin@paysuper.com", record.ActorEmail)
+	assert.Equal(suite.T(), auditTestAction, record.Action)
+	assert.Equal(suite.T(), http.MethodPost, record.Method)
+	assert.Equal(suite.T(), auditTestRoute, record.Route)
+	assert.Equal(suite.T(), auditTestMerchantId, record.MerchantId)
+	assert.JSONEq(suite.T(), auditTestBody, string(record.NewPayload))
+	assert.Empty(suite.T(), record.OldPayload)
+	assert.Equal(suite.T(), common.AuditOldPayloadNotCaptured, record.OldPayloadStatus)
+	assert.JSONEq(suite.T(), `{"status":"ok"}`, string(record.Result))
+	assert.Equal(suite.T(), http.StatusOK, record.Status)
+	assert.Empty(suite.T(), record.Error)
+}
+
+func (suite *AuditMiddlewareTestSuite) TestAudit_Get_NotRecorded() {
+	rec := suite.serve(http.MethodGet)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Empty(suite.T(), suite.records())
+}
+
+func (suite *AuditMiddlewareTestSuite) TestAudit_HandlerError() {
+	suite.handler = func(ctx echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	rec := suite.serve(http.MethodPost)
+	assert.Equal(suite.T(), http.StatusBadRequest, rec.Code)
+
+	records := suite.records()
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), http.StatusBadRequest, records[0].Status)
+	assert.NotEmpty(suite.T(), records[0].Error)
+	assert.Contains(suite.T(), string(records[0].Result), common.ErrorRequestParamsIncorrect.Code)
+}
+
+func (suite *AuditMiddlewareTestSuite) TestAudit_Snapshot_Captured() {
+	rec := suite.serveRoute(http.MethodPost, auditTestSnapshotRoute)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+
+	records := suite.records()
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), common.AuditOldPayloadCaptured, records[0].OldPayloadStatus)
+	assert.JSONEq(suite.T(), `{"status":3}`, string(records[0].OldPayload))
+	assert.JSONEq(suite.T(), auditTestBody, string(records[0].NewPayload))
+}
+
+func (suite *AuditMiddlewareTestSuite) TestAudit_Snapshot_Failed() {
+	suite.snapshot = func(ctx echo.Context) (interface{}, error) {
+		return nil, common.ErrorUnknown
+	}
+
+	rec := suite.serveRoute(http.MethodPost, auditTestSnapshotRoute)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+
+	records := suite.records()
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), common.AuditOldPayloadFailed, records[0].OldPayloadStatus)
+	assert.Empty(suite.T(), records[0].OldPayload)
+	assert.Equal(suite.T(), http.StatusOK, records[0].Status)
+}
@@ -0,0 +1,118 @@
+package dispatcher
+
+import (
+	jwtverifier "github.com/ProtocolONE/authone-jwt-verifier-golang"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"net/http"
+)
+
+const (
+	authCacheToken     = "token"
+	authCacheProfile   = "profile"
+	authCacheMerchants = "merchants"
+
+	authCacheHit  = "hit"
+	authCacheMiss = "miss"
+)
+
+// cachedToken is the introspection result of the token, user is nil for the invalid token
+type cachedToken struct {
+	user *jwtverifier.UserInfo
+}
+
+// cachedProfile is empty if the user has no profile yet
+type cachedProfile struct {
+	profileId string
+	locale    string
+}
+
+func (d *Dispatcher) authCacheGet(cache, key string) (interface{}, bool) {
+	value, ok := d.appSet.AuthCache.Get(key)
+	result := authCacheMiss
+
+	if ok {
+		result = authCacheHit
+	}
+
+	authCacheRequestsTotal.WithLabelValues(cache, result).Inc()
+	return value, ok
+}
+
+// getUserInfo introspects the token, the invalid tokens are remembered for the shorter time
+func (d *Dispatcher) getUserInfo(ctx echo.Context, token string) (*jwtverifier.UserInfo, error) {
+	key := common.AuthCacheTokenKey(token)
+
+	if value, ok := d.authCacheGet(authCacheToken, key); ok {
+		if cached := value.(*cachedToken); cached.user != nil {
+			return cached.user, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizedUserNotFound)
+	}
+
+	u, err := d.appSet.JwtVerifier.GetUserInfo(ctx.Request().Context(), token)
+
+	if err != nil {
+		d.appSet.AuthCache.Set(key, &cachedToken{}, d.globalCfg.AuthCacheNegativeTtl)
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizedUserNotFound)
+	}
+
+	d.appSet.AuthCache.Set(key, &cachedToken{user: u}, d.globalCfg.AuthCacheTtl, common.AuthCacheUserTag(u.UserID))
+	return u, nil
+}
+
+// getUserProfile returns the profile data used by the request context, failed calls aren't cached
+func (d *Dispatcher) getUserProfile(ctx echo.Context, userId string) *cachedProfile {
+	key := common.AuthCacheProfileKey(userId)
+
+	if value, ok := d.authCacheGet(authCacheProfile, key); ok {
+		return value.(*cachedProfile)
+	}
+
+	req := &billingpb.GetUserProfileRequest{
+		UserId: userId,
+	}
+	rsp, err := d.appSet.Services.Billing.GetUserProfile(ctx.Request().Context(), req)
+
+	if err != nil || rsp == nil {
+		return &cachedProfile{}
+	}
+
+	profile := &cachedProfile{}
+
+	if rsp.Status == http.StatusOK {
+		profile.profileId = rsp.Item.Id
+		profile.locale = rsp.Item.Locale
+	}
+
+	d.appSet.AuthCache.Set(key, profile, d.globalCfg.AuthCacheTtl, common.AuthCacheUserTag(userId))
+	return profile
+}
+
+// getMerchantsForUser returns the merchants with the user roles, the value is dropped when any of the merchants changes the users
+func (d *Dispatcher) getMerchantsForUser(ctx echo.Context, userId string) (*billingpb.GetMerchantsForUserResponse, error) {
+	key := common.AuthCacheMerchantsKey(userId)
+
+	if value, ok := d.authCacheGet(authCacheMerchants, key); ok {
+		return value.(*billingpb.GetMerchantsForUserResponse), nil
+	}
+
+	res, err := d.appSet.Services.Billing.GetMerchantsForUser(
+		ctx.Request().Context(),
+		&billingpb.GetMerchantsForUserRequest{UserId: userId},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{common.AuthCacheUserTag(userId)}
+
+	for _, merchant := range res.Merchants {
+		tags = append(tags, common.AuthCacheMerchantTag(merchant.Id))
+	}
+
+	d.appSet.AuthCache.Set(key, res, d.globalCfg.AuthCacheTtl, tags...)
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package dispatcher
+
+import (
+	"context"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	casbinMiddleware "github.com/paysuper/echo-casbin-middleware"
+	"github.com/paysuper/paysuper-management-api/internal/casbin"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/casbinpb"
+	"net/http"
+	"path/filepath"
+	"strconv"
+)
+
+const casbinRemoteAllowedKey = "casbinRemoteAllowed"
+
+// initLocalEnforcer loads the policy for the embedded enforcer, the policy is reloaded with the config
+// and periodically to pick up the changes of the policy file and remote policy
+func (d *Dispatcher) initLocalEnforcer() error {
+	d.enforcer = casbin.NewLocalEnforcer(
+		d.workDirPath(d.globalCfg.CasbinModelPath),
+		d.workDirPath(d.globalCfg.CasbinPolicyPath),
+		casbinpb.NewCasbinService("", d.ms.Client("", "")),
+		d.L(),
+	)
+
+	if e := d.enforcer.Reload(d.ctx); e != nil {
+		return e
+	}
+
+	d.cfg.OnReload(func(ctx context.Context) {
+		if e := d.enforcer.Reload(ctx); e != nil {
+			d.L().Error("casbin policy reload failed", logger.PairArgs("err", e.Error()))
+		}
+	})
+	go d.enforcer.Watch(d.ctx, d.globalCfg.CasbinReloadInterval)
+
+	return nil
+}
+
+// CasbinMiddleware enforces the policy by the remote casbin service or the embedded enforcer.
+// In shadow mode both enforcers are asked and the disagreements are logged.
+func (d *Dispatcher) CasbinMiddleware(fn func(c echo.Context) string) echo.MiddlewareFunc {
+	cfg := casbinMiddleware.Config{
+		Skipper:          middleware.DefaultSkipper,
+		Mode:             casbinMiddleware.EnforceModeEnforcing,
+		Logger:           d.L(),
+		CtxUserExtractor: fn,
+	}
+
+	return d.casbinMiddleware(fn, casbinMiddleware.MiddlewareWithConfig(d.ms.Client("", ""), cfg))
+}
+
+// casbinMiddleware combines the middleware of the remote enforcer with the embedded enforcer by the casbin mode
+func (d *Dispatcher) casbinMiddleware(fn func(c echo.Context) string, remote echo.MiddlewareFunc) echo.MiddlewareFunc {
+	if d.enforcer == nil {
+		return remote
+	}
+
+	if d.globalCfg.CasbinMode != common.CasbinModeLocal {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			remoteNext := remote(func(c echo.Context) error {
+				c.Set(casbinRemoteAllowedKey, true)
+				return next(c)
+			})
+
+			return func(c echo.Context) error {
+				local, localErr := d.enforcer.Enforce(fn(c), c.Request().URL.Path, c.Request().Method)
+				err := remoteNext(c)
+
+				if remoteAllowed, ok := casbinRemoteDecision(c, err); ok && localErr == nil {
+					d.casbinShadowCompare(c, fn(c), local, remoteAllowed)
+				}
+
+				return err
+			}
+		}
+	}
+
+	probe := remote(func(c echo.Context) error {
+		c.Set(casbinRemoteAllowedKey, true)
+		return nil
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			sub := fn(c)
+			allowed, err := d.enforcer.Enforce(sub, c.Request().URL.Path, c.Request().Method)
+
+			if err != nil {
+				common.RequestLogger(c, d.L()).Error("casbin enforce failed", logger.PairArgs("err", err.Error()))
+				return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
+			}
+
+			if d.globalCfg.CasbinShadow {
+				if remoteAllowed, ok := casbinRemoteDecision(c, probe(c)); ok {
+					d.casbinShadowCompare(c, sub, allowed, remoteAllowed)
+				}
+			}
+
+			if !allowed {
+				return echo.NewHTTPError(http.StatusForbidden, common.ErrorMessageAccessDenied)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// casbinRemoteDecision returns the decision of the remote enforcer, ok is false if the remote call failed
+func casbinRemoteDecision(c echo.Context, err error) (allowed bool, ok bool) {
+	if v, _ := c.Get(casbinRemoteAllowedKey).(bool); v {
+		return true, true
+	}
+
+	if httpErr, isHttpErr := err.(*echo.HTTPError); isHttpErr && httpErr.Code == http.StatusForbidden {
+		return false, true
+	}
+
+	return false, false
+}
+
+func (d *Dispatcher) casbinShadowCompare(c echo.Context, sub string, local, remote bool) {
+	if local == remote {
+		return
+	}
+
+	casbinShadowDisagreementsTotal.Inc()
+	common.RequestLogger(c, d.L()).Error(
+		"casbin enforcers disagree",
+		logger.WithFields(logger.Fields{
+			"subject": sub,
+			"path":    c.Request().URL.Path,
+			"method":  c.Request().Method,
+			"local":   strconv.FormatBool(local),
+			"remote":  strconv.FormatBool(remote),
+		}),
+	)
+}
+
+func (d *Dispatcher) workDirPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(d.cfg.WorkDir, path)
+}
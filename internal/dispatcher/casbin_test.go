@@ -0,0 +1,204 @@
+package dispatcher_test
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	microMock "github.com/micro/go-micro/client/mock"
+	"github.com/paysuper/paysuper-management-api/internal/casbin"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/casbinpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	casbinModelPath  = "../../assets/casbin_model.conf"
+	casbinPolicyPath = "../../assets/policy.conf"
+	casbinTestPolicy = `
+p,merchantListOrders,/admin/api/v1/order,GET
+p,merchantGetOrderTimeline,/admin/api/v1/order/:order_id/timeline,GET
+g,merchant_owner,merchantListOrders
+g,merchant_owner,merchantGetOrderTimeline
+g,merchant_view_only,merchantListOrders
+`
+	casbinTestOrderId = "5dbac6a9120a810001a8fe41"
+)
+
+type CasbinMiddlewareTestSuite struct {
+	suite.Suite
+	dispatcher *dispatcher.Dispatcher
+	dir        string
+	policyPath string
+	enforcer   *casbin.LocalEnforcer
+}
+
+func Test_CasbinMiddleware(t *testing.T) {
+	suite.Run(t, new(CasbinMiddlewareTestSuite))
+}
+
+func (suite *CasbinMiddlewareTestSuite) SetupTest() {
+	suite.dispatcher = newTestDispatcher()
+
+	dir, err := ioutil.TempDir("", "casbin")
+	assert.NoError(suite.T(), err)
+
+	suite.dir = dir
+	suite.policyPath = filepath.Join(dir, "policy.conf")
+	assert.NoError(suite.T(), ioutil.WriteFile(suite.policyPath, []byte(casbinTestPolicy), 0644))
+
+	suite.enforcer = suite.newEnforcer(suite.policyPath)
+	suite.dispatcher.SetEnforcerForTest(suite.enforcer)
+}
+
+func (suite *CasbinMiddlewareTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+// newEnforcer loads the policy file only, the casbin service is unavailable for the tests
+func (suite *CasbinMiddlewareTestSuite) newEnforcer(policyPath string) *casbin.LocalEnforcer {
+	srv := casbinpb.NewCasbinService("", microMock.NewClient(nil))
+	enforcer := casbin.NewLocalEnforcer(casbinModelPath, policyPath, srv, suite.dispatcher.L())
+	assert.NoError(suite.T(), enforcer.Reload(context.Background()))
+
+	return enforcer
+}
+
+// remote replaces the remote enforcer, it denies the request as the casbin middleware does
+func (suite *CasbinMiddlewareTestSuite) remote(allowed bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if !allowed {
+				return echo.NewHTTPError(http.StatusForbidden, "Forbidden")
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func (suite *CasbinMiddlewareTestSuite) serve(sub, method, path string, remoteAllowed bool) error {
+	ctx, _ := newTestContext(httptest.NewRequest(method, path, nil))
+	fn := func(c echo.Context) string {
+		return sub
+	}
+
+	return serveMiddleware(suite.dispatcher.CasbinMiddlewareForTest(fn, suite.remote(remoteAllowed)), ctx)
+}
+
+func (suite *CasbinMiddlewareTestSuite) assertForbidden(err error) {
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusForbidden, httpErr.Code)
+}
+
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Local_Allow() {
+	suite.dispatcher.GlobalConfigForTest().CasbinMode = common.CasbinModeLocal
+
+	err := suite.serve("merchant_owner", http.MethodGet, "/admin/api/v1/order/"+casbinTestOrderId+"/timeline", false)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Local_Deny() {
+	suite.dispatcher.GlobalConfigForTest().CasbinMode = common.CasbinModeLocal
+
+	err := suite.serve("merchant_view_only", http.MethodGet, "/admin/api/v1/order/"+casbinTestOrderId+"/timeline", true)
+	suite.assertForbidden(err)
+	assert.Equal(suite.T(), common.ErrorMessageAccessDenied, err.(*echo.HTTPError).Message)
+}
+
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Local_Shadow_Disagreement() {
+	cfg := suite.dispatcher.GlobalConfigForTest()
+	cfg.CasbinMode = common.CasbinModeLocal
+	cfg.CasbinShadow = true
+	disagreements := dispatcher.CasbinShadowDisagreementsForTest()
+
+	// the local decision is used, the remote one is compared only
+	err := suite.serve("merchant_owner", http.MethodGet, "/admin/api/v1/order", false)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), disagreements+1, dispatcher.CasbinShadowDisagreementsForTest())
+
+	err = suite.serve("merchant_owner", http.MethodGet, "/admin/api/v1/order", true)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), disagreements+1, dispatcher.CasbinShadowDisagreementsForTest())
+}
+
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Remote_Shadow_Disagreement() {
+	suite.dispatcher.GlobalConfigForTest().CasbinMode = common.CasbinModeRemote
+	disagreements := dispatcher.CasbinShadowDisagreementsForTest()
+
+	// the remote decision is used, the local one is compared only
+	err := suite.serve("merchant_view_only", http.MethodGet, "/admin/api/v1/order/"+casbinTestOrderId+"/timeline", true)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), disagreements+1, dispatcher.CasbinShadowDisagreementsForTest())
+
+	err = suite.serve("merchant_owner", http.MethodGet, "/admin/api/v1/order", false)
+	suite.assertForbidden(err)
+	assert.Equal(suite.T(), disagreements+2, dispatcher.CasbinShadowDisagreementsForTest())
+
+	err = suite.serve("merchant_view_only", http.MethodGet, "/admin/api/v1/order", true)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), disagreements+2, dispatcher.CasbinShadowDisagreementsForTest())
+}
+
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Reload_RemoteFailed_EnforcerKept() {
+	assert.NoError(suite.T(), ioutil.WriteFile(suite.policyPath, []byte("p,merchantListOrders,/admin/api/v1/order,GET\n"), 0644))
+
+	err := suite.enforcer.Reload(context.Background())
+	assert.Error(suite.T(), err)
+
+	allowed, err := suite.enforcer.Enforce("merchant_owner", "/admin/api/v1/order", http.MethodGet)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), allowed)
+}
+
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Reload_PolicyFileFailed_EnforcerKept() {
+	assert.NoError(suite.T(), os.Remove(suite.policyPath))
+
+	err := suite.enforcer.Reload(context.Background())
+	assert.Error(suite.T(), err)
+
+	allowed, err := suite.enforcer.Enforce("merchant_owner", "/admin/api/v1/order", http.MethodGet)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), allowed)
+}
+
+// TestCasbin_Policy enforces the shipped policy with the shipped model for the paths of the real routes
+func (suite *CasbinMiddlewareTestSuite) TestCasbin_Policy() {
+	enforcer := suite.newEnforcer(casbinPolicyPath)
+
+	tests := []struct {
+		sub     string
+		method  string
+		path    string
+		allowed bool
+	}{
+		{"merchant_owner", http.MethodGet, "/admin/api/v1/order/" + casbinTestOrderId + "/timeline", true},
+		{"merchant_view_only", http.MethodGet, "/admin/api/v1/order/" + casbinTestOrderId + "/timeline", true},
+		{"merchant_owner", http.MethodPost, "/admin/api/v1/refunds/bulk", true},
+		{"merchant_developer", http.MethodPost, "/admin/api/v1/refunds/bulk", true},
+		{"merchant_view_only", http.MethodPost, "/admin/api/v1/refunds/bulk", false},
+		{"merchant_accounting", http.MethodPost, "/admin/api/v1/refunds/bulk", false},
+		{"merchant_owner", http.MethodGet, "/admin/api/v1/projects/" + casbinTestOrderId + "/api_keys", true},
+		{"merchant_view_only", http.MethodGet, "/admin/api/v1/projects/" + casbinTestOrderId + "/api_keys", false},
+		{"merchant_view_only", http.MethodDelete, "/admin/api/v1/user/sessions/" + casbinTestOrderId, true},
+		{"system_admin", http.MethodGet, "/system/api/v1/audit", true},
+		{"system_admin", http.MethodPut, "/system/api/v1/order/" + casbinTestOrderId + "/replace_code", true},
+		{"system_view_only", http.MethodGet, "/system/api/v1/audit", false},
+		{"merchant_owner", http.MethodGet, "/system/api/v1/audit", false},
+		{"system_admin", http.MethodGet, "/system/api/v1/audit/" + casbinTestOrderId, false},
+	}
+
+	for _, tt := range tests {
+		allowed, err := enforcer.Enforce(tt.sub, tt.path, tt.method)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), tt.allowed, allowed, "%s %s %s", tt.sub, tt.method, tt.path)
+	}
+}
@@ -0,0 +1,297 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	ApiKeyScopeReadOrders   = "read-orders"
+	ApiKeyScopeCreateTokens = "create-tokens"
+	ApiKeyScopeRefunds      = "refunds"
+
+	apiKeySecretLength = 32
+)
+
+// ApiKey is the scoped credential of the project for S2S requests
+type ApiKey struct {
+	Id         string   `json:"id" bson:"_id"`
+	ProjectId  string   `json:"project_id" bson:"project_id"`
+	MerchantId string   `json:"merchant_id" bson:"merchant_id"`
+	Name       string   `json:"name" bson:"name"`
+	Scopes     []string `json:"scopes" bson:"scopes"`
+	// Secret is returned to the client only once, when the key is created or rotated
+	Secret     string     `json:"secret,omitempty" bson:"secret"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" bson:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// ApiKeyStore is a storage backend for the project API keys
+type ApiKeyStore interface {
+	Create(key *ApiKey) error
+	// Get returns nil if the key isn't found
+	Get(projectId, id string) (*ApiKey, error)
+	List(projectId string) ([]*ApiKey, error)
+	Update(key *ApiKey) error
+	// Delete returns false if the key isn't found
+	Delete(projectId, id string) (bool, error)
+	// Touch saves the time the key was last used at
+	Touch(projectId, id string, usedAt time.Time) error
+}
+
+// NewApiKey generates the key with the new random secret
+func NewApiKey(projectId, merchantId, name string, scopes []string, expiresAt *time.Time) (*ApiKey, error) {
+	secret := make([]byte, apiKeySecretLength)
+
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return &ApiKey{
+		Id:         bson.NewObjectId().Hex(),
+		ProjectId:  projectId,
+		MerchantId: merchantId,
+		Name:       name,
+		Scopes:     scopes,
+		Secret:     hex.EncodeToString(secret),
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// IsExpired
+func (k *ApiKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// HasScope
+func (k *ApiKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Public returns the copy of the key without secret
+func (k *ApiKey) Public() *ApiKey {
+	key := *k
+	key.Secret = ""
+	return &key
+}
+
+// RequireApiKeyScope denies the S2S requests authenticated by API key without the scope,
+// requests authenticated by the project secret have all scopes
+func RequireApiKeyScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			user := ExtractUserContext(ctx)
+
+			if user.ApiKeyId != "" && !user.HasScope(scope) {
+				return echo.NewHTTPError(http.StatusForbidden, ErrorMessageApiKeyScopeDenied)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+type mongoApiKeyStore struct {
+	keys mongoCollection
+}
+
+// NewMongoApiKeyStore returns the API keys storage shared by all instances
+func NewMongoApiKeyStore(db *mgo.Database) (ApiKeyStore, error) {
+	s := &mongoApiKeyStore{
+		keys: mongoCollection{db: db, name: collectionApiKeys},
+	}
+
+	if err := s.keys.ensureIndexes(mgo.Index{Key: []string{"project_id", "created_at"}}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoApiKeyStore) Create(key *ApiKey) error {
+	return s.keys.with(func(c *mgo.Collection) error {
+		return c.Insert(key)
+	})
+}
+
+func (s *mongoApiKeyStore) Get(projectId, id string) (*ApiKey, error) {
+	key := &ApiKey{}
+	err := s.keys.with(func(c *mgo.Collection) error {
+		return c.Find(bson.M{"_id": id, "project_id": projectId}).One(key)
+	})
+
+	if isMongoNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (s *mongoApiKeyStore) List(projectId string) ([]*ApiKey, error) {
+	keys := make([]*ApiKey, 0)
+	err := s.keys.with(func(c *mgo.Collection) error {
+		return c.Find(bson.M{"project_id": projectId}).Sort("created_at").All(&keys)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *mongoApiKeyStore) Update(key *ApiKey) error {
+	err := s.keys.with(func(c *mgo.Collection) error {
+		return c.Update(bson.M{"_id": key.Id, "project_id": key.ProjectId}, key)
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *mongoApiKeyStore) Delete(projectId, id string) (bool, error) {
+	err := s.keys.with(func(c *mgo.Collection) error {
+		return c.Remove(bson.M{"_id": id, "project_id": projectId})
+	})
+
+	if isMongoNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *mongoApiKeyStore) Touch(projectId, id string, usedAt time.Time) error {
+	err := s.keys.with(func(c *mgo.Collection) error {
+		return c.Update(bson.M{"_id": id, "project_id": projectId}, bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+type memoryApiKeyStore struct {
+	mx   sync.RWMutex
+	keys map[string]map[string]*ApiKey
+}
+
+// NewMemoryApiKeyStore returns the in-memory API keys storage for tests, keys aren't shared between instances
+func NewMemoryApiKeyStore() ApiKeyStore {
+	return &memoryApiKeyStore{
+		keys: make(map[string]map[string]*ApiKey),
+	}
+}
+
+func (s *memoryApiKeyStore) Create(key *ApiKey) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, ok := s.keys[key.ProjectId]; !ok {
+		s.keys[key.ProjectId] = make(map[string]*ApiKey)
+	}
+
+	s.keys[key.ProjectId][key.Id] = copyApiKey(key)
+	return nil
+}
+
+func (s *memoryApiKeyStore) Get(projectId, id string) (*ApiKey, error) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	if key, ok := s.keys[projectId][id]; ok {
+		return copyApiKey(key), nil
+	}
+
+	return nil, nil
+}
+
+func (s *memoryApiKeyStore) List(projectId string) ([]*ApiKey, error) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	keys := make([]*ApiKey, 0, len(s.keys[projectId]))
+
+	for _, key := range s.keys[projectId] {
+		keys = append(keys, copyApiKey(key))
+	}
+
+	return keys, nil
+}
+
+func (s *memoryApiKeyStore) Update(key *ApiKey) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, ok := s.keys[key.ProjectId][key.Id]; ok {
+		s.keys[key.ProjectId][key.Id] = copyApiKey(key)
+	}
+
+	return nil
+}
+
+func (s *memoryApiKeyStore) Delete(projectId, id string) (bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, ok := s.keys[projectId][id]; !ok {
+		return false, nil
+	}
+
+	delete(s.keys[projectId], id)
+	return true, nil
+}
+
+func (s *memoryApiKeyStore) Touch(projectId, id string, usedAt time.Time) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if key, ok := s.keys[projectId][id]; ok {
+		key.LastUsedAt = &usedAt
+	}
+
+	return nil
+}
+
+func copyApiKey(key *ApiKey) *ApiKey {
+	c := *key
+	c.Scopes = append([]string(nil), key.Scopes...)
+
+	if key.ExpiresAt != nil {
+		expiresAt := *key.ExpiresAt
+		c.ExpiresAt = &expiresAt
+	}
+
+	if key.LastUsedAt != nil {
+		lastUsedAt := *key.LastUsedAt
+		c.LastUsedAt = &lastUsedAt
+	}
+
+	return &c
+}
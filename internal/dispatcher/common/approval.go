@@ -0,0 +1,330 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	// The change is approved, but the original handler returned the error
+	ApprovalStatusFailed = "failed"
+)
+
+type approvalContextKey struct{}
+
+// Approval is the request of the system user which runs only after the approval of the second system user
+type Approval struct {
+	Id     string `json:"id" bson:"_id"`
+	Action string `json:"action" bson:"action"`
+	Method string `json:"method" bson:"method"`
+	// Route is the path template and Path is the requested path with the query string
+	Route       string          `json:"route" bson:"route"`
+	Path        string          `json:"path" bson:"path"`
+	ContentType string          `json:"content_type,omitempty" bson:"content_type"`
+	Payload     json.RawMessage `json:"payload,omitempty" bson:"payload"`
+	MerchantId  string          `json:"merchant_id,omitempty" bson:"merchant_id"`
+	MakerId     string          `json:"maker_id" bson:"maker_id"`
+	MakerEmail  string          `json:"maker_email,omitempty" bson:"maker_email"`
+	CheckerId   string          `json:"checker_id,omitempty" bson:"checker_id"`
+	Comment     string          `json:"comment,omitempty" bson:"comment"`
+	Status      string          `json:"status" bson:"status"`
+	// Response of the original handler after the approval
+	ResultStatus int             `json:"result_status,omitempty" bson:"result_status"`
+	Result       json.RawMessage `json:"result,omitempty" bson:"result"`
+	CreatedAt    time.Time       `json:"created_at" bson:"created_at"`
+	DecidedAt    *time.Time      `json:"decided_at,omitempty" bson:"decided_at"`
+	// Maker is the user on behalf of which the original handler runs
+	Maker *AuthUser `json:"-" bson:"maker"`
+	// Body is the raw request body, Payload is its JSON representation
+	Body []byte `json:"-" bson:"body"`
+}
+
+// ApprovalFilter
+type ApprovalFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// ApprovalStore keeps the approval requests
+type ApprovalStore interface {
+	Create(approval *Approval) error
+	// Get returns nil if the approval doesn't exist
+	Get(id string) (*Approval, error)
+	// List returns the matching approvals, the newest first, and the total count of the matching approvals
+	List(filter *ApprovalFilter) (approvals []*Approval, count int, err error)
+	// Decide changes the status of the pending approval, decided is false if the approval isn't pending anymore
+	Decide(id, checkerId, status, comment string) (approval *Approval, decided bool, err error)
+	// Complete saves the response of the original handler
+	Complete(id, status string, resultStatus int, result json.RawMessage) error
+}
+
+// NewApproval
+func NewApproval(user *AuthUser, action, method, route, path, contentType string, body []byte) *Approval {
+	maker := *user
+	return &Approval{
+		Id:          bson.NewObjectId().Hex(),
+		Action:      action,
+		Method:      method,
+		Route:       route,
+		Path:        path,
+		ContentType: contentType,
+		Body:        body,
+		Payload:     AuditPayload(body, len(body)),
+		MakerId:     user.Id,
+		MakerEmail:  user.Email,
+		Status:      ApprovalStatusPending,
+		CreatedAt:   time.Now().UTC(),
+		Maker:       &maker,
+	}
+}
+
+// ParseApprovalRoutes splits the comma separated list of the actions which require the approval
+func ParseApprovalRoutes(routes string) map[string]bool {
+	actions := make(map[string]bool)
+
+	for _, action := range strings.Split(routes, ",") {
+		if action = strings.TrimSpace(action); action != "" {
+			actions[action] = true
+		}
+	}
+
+	return actions
+}
+
+// WithApproval marks the request context as the execution of the approved request
+func WithApproval(ctx context.Context, approval *Approval) context.Context {
+	return context.WithValue(ctx, approvalContextKey{}, approval)
+}
+
+// ExtractApproval returns the approved request which is executed within the context
+func ExtractApproval(ctx context.Context) *Approval {
+	approval, _ := ctx.Value(approvalContextKey{}).(*Approval)
+	return approval
+}
+
+type mongoApprovalStore struct {
+	approvals mongoCollection
+}
+
+// NewMongoApprovalStore returns the approval storage shared by all instances
+func NewMongoApprovalStore(db *mgo.Database) (ApprovalStore, error) {
+	s := &mongoApprovalStore{
+		approvals: mongoCollection{db: db, name: collectionApprovals},
+	}
+
+	if err := s.approvals.ensureIndexes(mgo.Index{Key: []string{"status", "-created_at"}}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoApprovalStore) Create(approval *Approval) error {
+	return s.approvals.with(func(c *mgo.Collection) error {
+		return c.Insert(approval)
+	})
+}
+
+func (s *mongoApprovalStore) Get(id string) (*Approval, error) {
+	approval := &Approval{}
+	err := s.approvals.with(func(c *mgo.Collection) error {
+		return c.FindId(id).One(approval)
+	})
+
+	if isMongoNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+func (s *mongoApprovalStore) List(filter *ApprovalFilter) ([]*Approval, int, error) {
+	query := bson.M{}
+
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	approvals := make([]*Approval, 0)
+	count := 0
+
+	err := s.approvals.with(func(c *mgo.Collection) error {
+		var err error
+
+		if count, err = c.Find(query).Count(); err != nil {
+			return err
+		}
+
+		return c.Find(query).Sort("-created_at").Skip(filter.Offset).Limit(filter.Limit).All(&approvals)
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return approvals, count, nil
+}
+
+func (s *mongoApprovalStore) Decide(id, checkerId, status, comment string) (*Approval, bool, error) {
+	approval := &Approval{}
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{
+			"status":     status,
+			"checker_id": checkerId,
+			"comment":    comment,
+			"decided_at": time.Now().UTC(),
+		}},
+		ReturnNew: true,
+	}
+
+	err := s.approvals.with(func(c *mgo.Collection) error {
+		_, err := c.Find(bson.M{"_id": id, "status": ApprovalStatusPending}).Apply(change, approval)
+		return err
+	})
+
+	if isMongoNotFound(err) {
+		approval, err = s.Get(id)
+		return approval, false, err
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return approval, true, nil
+}
+
+func (s *mongoApprovalStore) Complete(id, status string, resultStatus int, result json.RawMessage) error {
+	err := s.approvals.with(func(c *mgo.Collection) error {
+		return c.UpdateId(id, bson.M{"$set": bson.M{
+			"status":        status,
+			"result_status": resultStatus,
+			"result":        result,
+		}})
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+type memoryApprovalStore struct {
+	mx        sync.RWMutex
+	approvals map[string]*Approval
+}
+
+// NewMemoryApprovalStore returns the in-memory approval storage for tests
+func NewMemoryApprovalStore() ApprovalStore {
+	return &memoryApprovalStore{
+		approvals: make(map[string]*Approval),
+	}
+}
+
+func (s *memoryApprovalStore) Create(approval *Approval) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	cp := *approval
+	s.approvals[approval.Id] = &cp
+	return nil
+}
+
+func (s *memoryApprovalStore) Get(id string) (*Approval, error) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	approval, ok := s.approvals[id]
+
+	if !ok {
+		return nil, nil
+	}
+
+	cp := *approval
+	return &cp, nil
+}
+
+func (s *memoryApprovalStore) List(filter *ApprovalFilter) ([]*Approval, int, error) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	var matched []*Approval
+
+	for _, approval := range s.approvals {
+		if filter.Status == "" || approval.Status == filter.Status {
+			cp := *approval
+			matched = append(matched, &cp)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	count := len(matched)
+
+	if filter.Offset >= count {
+		return []*Approval{}, count, nil
+	}
+
+	matched = matched[filter.Offset:]
+
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	return matched, count, nil
+}
+
+func (s *memoryApprovalStore) Decide(id, checkerId, status, comment string) (*Approval, bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	approval, ok := s.approvals[id]
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	if approval.Status != ApprovalStatusPending {
+		cp := *approval
+		return &cp, false, nil
+	}
+
+	now := time.Now().UTC()
+	approval.Status = status
+	approval.CheckerId = checkerId
+	approval.Comment = comment
+	approval.DecidedAt = &now
+
+	cp := *approval
+	return &cp, true, nil
+}
+
+func (s *memoryApprovalStore) Complete(id, status string, resultStatus int, result json.RawMessage) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if approval, ok := s.approvals[id]; ok {
+		approval.Status = status
+		approval.ResultStatus = resultStatus
+		approval.Result = result
+	}
+
+	return nil
+}
@@ -0,0 +1,397 @@
+package common
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/labstack/echo/v4"
+	"github.com/micro/go-micro/broker"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AuditSinkStdout = "stdout"
+	AuditSinkFile   = "file"
+	AuditSinkBroker = "broker"
+	AuditSinkNone   = "none"
+
+	// The state before the action is read by the snapshot of the route
+	AuditOldPayloadCaptured = "captured"
+	// The snapshot of the route failed, the action is done anyway
+	AuditOldPayloadFailed = "failed"
+	// The route has no snapshot or the handler wasn't reached, e.g. the action waits for the approval
+	AuditOldPayloadNotCaptured = "not_captured"
+
+	auditSnapshotContextKey = "auditSnapshot"
+)
+
+// AuditRecord describes the privileged action of the system user
+type AuditRecord struct {
+	Id         string `json:"id" bson:"_id"`
+	ActorId    string `json:"actor_id" bson:"actor_id"`
+	ActorEmail string `json:"actor_email,omitempty" bson:"actor_email"`
+	MerchantId string `json:"merchant_id,omitempty" bson:"merchant_id"`
+	// Action is the name of the handler, e.g. OrderRoute.replaceCode
+	Action string `json:"action" bson:"action"`
+	Method string `json:"method" bson:"method"`
+	// Route is the path template and Path is the requested path
+	Route string `json:"route" bson:"route"`
+	Path  string `json:"path" bson:"path"`
+	// OldPayload is the state of the resource before the action, it's read by the snapshot registered for the route
+	OldPayload json.RawMessage `json:"old_payload,omitempty" bson:"old_payload"`
+	// OldPayloadStatus tells whether OldPayload was captured, so the missing state is never silent
+	OldPayloadStatus string `json:"old_payload_status" bson:"old_payload_status"`
+	// NewPayload is the request body of the action
+	NewPayload json.RawMessage `json:"new_payload,omitempty" bson:"new_payload"`
+	// Result is the response body of the action
+	Result    json.RawMessage `json:"result,omitempty" bson:"result"`
+	Status    int             `json:"status" bson:"status"`
+	Error     string          `json:"error,omitempty" bson:"error"`
+	RequestId string          `json:"request_id,omitempty" bson:"request_id"`
+	RemoteIp  string          `json:"remote_ip,omitempty" bson:"remote_ip"`
+	CreatedAt time.Time       `json:"created_at" bson:"created_at"`
+}
+
+// AuditFilter
+type AuditFilter struct {
+	ActorId    string
+	MerchantId string
+	// Action matches the full action name or the handler method name
+	Action string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// Match returns true if the record satisfies all filter conditions
+func (f *AuditFilter) Match(r *AuditRecord) bool {
+	if f.ActorId != "" && r.ActorId != f.ActorId {
+		return false
+	}
+
+	if f.MerchantId != "" && r.MerchantId != f.MerchantId {
+		return false
+	}
+
+	if f.Action != "" && !strings.EqualFold(r.Action, f.Action) && !strings.HasSuffix(strings.ToLower(r.Action), "."+strings.ToLower(f.Action)) {
+		return false
+	}
+
+	if !f.From.IsZero() && r.CreatedAt.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && r.CreatedAt.After(f.To) {
+		return false
+	}
+
+	return true
+}
+
+// AuditSink delivers the audit records to the external storage
+type AuditSink interface {
+	Write(record *AuditRecord) error
+}
+
+// AuditStore keeps the audit records for the search and forwards them to the sink
+type AuditStore interface {
+	Add(record *AuditRecord) error
+	// List returns the matching records, the newest first, and the total count of the matching records
+	List(filter *AuditFilter) (records []*AuditRecord, count int, err error)
+}
+
+// NewAuditRecord
+func NewAuditRecord() *AuditRecord {
+	return &AuditRecord{
+		Id:        bson.NewObjectId().Hex(),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// AuditPayload converts the body to the JSON value, the body which isn't JSON or exceeds maxSize is saved as a string
+func AuditPayload(body []byte, maxSize int) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if len(body) <= maxSize && json.Valid(body) {
+		return json.RawMessage(body)
+	}
+
+	if len(body) > maxSize {
+		body = body[:maxSize]
+	}
+
+	b, _ := json.Marshal(string(body))
+	return b
+}
+
+// AuditSnapshotFunc reads the state of the resource which is changed by the route
+type AuditSnapshotFunc func(ctx echo.Context) (interface{}, error)
+
+// AuditSnapshot is the route middleware which reads the state of the resource before the action,
+// the audit middleware of the group saves it to AuditRecord.OldPayload
+func AuditSnapshot(fn AuditSnapshotFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			snapshot := &AuditSnapshotResult{}
+			snapshot.Payload, snapshot.Err = fn(ctx)
+
+			ctx.Set(auditSnapshotContextKey, snapshot)
+			return next(ctx)
+		}
+	}
+}
+
+// AuditSnapshotResult is the state read by the snapshot or the error of the reading
+type AuditSnapshotResult struct {
+	Payload interface{}
+	Err     error
+}
+
+// ExtractAuditSnapshotContext returns nil if the route has no snapshot or it wasn't reached
+func ExtractAuditSnapshotContext(ctx echo.Context) *AuditSnapshotResult {
+	if snapshot, ok := ctx.Get(auditSnapshotContextKey).(*AuditSnapshotResult); ok {
+		return snapshot
+	}
+	return nil
+}
+
+type writerAuditSink struct {
+	mx sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterAuditSink writes the records as JSON lines
+func NewWriterAuditSink(w io.Writer) AuditSink {
+	return &writerAuditSink{w: w}
+}
+
+// NewFileAuditSink appends the records as JSON lines to the file
+func NewFileAuditSink(path string) (AuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterAuditSink(f), nil
+}
+
+func (s *writerAuditSink) Write(record *AuditRecord) error {
+	b, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+type brokerAuditSink struct {
+	once   sync.Once
+	err    error
+	broker broker.Broker
+	topic  string
+}
+
+// NewBrokerAuditSink publishes the records to the message broker topic, the broker is connected on the first record
+func NewBrokerAuditSink(b broker.Broker, topic string) AuditSink {
+	return &brokerAuditSink{broker: b, topic: topic}
+}
+
+func (s *brokerAuditSink) Write(record *AuditRecord) error {
+	s.once.Do(func() {
+		s.err = s.broker.Connect()
+	})
+
+	if s.err != nil {
+		return s.err
+	}
+
+	b, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	msg := &broker.Message{
+		Header: map[string]string{"action": record.Action, "actor_id": record.ActorId},
+		Body:   b,
+	}
+
+	return s.broker.Publish(s.topic, msg)
+}
+
+type nopAuditSink struct{}
+
+// NewNopAuditSink discards the records, they are available only in the audit store
+func NewNopAuditSink() AuditSink {
+	return nopAuditSink{}
+}
+
+func (nopAuditSink) Write(*AuditRecord) error {
+	return nil
+}
+
+type mongoAuditStore struct {
+	records mongoCollection
+	sink    AuditSink
+}
+
+// NewMongoAuditStore keeps the records in the storage shared by all instances and writes every record to the sink
+func NewMongoAuditStore(db *mgo.Database, sink AuditSink) (AuditStore, error) {
+	s := &mongoAuditStore{
+		records: mongoCollection{db: db, name: collectionAudit},
+		sink:    sink,
+	}
+
+	err := s.records.ensureIndexes(
+		mgo.Index{Key: []string{"-created_at"}},
+		mgo.Index{Key: []string{"actor_id", "-created_at"}},
+		mgo.Index{Key: []string{"merchant_id", "-created_at"}},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoAuditStore) Add(record *AuditRecord) error {
+	err := s.records.with(func(c *mgo.Collection) error {
+		return c.Insert(record)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.sink.Write(record)
+}
+
+func (s *mongoAuditStore) List(filter *AuditFilter) ([]*AuditRecord, int, error) {
+	query := bson.M{}
+
+	if filter.ActorId != "" {
+		query["actor_id"] = filter.ActorId
+	}
+
+	if filter.MerchantId != "" {
+		query["merchant_id"] = filter.MerchantId
+	}
+
+	if filter.Action != "" {
+		// the full action name or the handler method name, see AuditFilter.Match
+		query["action"] = bson.RegEx{Pattern: `(^|\.)` + regexp.QuoteMeta(filter.Action) + `$`, Options: "i"}
+	}
+
+	createdAt := bson.M{}
+
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+
+	if !filter.To.IsZero() {
+		createdAt["$lte"] = filter.To
+	}
+
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	records := make([]*AuditRecord, 0)
+	count := 0
+
+	err := s.records.with(func(c *mgo.Collection) error {
+		var err error
+		count, err = c.Find(query).Count()
+
+		if err != nil {
+			return err
+		}
+
+		q := c.Find(query).Sort("-created_at", "-_id").Skip(filter.Offset)
+
+		if filter.Limit > 0 {
+			q = q.Limit(filter.Limit)
+		}
+
+		return q.All(&records)
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, count, nil
+}
+
+type memoryAuditStore struct {
+	mx      sync.RWMutex
+	size    int
+	records []*AuditRecord
+	next    int
+	sink    AuditSink
+}
+
+// NewMemoryAuditStore keeps up to size latest records in memory and writes every record to the sink
+func NewMemoryAuditStore(size int, sink AuditSink) AuditStore {
+	return &memoryAuditStore{
+		size: size,
+		sink: sink,
+	}
+}
+
+func (s *memoryAuditStore) Add(record *AuditRecord) error {
+	if s.size > 0 {
+		s.mx.Lock()
+
+		if len(s.records) < s.size {
+			s.records = append(s.records, record)
+		} else {
+			s.records[s.next] = record
+		}
+
+		s.next = (s.next + 1) % s.size
+		s.mx.Unlock()
+	}
+
+	return s.sink.Write(record)
+}
+
+func (s *memoryAuditStore) List(filter *AuditFilter) ([]*AuditRecord, int, error) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	records := make([]*AuditRecord, 0)
+	count := 0
+
+	// walk from the newest record to the oldest one
+	for i := 0; i < len(s.records); i++ {
+		record := s.records[(s.next-1-i+2*len(s.records))%len(s.records)]
+
+		if !filter.Match(record) {
+			continue
+		}
+
+		if count >= filter.Offset && (filter.Limit <= 0 || len(records) < filter.Limit) {
+			records = append(records, record)
+		}
+
+		count++
+	}
+
+	return records, count, nil
+}
@@ -0,0 +1,144 @@
+package common
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// AuthCache keeps the results of the remote calls made to authenticate the request
+type AuthCache interface {
+	// Get returns the value if it exists and isn't expired
+	Get(key string) (value interface{}, ok bool)
+	// Set saves the value for ttl, the value is removed on invalidation of any of the tags
+	Set(key string, value interface{}, ttl time.Duration, tags ...string)
+	// Invalidate removes all values with the tag
+	Invalidate(tag string)
+}
+
+// AuthCacheTokenKey returns the key of the token introspection result, the token itself isn't kept in memory
+func AuthCacheTokenKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(hash[:])
+}
+
+// AuthCacheProfileKey
+func AuthCacheProfileKey(userId string) string {
+	return "profile:" + userId
+}
+
+// AuthCacheMerchantsKey
+func AuthCacheMerchantsKey(userId string) string {
+	return "merchants:" + userId
+}
+
+// AuthCacheUserTag marks all values of the user
+func AuthCacheUserTag(userId string) string {
+	return "user:" + userId
+}
+
+// AuthCacheMerchantTag marks the values which depend on the merchant users and roles
+func AuthCacheMerchantTag(merchantId string) string {
+	return "merchant:" + merchantId
+}
+
+type authCacheEntry struct {
+	key      string
+	value    interface{}
+	expireAt time.Time
+	tags     []string
+}
+
+type memoryAuthCache struct {
+	mx      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+	tags    map[string]map[string]struct{}
+}
+
+// NewMemoryAuthCache returns the in-memory cache with up to size values, the least recently used values are evicted first
+func NewMemoryAuthCache(size int) AuthCache {
+	return &memoryAuthCache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		tags:    make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *memoryAuthCache) Get(key string) (interface{}, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	el, ok := c.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*authCacheEntry)
+
+	if !time.Now().Before(entry.expireAt) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *memoryAuthCache) Set(key string, value interface{}, ttl time.Duration, tags ...string) {
+	if ttl <= 0 || c.size <= 0 {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	for c.lru.Len() >= c.size {
+		c.remove(c.lru.Back())
+	}
+
+	entry := &authCacheEntry{key: key, value: value, expireAt: time.Now().Add(ttl), tags: tags}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for _, tag := range tags {
+		if _, ok := c.tags[tag]; !ok {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+}
+
+func (c *memoryAuthCache) Invalidate(tag string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	for key := range c.tags[tag] {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+		}
+	}
+
+	delete(c.tags, tag)
+}
+
+func (c *memoryAuthCache) remove(el *list.Element) {
+	entry := c.lru.Remove(el).(*authCacheEntry)
+	delete(c.entries, entry.key)
+
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+// BackgroundTasks runs the work started by the requests which outlives them. The server waits for the tasks
+// at the shutdown, the context of the tasks is cancelled to tell them to stop.
+type BackgroundTasks struct {
+	mx     sync.Mutex
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed bool
+}
+
+// NewBackgroundTasks
+func NewBackgroundTasks() *BackgroundTasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackgroundTasks{ctx: ctx, cancel: cancel}
+}
+
+// Go runs the task, false is returned if the shutdown is already started and the task isn't run
+func (t *BackgroundTasks) Go(task func(stop context.Context)) bool {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.closed {
+		return false
+	}
+
+	t.wg.Add(1)
+
+	go func() {
+		defer t.wg.Done()
+		task(t.ctx)
+	}()
+
+	return true
+}
+
+// Shutdown tells the tasks to stop and waits for them until ctx is done
+func (t *BackgroundTasks) Shutdown(ctx context.Context) error {
+	t.mx.Lock()
+	t.closed = true
+	t.mx.Unlock()
+
+	t.cancel()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		t.wg.Wait()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
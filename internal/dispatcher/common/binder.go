@@ -2,7 +2,6 @@ package common
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"github.com/ProtocolONE/go-core/v2/pkg/logger"
 	"github.com/ProtocolONE/go-core/v2/pkg/provider"
@@ -516,10 +515,10 @@ func (b *ChangeProjectRequestBinder) Bind(i interface{}, ctx echo.Context) error
 	}
 
 	pReq := &billingpb.GetProjectRequest{ProjectId: projectId, MerchantId: projectReq.MerchantId}
-	pRsp, err := b.dispatch.Services.Billing.GetProject(context.Background(), pReq)
+	pRsp, err := b.dispatch.Services.Billing.GetProject(ctx.Request().Context(), pReq)
 
 	if err != nil {
-		b.L().Error(`Call billing server method "GetProject" failed`, logger.Args("error", err.Error(), "request", pReq))
+		RequestLogger(ctx, b.L()).Error(`Call billing server method "GetProject" failed`, logger.Args("error", err.Error(), "request", pReq))
 		return err
 	}
 
@@ -0,0 +1,366 @@
+package common
+
+import (
+	"fmt"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"sync"
+	"time"
+)
+
+const (
+	BulkRefundStatusProcessing = "processing"
+	BulkRefundStatusCompleted  = "completed"
+)
+
+// BulkRefundRow is the refund of the bulk submission and its result
+type BulkRefundRow struct {
+	// The unique identifier for the order.
+	OrderId string `json:"order_id" bson:"order_id" validate:"required,uuid"`
+	// The refund amount in the order currency.
+	Amount float64 `json:"amount" bson:"amount" validate:"required,gt=0"`
+	// The refund reason.
+	Reason string `json:"reason" bson:"reason" validate:"required,max=255"`
+	// The unique identifier for the created refund.
+	RefundId string `json:"refund_id,omitempty" bson:"refund_id" validate:"-"`
+	// The reason of the refund failure.
+	Error string `json:"error,omitempty" bson:"error" validate:"-"`
+}
+
+// BulkRefundJob is the bulk refund submission processed in the background
+type BulkRefundJob struct {
+	Id         string `json:"id" bson:"_id"`
+	MerchantId string `json:"merchant_id" bson:"merchant_id"`
+	CreatorId  string `json:"creator_id" bson:"creator_id"`
+	// The job status. Available values: processing, completed.
+	Status      string     `json:"status" bson:"status"`
+	Total       int        `json:"total" bson:"total"`
+	Processed   int        `json:"processed" bson:"processed"`
+	Succeeded   int        `json:"succeeded" bson:"succeeded"`
+	Failed      int        `json:"failed" bson:"failed"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at"`
+	// Rows are in the order of the submission, they are available in the result file only
+	Rows []*BulkRefundRow `json:"-" bson:"rows"`
+}
+
+// BulkRefundStore keeps the bulk refund jobs and the results of their rows
+type BulkRefundStore interface {
+	Create(job *BulkRefundJob) error
+	// Get returns nil if the merchant has no such job, the rows are copied
+	Get(merchantId, id string) (*BulkRefundJob, error)
+	// SetRowResult saves the result of the row, the job completes with its last row
+	SetRowResult(id string, index int, refundId, rowErr string) error
+	// Interrupt fails the rows without the result with rowErr and completes the job,
+	// it's called when the processing is stopped before the last row
+	Interrupt(id string, rowErr string) error
+	// InterruptStale interrupts the jobs which are still processed but were created before the time,
+	// it's called for the jobs left by the instances stopped without the graceful shutdown
+	InterruptStale(createdBefore time.Time, rowErr string) (count int, err error)
+}
+
+// NewBulkRefundJob
+func NewBulkRefundJob(merchantId, creatorId string, rows []*BulkRefundRow) *BulkRefundJob {
+	return &BulkRefundJob{
+		Id:         bson.NewObjectId().Hex(),
+		MerchantId: merchantId,
+		CreatorId:  creatorId,
+		Status:     BulkRefundStatusProcessing,
+		Total:      len(rows),
+		CreatedAt:  time.Now().UTC(),
+		Rows:       rows,
+	}
+}
+
+// mongoBulkRefundJob is the stored job, the completed job is removed after expireAt
+type mongoBulkRefundJob struct {
+	BulkRefundJob `bson:",inline"`
+	ExpireAt      *time.Time `bson:"expire_at,omitempty"`
+}
+
+type mongoBulkRefundStore struct {
+	ttl  time.Duration
+	jobs mongoCollection
+}
+
+// NewMongoBulkRefundStore returns the storage shared by all instances, so the job is available
+// from any instance and survives the restart. The completed jobs are removed after ttl.
+func NewMongoBulkRefundStore(db *mgo.Database, ttl time.Duration) (BulkRefundStore, error) {
+	s := &mongoBulkRefundStore{
+		ttl:  ttl,
+		jobs: mongoCollection{db: db, name: collectionBulkRefunds},
+	}
+
+	err := s.jobs.ensureIndexes(
+		mgo.Index{Key: []string{"merchant_id", "-created_at"}},
+		mgo.Index{Key: []string{"expire_at"}, ExpireAfter: time.Second},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoBulkRefundStore) Create(job *BulkRefundJob) error {
+	return s.jobs.with(func(c *mgo.Collection) error {
+		return c.Insert(&mongoBulkRefundJob{BulkRefundJob: *job})
+	})
+}
+
+func (s *mongoBulkRefundStore) Get(merchantId, id string) (*BulkRefundJob, error) {
+	job := &mongoBulkRefundJob{}
+	err := s.jobs.with(func(c *mgo.Collection) error {
+		return c.Find(bson.M{"_id": id, "merchant_id": merchantId}).One(job)
+	})
+
+	if isMongoNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job.BulkRefundJob, nil
+}
+
+func (s *mongoBulkRefundStore) SetRowResult(id string, index int, refundId, rowErr string) error {
+	inc := bson.M{"processed": 1}
+
+	if rowErr == "" {
+		inc["succeeded"] = 1
+	} else {
+		inc["failed"] = 1
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			fmt.Sprintf("rows.%d.refund_id", index): refundId,
+			fmt.Sprintf("rows.%d.error", index):     rowErr,
+		},
+		"$inc": inc,
+	}
+
+	return s.update(id, index, update)
+}
+
+func (s *mongoBulkRefundStore) Interrupt(id string, rowErr string) error {
+	job := &mongoBulkRefundJob{}
+	err := s.jobs.with(func(c *mgo.Collection) error {
+		return c.FindId(id).One(job)
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{}
+	count := 0
+
+	for i, row := range job.Rows {
+		if row.RefundId == "" && row.Error == "" {
+			set[fmt.Sprintf("rows.%d.error", i)] = rowErr
+			count++
+		}
+	}
+
+	// all rows have the result, but the instance stopped before the job was completed
+	if count == 0 {
+		return s.update(id, 0, bson.M{"$inc": bson.M{"processed": 0}})
+	}
+
+	return s.update(id, 0, bson.M{"$set": set, "$inc": bson.M{"processed": count, "failed": count}})
+}
+
+func (s *mongoBulkRefundStore) InterruptStale(createdBefore time.Time, rowErr string) (int, error) {
+	var jobs []*mongoBulkRefundJob
+	err := s.jobs.with(func(c *mgo.Collection) error {
+		query := bson.M{"status": BulkRefundStatusProcessing, "created_at": bson.M{"$lt": createdBefore}}
+		return c.Find(query).Select(bson.M{"_id": 1}).All(&jobs)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		if err := s.Interrupt(job.Id, rowErr); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(jobs), nil
+}
+
+// update applies the update to the job and completes the job if all rows are processed
+func (s *mongoBulkRefundStore) update(id string, index int, update bson.M) error {
+	job := &mongoBulkRefundJob{}
+	err := s.jobs.with(func(c *mgo.Collection) error {
+		change := mgo.Change{Update: update, ReturnNew: true}
+		_, err := c.Find(bson.M{"_id": id, "total": bson.M{"$gt": index}}).
+			Select(bson.M{"total": 1, "processed": 1}).
+			Apply(change, job)
+		return err
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	if err != nil || job.Processed < job.Total {
+		return err
+	}
+
+	now := time.Now().UTC()
+	expireAt := now.Add(s.ttl)
+
+	err = s.jobs.with(func(c *mgo.Collection) error {
+		return c.Update(
+			bson.M{"_id": id, "status": BulkRefundStatusProcessing},
+			bson.M{"$set": bson.M{"status": BulkRefundStatusCompleted, "completed_at": now, "expire_at": expireAt}},
+		)
+	})
+
+	// the job is completed already by the concurrent update
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+type memoryBulkRefundStore struct {
+	mx   sync.RWMutex
+	ttl  time.Duration
+	jobs map[string]*BulkRefundJob
+}
+
+// NewMemoryBulkRefundStore returns the in-memory storage, the completed jobs are forgotten after ttl
+func NewMemoryBulkRefundStore(ttl time.Duration) BulkRefundStore {
+	return &memoryBulkRefundStore{
+		ttl:  ttl,
+		jobs: make(map[string]*BulkRefundJob),
+	}
+}
+
+func (s *memoryBulkRefundStore) Create(job *BulkRefundJob) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now()
+
+	for id, j := range s.jobs {
+		if j.CompletedAt != nil && now.Sub(*j.CompletedAt) >= s.ttl {
+			delete(s.jobs, id)
+		}
+	}
+
+	s.jobs[job.Id] = copyBulkRefundJob(job)
+	return nil
+}
+
+func (s *memoryBulkRefundStore) Get(merchantId, id string) (*BulkRefundJob, error) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	job, ok := s.jobs[id]
+
+	if !ok || job.MerchantId != merchantId {
+		return nil, nil
+	}
+
+	return copyBulkRefundJob(job), nil
+}
+
+func (s *memoryBulkRefundStore) SetRowResult(id string, index int, refundId, rowErr string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	job, ok := s.jobs[id]
+
+	if !ok || index < 0 || index >= len(job.Rows) {
+		return nil
+	}
+
+	job.Rows[index].RefundId = refundId
+	job.Rows[index].Error = rowErr
+	job.Processed++
+
+	if rowErr == "" {
+		job.Succeeded++
+	} else {
+		job.Failed++
+	}
+
+	if job.Processed == job.Total {
+		now := time.Now().UTC()
+		job.Status = BulkRefundStatusCompleted
+		job.CompletedAt = &now
+	}
+
+	return nil
+}
+
+func (s *memoryBulkRefundStore) Interrupt(id string, rowErr string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	job, ok := s.jobs[id]
+
+	if !ok || job.Status == BulkRefundStatusCompleted {
+		return nil
+	}
+
+	for _, row := range job.Rows {
+		if row.RefundId == "" && row.Error == "" {
+			row.Error = rowErr
+			job.Processed++
+			job.Failed++
+		}
+	}
+
+	now := time.Now().UTC()
+	job.Status = BulkRefundStatusCompleted
+	job.CompletedAt = &now
+
+	return nil
+}
+
+func (s *memoryBulkRefundStore) InterruptStale(createdBefore time.Time, rowErr string) (int, error) {
+	s.mx.RLock()
+	var ids []string
+
+	for id, job := range s.jobs {
+		if job.Status == BulkRefundStatusProcessing && job.CreatedAt.Before(createdBefore) {
+			ids = append(ids, id)
+		}
+	}
+
+	s.mx.RUnlock()
+
+	for _, id := range ids {
+		if err := s.Interrupt(id, rowErr); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(ids), nil
+}
+
+func copyBulkRefundJob(job *BulkRefundJob) *BulkRefundJob {
+	cp := *job
+	cp.Rows = make([]*BulkRefundRow, len(job.Rows))
+
+	for i, row := range job.Rows {
+		r := *row
+		cp.Rows[i] = &r
+	}
+
+	return &cp
+}
@@ -22,7 +22,7 @@ func CheckProjectAuthRequestSignature(dispatch HandlerSet, ctx echo.Context, pro
 
 	rsp, err := dispatch.Services.Billing.CheckProjectRequestSignature(ctx.Request().Context(), req)
 	if err != nil {
-		dispatch.AwareSet.L().Error(InternalErrorTemplate, logger.Args("err", err.Error()))
+		RequestLogger(ctx, dispatch.AwareSet.L()).Error(InternalErrorTemplate, logger.Args("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, ErrorUnknown)
 	}
 
@@ -32,28 +32,34 @@ func CheckProjectAuthRequestSignature(dispatch HandlerSet, ctx echo.Context, pro
 	return nil
 }
 
-// GetValidationError
-func GetValidationError(err error) (rspErr *billingpb.ResponseErrorMessage) {
-
-	vErr := err.(validator.ValidationErrors)[0] // TODO: possible out of range
-	val, ok := ValidationErrors[vErr.Field()]
-
-	if ok {
-		rspErr = val
-	} else {
-		val, ok := ValidationNamespaceErrors[vErr.StructNamespace()]
-
-		if ok {
-			rspErr = val
-		} else {
-			if vErr.Tag() == RequestParameterZipUsa {
-				rspErr = ErrorMessageIncorrectZip
-			} else {
-				rspErr = ErrorValidationFailed
-			}
+// GetValidationError returns the error for the first failed field. Shared error templates are copied,
+// the list of all failed fields is sent in the error envelope, see NewValidationHTTPError.
+func GetValidationError(err error) *billingpb.ResponseErrorMessage {
+	vErrs, ok := err.(validator.ValidationErrors)
+
+	if !ok || len(vErrs) == 0 {
+		return NewValidationError(err.Error())
+	}
+
+	vErr := vErrs[0]
+	tpl, ok := ValidationErrors[vErr.StructField()]
+
+	if !ok {
+		tpl, ok = ValidationNamespaceErrors[vErr.StructNamespace()]
+	}
+
+	if !ok {
+		tpl = ErrorValidationFailed
+
+		if vErr.Tag() == RequestParameterZipUsa {
+			tpl = ErrorMessageIncorrectZip
 		}
 	}
 
-	rspErr.Details = fmt.Sprintf(ErrorMessageMask, vErr.Field(), vErr.Tag())
-	return rspErr
+	return NewManagementApiResponseError(tpl.Code, tpl.Message, fmt.Sprintf(ErrorMessageMask, vErr.StructField(), vErr.Tag()))
+}
+
+// NewValidationHTTPError returns 400 error, the error envelope lists all failed fields
+func NewValidationHTTPError(err error) *echo.HTTPError {
+	return echo.NewHTTPError(http.StatusBadRequest, GetValidationError(err)).SetInternal(err)
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/paysuper/paysuper-proto/go/taxpb"
 	"gopkg.in/go-playground/validator.v9"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 const (
@@ -24,6 +26,20 @@ const (
 	MerchantS2SGroupPath     = "/merchant/s2s/api/v1"
 	NoAuthGroupPath          = "/api/v1"
 	WebHookGroupPath         = "/webhook"
+	LivenessPath             = "/healthz"
+	ReadinessPath            = "/readyz"
+)
+
+var (
+	// Group names for labels of metrics, keep longest paths first
+	routeGroupNames = []struct{ path, name string }{
+		{AuthProjectGroupPath, "auth_project"},
+		{AuthUserGroupPath, "auth_user"},
+		{SystemUserGroupPath, "system_user"},
+		{MerchantS2SGroupPath, "merchant_s2s"},
+		{WebHookGroupPath, "webhook"},
+		{NoAuthGroupPath, "common"},
+	}
 )
 
 // Cursor
@@ -32,6 +48,16 @@ type Cursor struct {
 	Sort          []string
 }
 
+// RouteGroupName returns name of the route group by the route path
+func RouteGroupName(path string) string {
+	for _, group := range routeGroupNames {
+		if strings.HasPrefix(path, group.path) {
+			return group.name
+		}
+	}
+	return "other"
+}
+
 // ExtractUserContext
 func ExtractUserContext(ctx echo.Context) *AuthUser {
 	if user, ok := ctx.Get("user").(*AuthUser); ok {
@@ -56,6 +82,23 @@ func ExtractCursorContext(ctx echo.Context) *Cursor {
 	return &Cursor{}
 }
 
+// ExtractPageCursorContext returns the cursor of the listing page, nil if the page is requested without the cursor
+func ExtractPageCursorContext(ctx echo.Context) *PageCursor {
+	if cursor, ok := ctx.Get("pageCursor").(*PageCursor); ok {
+		return cursor
+	}
+	return nil
+}
+
+// ExtractRawQueryContext returns the query sent by the client, it differs from the request query
+// when the query is taken from the page cursor
+func ExtractRawQueryContext(ctx echo.Context) url.Values {
+	if query, ok := ctx.Get("rawQuery").(url.Values); ok {
+		return query
+	}
+	return ctx.Request().URL.Query()
+}
+
 // ExtractBinderContext
 func ExtractBinderContext(ctx echo.Context) echo.Binder {
 	if binder, ok := ctx.Get("binder").(echo.Binder); ok {
@@ -64,6 +107,14 @@ func ExtractBinderContext(ctx echo.Context) echo.Binder {
 	return nil
 }
 
+// ExtractLogFieldsContext
+func ExtractLogFieldsContext(ctx echo.Context) logger.Fields {
+	if fields, ok := ctx.Get("logFields").(logger.Fields); ok {
+		return fields
+	}
+	return nil
+}
+
 // SetUserContext
 func SetUserContext(ctx echo.Context, user *AuthUser) {
 	ctx.Set("user", user)
@@ -79,11 +130,34 @@ func SetCursorContext(ctx echo.Context, cursor *Cursor) {
 	ctx.Set("cursor", cursor)
 }
 
+// SetPageCursorContext
+func SetPageCursorContext(ctx echo.Context, cursor *PageCursor) {
+	ctx.Set("pageCursor", cursor)
+}
+
+// SetRawQueryContext
+func SetRawQueryContext(ctx echo.Context, query url.Values) {
+	ctx.Set("rawQuery", query)
+}
+
 // SetBinder
 func SetBinder(ctx echo.Context, binder echo.Binder) {
 	ctx.Set("binder", binder)
 }
 
+// AddLogFieldsContext appends the fields attached to every log entry written with RequestLogger
+func AddLogFieldsContext(ctx echo.Context, fields logger.Fields) {
+	current := ExtractLogFieldsContext(ctx)
+	merged := make(logger.Fields, len(current)+len(fields))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	ctx.Set("logFields", merged)
+}
+
 // Groups
 type Groups struct {
 	AuthProject *echo.Group
@@ -94,6 +168,10 @@ type Groups struct {
 	SystemUser  *echo.Group
 	// Group for process S2S requests from merchant's server to PaySuper's server
 	MerchantS2S *echo.Group
+	// Idempotency middleware for the routes of the groups without it, the route must authenticate the request before it
+	Idempotency echo.MiddlewareFunc
+	// S2SSignature authenticates the request signed with the project secret or API key for the routes out of MerchantS2S group
+	S2SSignature echo.MiddlewareFunc
 }
 
 // Handler
@@ -131,14 +209,14 @@ func (h HandlerSet) BindAndValidate(req interface{}, ctx echo.Context) *echo.HTT
 		return echo.NewHTTPError(http.StatusBadRequest, ErrorRequestParamsIncorrect)
 	}
 	if err := h.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, GetValidationError(err))
+		return NewValidationHTTPError(err)
 	}
 	return nil
 }
 
 // SrvCallHandler returns error if present, otherwise response as JSON with 200 OK
-func (h HandlerSet) SrvCallHandler(req interface{}, err error, name, method string) *echo.HTTPError {
-	h.AwareSet.L().Error(billingpb.ErrorGrpcServiceCallFailed,
+func (h HandlerSet) SrvCallHandler(ctx echo.Context, req interface{}, err error, name, method string) *echo.HTTPError {
+	RequestLogger(ctx, h.AwareSet.L()).Error(billingpb.ErrorGrpcServiceCallFailed,
 		logger.PairArgs(
 			ErrorFieldService, name,
 			ErrorFieldMethod, method,
@@ -156,6 +234,23 @@ type AuthUser struct {
 	Role       string
 	MerchantId string
 	ProfileId  string
+	// Project authenticated by S2S request
+	ProjectId string
+	// Locale of the user profile, used for the error messages
+	Locale string
+	// API key and its scopes if the S2S request is authenticated by the key instead of the project secret
+	ApiKeyId string
+	Scopes   []string
+}
+
+// HasScope
+func (u *AuthUser) HasScope(scope string) bool {
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
 func (h *HandlerSet) RequestReportFile(
@@ -182,13 +277,13 @@ func (h *HandlerSet) RequestReportFile(
 	}
 
 	if err = h.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, GetValidationError(err))
+		return NewValidationHTTPError(err)
 	}
 
 	res, err := h.Services.Reporter.CreateFile(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.SrvCallHandler(req, err, reporterpb.ServiceName, "CreateFile")
+		return h.SrvCallHandler(ctx, req, err, reporterpb.ServiceName, "CreateFile")
 	}
 
 	if res.Status != http.StatusOK {
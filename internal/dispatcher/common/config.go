@@ -1,5 +1,7 @@
 package common
 
+import "time"
+
 type Auth1 struct {
 	Issuer       string `envconfig:"AUTH1_ISSUER" default:"https://dev-auth1.tst.protocol.one"`
 	ClientId     string `envconfig:"AUTH1_CLIENTID" required:"true"`
@@ -45,4 +47,91 @@ type Config struct {
 
 	AllowOrigin string `envconfig:"ALLOW_ORIGIN" default:"*"`
 	HttpScheme  string `envconfig:"HTTP_SCHEME" default:"https"`
+
+	// Mongo database of the state which all instances must share, e.g. the project API keys
+	MongoDsn string `envconfig:"MONGO_DSN" required:"true"`
+
+	IdempotencyKeyTtl time.Duration `envconfig:"IDEMPOTENCY_KEY_TTL" default:"24h"`
+	// The key stays reserved while the first request is processed, the key of the lost request is released after it
+	IdempotencyKeyLease time.Duration `envconfig:"IDEMPOTENCY_KEY_LEASE" default:"1m"`
+
+	// Requests per second and burst size for every route group, zero rate disables the limit
+	RateLimitAuthUser         float64 `envconfig:"RATE_LIMIT_AUTH_USER" default:"20"`
+	RateLimitAuthUserBurst    int     `envconfig:"RATE_LIMIT_AUTH_USER_BURST" default:"40"`
+	RateLimitSystemUser       float64 `envconfig:"RATE_LIMIT_SYSTEM_USER" default:"20"`
+	RateLimitSystemUserBurst  int     `envconfig:"RATE_LIMIT_SYSTEM_USER_BURST" default:"40"`
+	RateLimitMerchantS2S      float64 `envconfig:"RATE_LIMIT_MERCHANT_S2S" default:"10"`
+	RateLimitMerchantS2SBurst int     `envconfig:"RATE_LIMIT_MERCHANT_S2S_BURST" default:"20"`
+	RateLimitCommon           float64 `envconfig:"RATE_LIMIT_COMMON" default:"10"`
+	RateLimitCommonBurst      int     `envconfig:"RATE_LIMIT_COMMON_BURST" default:"20"`
+	// Comma separated IPs and CIDRs of the proxies in front of the server. X-Forwarded-For is used to limit
+	// the anonymous requests only if it's set by these proxies, otherwise the requests are limited by the peer address.
+	RateLimitTrustedProxies string `envconfig:"RATE_LIMIT_TRUSTED_PROXIES"`
+
+	// Timeout of every dependency check of the readiness probe, checks are run concurrently
+	HealthCheckTimeout time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+
+	// Allowed difference between the signed request timestamp and the server time
+	S2SSignatureMaxSkew time.Duration `envconfig:"S2S_SIGNATURE_MAX_SKEW" default:"5m"`
+	// Comma separated projects which must sign their requests, Basic auth is refused for them. "*" disables
+	// Basic auth for all projects. Basic auth stays available for the projects which aren't listed.
+	S2SBasicAuthDisabledProjects string `envconfig:"S2S_BASIC_AUTH_DISABLED_PROJECTS"`
+	// The old API key remains valid for the period after rotation
+	ApiKeyRotationGracePeriod time.Duration `envconfig:"API_KEY_ROTATION_GRACE_PERIOD" default:"24h"`
+
+	// Cache of the token introspection, user profile and merchants of the user, zero ttl disables the cache
+	AuthCacheTtl time.Duration `envconfig:"AUTH_CACHE_TTL" default:"30s"`
+	// Time to remember the invalid tokens
+	AuthCacheNegativeTtl time.Duration `envconfig:"AUTH_CACHE_NEGATIVE_TTL" default:"5s"`
+	AuthCacheSize        int           `envconfig:"AUTH_CACHE_SIZE" default:"10000"`
+
+	// Casbin policy is enforced by the remote casbin service or the embedded enforcer
+	CasbinMode string `envconfig:"CASBIN_MODE" default:"remote"`
+	// Shadow mode evaluates the policy by both enforcers and logs the disagreements, the decision is made by CasbinMode
+	CasbinShadow bool `envconfig:"CASBIN_SHADOW"`
+	// Interval to check the policy file and remote policy for changes
+	CasbinReloadInterval time.Duration `envconfig:"CASBIN_RELOAD_INTERVAL" default:"1m"`
+	// Paths relative to the work directory
+	CasbinModelPath  string `envconfig:"CASBIN_MODEL_PATH" default:"assets/casbin_model.conf"`
+	CasbinPolicyPath string `envconfig:"CASBIN_POLICY_PATH" default:"assets/policy.conf"`
+
+	// Audit of the system user actions is written to the sink: stdout, file, broker or none
+	AuditSink string `envconfig:"AUDIT_SINK" default:"stdout"`
+	// File path for the file sink and topic for the broker sink
+	AuditFilePath    string `envconfig:"AUDIT_FILE_PATH" default:"audit.log"`
+	AuditBrokerTopic string `envconfig:"AUDIT_BROKER_TOPIC" default:"management-api.audit"`
+	// Number of the latest records kept by the in-memory audit store used in tests
+	AuditStoreSize int `envconfig:"AUDIT_STORE_SIZE" default:"10000"`
+	// Payloads larger than the size are truncated
+	AuditMaxPayloadSize int `envconfig:"AUDIT_MAX_PAYLOAD_SIZE" default:"65536"`
+
+	// Comma separated actions of the system users which run only after the approval of the second system user,
+	// e.g. OnboardingRoute.changeMerchantStatus,PaymentCostRoute.setAllPaymentChannelCostMerchant.
+	// The action is the handler name or the method with the path template, e.g. "PUT /system/api/v1/merchants/:merchant_id/change-status"
+	ApprovalRoutes string `envconfig:"APPROVAL_ROUTES"`
+	// Refunds with the smaller amount don't need the approval even if OrderRoute.createRefund is in ApprovalRoutes
+	ApprovalRefundMinAmount float64 `envconfig:"APPROVAL_REFUND_MIN_AMOUNT" default:"1000"`
+
+	// Sessions not seen for the period and revocations older than the period are forgotten,
+	// the period must be longer than the lifetime of the access token
+	SessionTtl time.Duration `envconfig:"SESSION_TTL" default:"24h"`
+
+	// Secret to sign the page cursors, all instances must share it
+	CursorSecret string        `envconfig:"CURSOR_SECRET" required:"true"`
+	CursorTtl    time.Duration `envconfig:"CURSOR_TTL" default:"24h"`
+
+	// Number of the bulk refund rows processed concurrently by one job
+	BulkRefundWorkers int `envconfig:"BULK_REFUND_WORKERS" default:"8"`
+	BulkRefundMaxRows int `envconfig:"BULK_REFUND_MAX_ROWS" default:"10000"`
+	// Completed bulk refund jobs and their results are forgotten after the period
+	BulkRefundJobTtl time.Duration `envconfig:"BULK_REFUND_JOB_TTL" default:"72h"`
+	// The rows not processed within the period after the submission are failed
+	BulkRefundJobTimeout time.Duration `envconfig:"BULK_REFUND_JOB_TIMEOUT" default:"1h"`
+
+	// Period after the order creation in which the logs of the order are searched, the window query parameter
+	// of the order timeline can't exceed OrderTimelineWindowMax
+	OrderTimelineWindow    time.Duration `envconfig:"ORDER_TIMELINE_WINDOW" default:"168h"`
+	OrderTimelineWindowMax time.Duration `envconfig:"ORDER_TIMELINE_WINDOW_MAX" default:"720h"`
+	// Maximum number of the log events read from one CloudWatch log group for the order
+	OrderTimelineMaxEvents int `envconfig:"ORDER_TIMELINE_MAX_EVENTS" default:"1000"`
 }
@@ -33,6 +33,8 @@ const (
 	RequestParameterPaymentMethodId          = "method_id"
 	RequestParameterOrderId                  = "order_id"
 	RequestParameterRefundId                 = "refund_id"
+	RequestParameterInvoiceId                = "invoice_id"
+	RequestParameterJobId                    = "job_id"
 	RequestParameterNotificationId           = "notification_id"
 	RequestParameterUserId                   = "user"
 	RequestParameterLimit                    = "limit"
@@ -58,6 +60,9 @@ const (
 	RequestParameterNotifyEmails             = "notify_emails"
 	RequestParameterIsProductsCheckout       = "is_products_checkout"
 	RequestParameterSecretKey                = "secret_key"
+	RequestParameterApiKeyId                 = "api_key_id"
+	RequestParameterApprovalId               = "approval_id"
+	RequestParameterSessionId                = "session_id"
 	RequestParameterSignatureRequired        = "signature_required"
 	RequestParameterSendNotifyEmail          = "send_notify_email"
 	RequestParameterUrlCheckAccount          = "url_check_account"
@@ -70,6 +75,7 @@ const (
 	RequestParameterUrlRefundPayment         = "url_refund_payment"
 	RequestParameterStatus                   = "status"
 	RequestAuthorizationTokenRegex           = "Bearer ([A-z0-9_.-]{10,})"
+	RequestIdRegex                           = "^[a-zA-Z0-9_.:-]{1,128}$"
 	RequestParameterZipUsa                   = "zip_usa"
 	RequestParameterRateId                   = "rate_id"
 	RequestParameterReceiptId                = "receipt_id"
@@ -128,9 +134,33 @@ const (
 	ErrorMessageMask = "field validation for '%s' failed on the '%s' tag"
 
 	HeaderAcceptLanguage      = "Accept-Language"
+	HeaderContentLanguage     = "Content-Language"
 	HeaderUserAgent           = "User-Agent"
 	HeaderXApiSignatureHeader = "X-API-SIGNATURE"
 	HeaderReferer             = "referer"
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	HeaderRetryAfter          = "Retry-After"
+	HeaderXRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderXRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderXRateLimitReset     = "X-RateLimit-Reset"
+	HeaderXS2SProjectId       = "X-PS-Project-Id"
+	HeaderXS2STimestamp       = "X-PS-Timestamp"
+	HeaderXS2SNonce           = "X-PS-Nonce"
+	HeaderXS2SSignature       = "X-PS-Signature"
+	HeaderXS2SApiKeyId        = "X-PS-Api-Key-Id"
+	HeaderXMerchantId         = "X-Merchant-Id"
+
+	ErrorTemplateName = "error.html"
+
+	IdempotencyKeyMaxLength = 255
+	S2SNonceMaxLength       = 128
+
+	CasbinModeRemote = "remote"
+	CasbinModeLocal  = "local"
+
+	// Disables Basic auth with the project secret for all projects
+	S2SBasicAuthAllProjects = "*"
 
 	// EnvironmentProduction        = "prod"
 	CustomerTokenCookiesName = "_ps_ctkn"
@@ -154,6 +184,9 @@ const (
 	ErrorFieldMethod  = "method"
 	ErrorFieldRequest = "request"
 
+	LogFieldTraceId   = "trace_id"
+	LogFieldRequestId = "request_id"
+
 	InternalErrorTemplate = "internal error"
 	ServiceErrorTemplate  = "service error"
 	BindingErrorTemplate  = "bind error"
@@ -400,7 +433,8 @@ var (
 
 	TestStubImplementMe = "implement me!"
 
-	TokenRegex = regexp.MustCompile(RequestAuthorizationTokenRegex)
+	TokenRegex     = regexp.MustCompile(RequestAuthorizationTokenRegex)
+	RequestIdMatch = regexp.MustCompile(RequestIdRegex)
 )
 
 func LogSrvCallFailedGRPC(log logger.Logger, err error, name, method string, req interface{}) {
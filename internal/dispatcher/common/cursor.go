@@ -0,0 +1,286 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	QueryParameterNameCursor = "cursor"
+
+	HeaderLink = "Link"
+)
+
+var (
+	errCursorMalformed = errors.New("cursor is malformed")
+	errCursorSignature = errors.New("cursor signature mismatch")
+	errCursorExpired   = errors.New("cursor is expired")
+)
+
+// PageCursor is the signed position in the listing. It keeps the whole query of the listing,
+// so the next pages are requested with the same filters, sort and creation time anchor,
+// and the unique identifier of the row on the page border.
+type PageCursor struct {
+	Path  string     `json:"p"`
+	Query url.Values `json:"q"`
+	// The next page starts after the row
+	After string `json:"a,omitempty"`
+	// The previous page ends before the row
+	Before   string `json:"b,omitempty"`
+	ExpireAt int64  `json:"e"`
+}
+
+// CursorPage is added to the listing response, the cursors are empty on the first and the last pages
+type CursorPage struct {
+	// The cursor of the next page, pass it in the cursor query parameter instead of the other parameters.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// The cursor of the previous page.
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+func cursorSign(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// EncodePageCursor returns the opaque cursor: the payload and its HMAC-SHA256 signature
+func EncodePageCursor(cfg *Config, cursor *PageCursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+
+	if err != nil {
+		return "", err
+	}
+
+	sign := cursorSign([]byte(cfg.CursorSecret), payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sign), nil
+}
+
+// DecodePageCursor checks the signature and the expiration time of the cursor
+func DecodePageCursor(cfg *Config, value string) (*PageCursor, error) {
+	parts := strings.Split(value, ".")
+
+	if len(parts) != 2 {
+		return nil, errCursorMalformed
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+
+	if err != nil {
+		return nil, errCursorMalformed
+	}
+
+	sign, err := base64.RawURLEncoding.DecodeString(parts[1])
+
+	if err != nil {
+		return nil, errCursorMalformed
+	}
+
+	if !hmac.Equal(sign, cursorSign([]byte(cfg.CursorSecret), payload)) {
+		return nil, errCursorSignature
+	}
+
+	cursor := &PageCursor{}
+
+	if err := json.Unmarshal(payload, cursor); err != nil {
+		return nil, errCursorMalformed
+	}
+
+	if time.Now().Unix() > cursor.ExpireAt {
+		return nil, errCursorExpired
+	}
+
+	return cursor, nil
+}
+
+// SetQueryParams replaces the query of the request, the values cached by the context are replaced too.
+// The original query is kept for the signature check.
+func SetQueryParams(ctx echo.Context, query url.Values) {
+	SetRawQueryContext(ctx, ctx.Request().URL.Query())
+	params := ctx.QueryParams()
+
+	for key := range params {
+		delete(params, key)
+	}
+
+	for key, values := range query {
+		params[key] = values
+	}
+
+	ctx.Request().URL.RawQuery = query.Encode()
+}
+
+// PageSeek is the page requested by the client and the wider window of rows requested from the service.
+// The services page by offset only, so the rows added or removed since the previous page shift the offset.
+// The window is searched for the border row of the cursor, and the page is cut right next to it.
+type PageSeek struct {
+	limit  int64
+	offset int64
+	after  string
+	before string
+	// The limit of the service request
+	Limit int64
+	// The offset of the service request
+	Offset int64
+}
+
+// NewPageSeek returns the window of the page. The border row is found if it's shifted by the page size at most,
+// otherwise the page is taken by the offset.
+func NewPageSeek(ctx echo.Context, cfg *Config, limit, offset int64) *PageSeek {
+	s := &PageSeek{limit: limit, offset: offset, Limit: limit, Offset: offset}
+	cursor := ExtractPageCursorContext(ctx)
+
+	if cursor == nil || limit <= 0 {
+		return s
+	}
+
+	shift := limit
+
+	if limit+shift+1 > int64(cfg.LimitMax) {
+		shift = int64(cfg.LimitMax) - limit - 1
+	}
+
+	if shift <= 0 {
+		return s
+	}
+
+	if cursor.After != "" && offset > 0 {
+		s.after = cursor.After
+		s.Offset = offset - 1
+		s.Limit = limit + shift + 1
+	} else if cursor.Before != "" {
+		s.before = cursor.Before
+		s.Limit = limit + shift + 1
+	}
+
+	return s
+}
+
+// Page returns the bounds of the page in the n rows returned by the service and the cursors of the pages
+// next to it. The id returns the unique identifier of the row.
+func (s *PageSeek) Page(ctx echo.Context, cfg *Config, count int64, n int, id func(i int) string) (int, int, *CursorPage) {
+	start, end := 0, n
+
+	if s.after != "" {
+		// the row after the border if the border row isn't found
+		start = int(s.offset - s.Offset)
+
+		for i := 0; i < n; i++ {
+			if id(i) == s.after {
+				start = i + 1
+				break
+			}
+		}
+
+		end = start + int(s.limit)
+	} else if s.before != "" {
+		end = int(s.limit)
+
+		for i := 0; i < n; i++ {
+			if id(i) == s.before {
+				start, end = i-int(s.limit), i
+				break
+			}
+		}
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if start > n {
+		start = n
+	}
+
+	if end > n {
+		end = n
+	}
+
+	if end < start {
+		end = start
+	}
+
+	var first, last string
+
+	if end > start {
+		first, last = id(start), id(end-1)
+	}
+
+	return start, end, Paginate(ctx, cfg, s.limit, s.Offset+int64(start), count, first, last)
+}
+
+// Paginate returns the cursors of the pages next to the current one and sets them to the Link header (RFC 5988).
+// The cursors repeat the query of the current request with the shifted offset, first and last are the identifiers
+// of the rows on the page borders.
+func Paginate(ctx echo.Context, cfg *Config, limit, offset, count int64, first, last string) *CursorPage {
+	page := &CursorPage{}
+
+	if limit <= 0 {
+		return page
+	}
+
+	var links []string
+	cursor := func(offset int64, after, before, rel string) string {
+		query := url.Values{}
+
+		for key, values := range ctx.QueryParams() {
+			if key != QueryParameterNameCursor {
+				query[key] = values
+			}
+		}
+
+		query.Set(QueryParameterNameLimit, strconv.FormatInt(limit, 10))
+		query.Set(QueryParameterNameOffset, strconv.FormatInt(offset, 10))
+
+		value, err := EncodePageCursor(cfg, &PageCursor{
+			Path:     ctx.Request().URL.Path,
+			Query:    query,
+			After:    after,
+			Before:   before,
+			ExpireAt: time.Now().Add(cfg.CursorTtl).Unix(),
+		})
+
+		if err != nil {
+			return ""
+		}
+
+		link := url.URL{
+			Scheme:   ctx.Scheme(),
+			Host:     ctx.Request().Host,
+			Path:     ctx.Request().URL.Path,
+			RawQuery: url.Values{QueryParameterNameCursor: {value}}.Encode(),
+		}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel))
+
+		return value
+	}
+
+	if offset+limit < count {
+		page.NextCursor = cursor(offset+limit, last, "", "next")
+	}
+
+	if offset > 0 {
+		prev := offset - limit
+
+		if prev < 0 {
+			prev = 0
+		}
+
+		page.PrevCursor = cursor(prev, "", first, "prev")
+	}
+
+	if len(links) > 0 {
+		ctx.Response().Header().Set(HeaderLink, strings.Join(links, ", "))
+	}
+
+	return page
+}
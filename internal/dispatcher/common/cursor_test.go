@@ -0,0 +1,345 @@
+package common
+
+import (
+	"encoding/base64"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	cursorTestPath = "/admin/api/v1/order"
+)
+
+func cursorTestConfig() *Config {
+	return &Config{
+		CursorSecret: "cursor_secret",
+		CursorTtl:    time.Hour,
+		LimitMax:     1000,
+	}
+}
+
+func cursorTestContext(target string, cursor *PageCursor) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+
+	if cursor != nil {
+		SetPageCursorContext(ctx, cursor)
+	}
+
+	return ctx, rec
+}
+
+func cursorTestIds(ids ...string) func(i int) string {
+	return func(i int) string {
+		return ids[i]
+	}
+}
+
+func TestPageCursor_EncodeDecode(t *testing.T) {
+	cfg := cursorTestConfig()
+	cursor := &PageCursor{
+		Path:     cursorTestPath,
+		Query:    url.Values{"status": {"processed"}, QueryParameterNameOffset: {"10"}},
+		After:    "o10",
+		ExpireAt: time.Now().Add(time.Minute).Unix(),
+	}
+
+	value, err := EncodePageCursor(cfg, cursor)
+	assert.NoError(t, err)
+
+	decoded, err := DecodePageCursor(cfg, value)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestDecodePageCursor_Error(t *testing.T) {
+	cfg := cursorTestConfig()
+	cursor := func(expireAt time.Time) string {
+		value, err := EncodePageCursor(cfg, &PageCursor{Path: cursorTestPath, After: "o10", ExpireAt: expireAt.Unix()})
+		assert.NoError(t, err)
+		return value
+	}
+	valid := cursor(time.Now().Add(time.Minute))
+	parts := strings.Split(valid, ".")
+	// the payload is replaced, the signature of the original payload is kept
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"p":"/admin/api/v1/order","a":"o20","e":4102444800}`)) + "." + parts[1]
+
+	anotherSecret := cursorTestConfig()
+	anotherSecret.CursorSecret = "another_secret"
+
+	tests := []struct {
+		name  string
+		cfg   *Config
+		value string
+		err   error
+	}{
+		{name: "tampered payload", cfg: cfg, value: tampered, err: errCursorSignature},
+		{name: "another secret", cfg: anotherSecret, value: valid, err: errCursorSignature},
+		{name: "truncated signature", cfg: cfg, value: parts[0] + "." + parts[1][:10], err: errCursorSignature},
+		{name: "expired", cfg: cfg, value: cursor(time.Now().Add(-time.Minute)), err: errCursorExpired},
+		{name: "no signature", cfg: cfg, value: parts[0], err: errCursorMalformed},
+		{name: "too many parts", cfg: cfg, value: valid + ".abc", err: errCursorMalformed},
+		{name: "payload isn't base64", cfg: cfg, value: "!!!." + parts[1], err: errCursorMalformed},
+		{name: "signature isn't base64", cfg: cfg, value: parts[0] + ".!!!", err: errCursorMalformed},
+		{name: "empty", cfg: cfg, value: "", err: errCursorMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodePageCursor(tt.cfg, tt.value)
+			assert.Equal(t, tt.err, err)
+			assert.Nil(t, decoded)
+		})
+	}
+}
+
+func TestNewPageSeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		cursor   *PageCursor
+		limit    int64
+		offset   int64
+		limitMax int32
+		expLimit int64
+		expOff   int64
+	}{
+		{name: "no cursor", limit: 3, offset: 3, limitMax: 1000, expLimit: 3, expOff: 3},
+		{name: "next page", cursor: &PageCursor{After: "c"}, limit: 3, offset: 3, limitMax: 1000, expLimit: 7, expOff: 2},
+		{name: "previous page", cursor: &PageCursor{Before: "d"}, limit: 3, offset: 0, limitMax: 1000, expLimit: 7, expOff: 0},
+		{name: "shift is limited by the max limit", cursor: &PageCursor{After: "c"}, limit: 3, offset: 3, limitMax: 6, expLimit: 6, expOff: 2},
+		{name: "no room for the shift", cursor: &PageCursor{After: "c"}, limit: 3, offset: 3, limitMax: 4, expLimit: 3, expOff: 3},
+		{name: "next page at the head", cursor: &PageCursor{After: "c"}, limit: 3, offset: 0, limitMax: 1000, expLimit: 3, expOff: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cursorTestConfig()
+			cfg.LimitMax = tt.limitMax
+			ctx, _ := cursorTestContext(cursorTestPath, tt.cursor)
+
+			seek := NewPageSeek(ctx, cfg, tt.limit, tt.offset)
+			assert.Equal(t, tt.expLimit, seek.Limit)
+			assert.Equal(t, tt.expOff, seek.Offset)
+		})
+	}
+}
+
+func TestPageSeek_Page(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor *PageCursor
+		offset int64
+		// the rows returned by the service for the window of the page
+		rows    []string
+		count   int64
+		expRows []string
+		// the offset of the page in the cursors of the pages next to it
+		expOffset int64
+		expNext   bool
+		expPrev   bool
+	}{
+		{
+			name:      "next page, rows aren't shifted",
+			cursor:    &PageCursor{After: "c"},
+			offset:    3,
+			rows:      []string{"c", "d", "e", "f", "g", "h", "i"},
+			count:     10,
+			expRows:   []string{"d", "e", "f"},
+			expOffset: 3,
+			expNext:   true,
+			expPrev:   true,
+		},
+		{
+			name:      "next page, a row is inserted at the head",
+			cursor:    &PageCursor{After: "c"},
+			offset:    3,
+			rows:      []string{"b", "c", "d", "e", "f", "g", "h"},
+			count:     11,
+			expRows:   []string{"d", "e", "f"},
+			expOffset: 4,
+			expNext:   true,
+			expPrev:   true,
+		},
+		{
+			name:      "next page, the rows are inserted at the head by the page size",
+			cursor:    &PageCursor{After: "c"},
+			offset:    3,
+			rows:      []string{"z", "a", "b", "c", "d", "e", "f"},
+			count:     13,
+			expRows:   []string{"d", "e", "f"},
+			expOffset: 6,
+			expNext:   true,
+			expPrev:   true,
+		},
+		{
+			name:      "next page, the border row isn't found",
+			cursor:    &PageCursor{After: "c"},
+			offset:    3,
+			rows:      []string{"d", "e", "f", "g", "h", "i"},
+			count:     9,
+			expRows:   []string{"e", "f", "g"},
+			expOffset: 3,
+			expNext:   true,
+			expPrev:   true,
+		},
+		{
+			name:      "next page, the last one",
+			cursor:    &PageCursor{After: "c"},
+			offset:    3,
+			rows:      []string{"c", "d"},
+			count:     4,
+			expRows:   []string{"d"},
+			expOffset: 3,
+			expNext:   false,
+			expPrev:   true,
+		},
+		{
+			name:      "previous page, rows aren't shifted",
+			cursor:    &PageCursor{Before: "d"},
+			offset:    0,
+			rows:      []string{"a", "b", "c", "d", "e", "f", "g"},
+			count:     10,
+			expRows:   []string{"a", "b", "c"},
+			expOffset: 0,
+			expNext:   true,
+			expPrev:   false,
+		},
+		{
+			name:      "previous page, a row is inserted at the head",
+			cursor:    &PageCursor{Before: "d"},
+			offset:    0,
+			rows:      []string{"x", "a", "b", "c", "d", "e", "f"},
+			count:     11,
+			expRows:   []string{"a", "b", "c"},
+			expOffset: 1,
+			expNext:   true,
+			expPrev:   true,
+		},
+		{
+			name:      "previous page, the border row isn't found",
+			cursor:    &PageCursor{Before: "d"},
+			offset:    0,
+			rows:      []string{"x", "y", "z", "w", "a", "b", "c"},
+			count:     13,
+			expRows:   []string{"x", "y", "z"},
+			expOffset: 0,
+			expNext:   true,
+			expPrev:   false,
+		},
+		{
+			name:      "no cursor",
+			offset:    3,
+			rows:      []string{"d", "e", "f"},
+			count:     6,
+			expRows:   []string{"d", "e", "f"},
+			expOffset: 3,
+			expNext:   false,
+			expPrev:   true,
+		},
+		{
+			name:      "empty window",
+			cursor:    &PageCursor{After: "c"},
+			offset:    3,
+			rows:      []string{},
+			count:     3,
+			expRows:   []string{},
+			expOffset: 2,
+			expNext:   false,
+			expPrev:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cursorTestConfig()
+			ctx, _ := cursorTestContext(cursorTestPath, tt.cursor)
+			seek := NewPageSeek(ctx, cfg, 3, tt.offset)
+
+			start, end, page := seek.Page(ctx, cfg, tt.count, len(tt.rows), cursorTestIds(tt.rows...))
+			assert.Equal(t, tt.expRows, tt.rows[start:end])
+			assert.Equal(t, tt.expNext, page.NextCursor != "")
+			assert.Equal(t, tt.expPrev, page.PrevCursor != "")
+
+			if page.NextCursor != "" {
+				next, err := DecodePageCursor(cfg, page.NextCursor)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.rows[end-1], next.After)
+				assert.Equal(t, strconv.FormatInt(tt.expOffset+3, 10), next.Query.Get(QueryParameterNameOffset))
+			}
+
+			if page.PrevCursor != "" {
+				prev, err := DecodePageCursor(cfg, page.PrevCursor)
+				assert.NoError(t, err)
+
+				if end > start {
+					assert.Equal(t, tt.rows[start], prev.Before)
+				}
+
+				prevOffset := tt.expOffset - 3
+
+				if prevOffset < 0 {
+					prevOffset = 0
+				}
+
+				assert.Equal(t, strconv.FormatInt(prevOffset, 10), prev.Query.Get(QueryParameterNameOffset))
+			}
+		})
+	}
+}
+
+func TestPaginate_LinkHeader(t *testing.T) {
+	cfg := cursorTestConfig()
+	ctx, rec := cursorTestContext(cursorTestPath+"?status=processed&cursor=previous", nil)
+
+	page := Paginate(ctx, cfg, 10, 10, 100, "o11", "o20")
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+
+	link := rec.Header().Get(HeaderLink)
+	assert.Equal(
+		t,
+		`<http://example.com/admin/api/v1/order?cursor=`+url.QueryEscape(page.NextCursor)+`>; rel="next", `+
+			`<http://example.com/admin/api/v1/order?cursor=`+url.QueryEscape(page.PrevCursor)+`>; rel="prev"`,
+		link,
+	)
+
+	next, err := DecodePageCursor(cfg, page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, cursorTestPath, next.Path)
+	assert.Equal(t, "o20", next.After)
+	assert.Empty(t, next.Before)
+	assert.Equal(t, url.Values{
+		"status":                 {"processed"},
+		QueryParameterNameLimit:  {"10"},
+		QueryParameterNameOffset: {"20"},
+	}, next.Query)
+
+	prev, err := DecodePageCursor(cfg, page.PrevCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "o11", prev.Before)
+	assert.Empty(t, prev.After)
+	assert.Equal(t, "0", prev.Query.Get(QueryParameterNameOffset))
+	assert.Empty(t, prev.Query.Get(QueryParameterNameCursor))
+}
+
+func TestPaginate_SinglePage(t *testing.T) {
+	cfg := cursorTestConfig()
+	ctx, rec := cursorTestContext(cursorTestPath, nil)
+
+	page := Paginate(ctx, cfg, 10, 0, 5, "o1", "o5")
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+	assert.Empty(t, rec.Header().Get(HeaderLink))
+
+	page = Paginate(ctx, cfg, 0, 0, 5, "o1", "o5")
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+}
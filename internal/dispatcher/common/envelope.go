@@ -0,0 +1,143 @@
+package common
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorEnvelope is the body of every error response
+type ErrorEnvelope struct {
+	Code      string        `json:"code"`
+	Message   string        `json:"message"`
+	Details   string        `json:"details,omitempty"`
+	RequestId string        `json:"request_id,omitempty"`
+	Errors    []*FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes the failed validation of the request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var statusErrors = map[int]*billingpb.ResponseErrorMessage{
+	http.StatusBadRequest:          ErrorMessageBadRequest,
+	http.StatusUnauthorized:        ErrorMessageUnauthorized,
+	http.StatusForbidden:           ErrorMessageAccessDenied,
+	http.StatusNotFound:            ErrorMessageNotFound,
+	http.StatusMethodNotAllowed:    ErrorMessageMethodNotAllowed,
+	http.StatusTooManyRequests:     ErrorMessageTooManyRequests,
+	http.StatusInternalServerError: ErrorInternal,
+}
+
+// NewErrorEnvelope converts the error returned by handler to the response status and envelope with messages in the locale
+func NewErrorEnvelope(err error, locale string) (int, *ErrorEnvelope) {
+	status := http.StatusInternalServerError
+	var message interface{} = err
+	var internal error
+
+	switch typed := err.(type) {
+	case *echo.HTTPError:
+		status = typed.Code
+		message = typed.Message
+		internal = typed.Internal
+	case *billingpb.ResponseErrorMessage:
+		status = http.StatusBadRequest
+	}
+
+	envelope := &ErrorEnvelope{}
+	// the specific messages without the own code are sent as is, the catalog translates
+	// the generic status messages only
+	localize := true
+
+	switch typed := message.(type) {
+	case *billingpb.ResponseErrorMessage:
+		envelope.Code = typed.Code
+		envelope.Message = typed.Message
+		envelope.Details = typed.Details
+	case string:
+		envelope.Code = statusError(status).Code
+		envelope.Message = typed
+		// echo errors, i.e. echo.ErrNotFound, have the status text as the message
+		localize = typed == http.StatusText(status)
+	case error:
+		envelope.Code = statusError(status).Code
+		envelope.Message = statusError(status).Message
+
+		// internal errors are not exposed to the client
+		if status < http.StatusInternalServerError {
+			envelope.Message = typed.Error()
+			localize = false
+		}
+	default:
+		envelope.Code = statusError(status).Code
+		envelope.Message = statusError(status).Message
+	}
+
+	if localize {
+		envelope.Message = LocalizeMessage(locale, envelope.Code, envelope.Message)
+	}
+
+	if vErrs, ok := internal.(validator.ValidationErrors); ok {
+		envelope.Errors = NewFieldErrors(vErrs, ValidationTranslator(locale))
+	}
+
+	return status, envelope
+}
+
+// ExtractRequestId returns the request id sent to the client or received from it
+func ExtractRequestId(ctx echo.Context) string {
+	if id := ctx.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return ctx.Request().Header.Get(echo.HeaderXRequestID)
+}
+
+// PrefersHTML returns true if the Accept header explicitly prefers HTML over JSON, as browsers do
+func PrefersHTML(accept string) bool {
+	var html, json float64
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		switch mediaType {
+		case echo.MIMETextHTML, "application/xhtml+xml":
+			if q > html {
+				html = q
+			}
+		case echo.MIMEApplicationJSON:
+			if q > json {
+				json = q
+			}
+		}
+	}
+
+	return html > 0 && html >= json
+}
+
+func statusError(status int) *billingpb.ResponseErrorMessage {
+	if rspErr, ok := statusErrors[status]; ok {
+		return rspErr
+	}
+	if status >= http.StatusInternalServerError {
+		return ErrorInternal
+	}
+	return ErrorUnknown
+}
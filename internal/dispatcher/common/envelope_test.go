@@ -0,0 +1,57 @@
+package common
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"testing"
+)
+
+func TestNewErrorEnvelope_Localized(t *testing.T) {
+	status, envelope := NewErrorEnvelope(echo.NewHTTPError(http.StatusNotFound, ErrorMessageNotFound), LocaleRu)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, ErrorMessageNotFound.Code, envelope.Code)
+	assert.Equal(t, messagesRu[ErrorMessageNotFound.Code], envelope.Message)
+}
+
+func TestNewErrorEnvelope_StatusText_Localized(t *testing.T) {
+	status, envelope := NewErrorEnvelope(echo.ErrNotFound, LocaleRu)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, ErrorMessageNotFound.Code, envelope.Code)
+	assert.Equal(t, messagesRu[ErrorMessageNotFound.Code], envelope.Message)
+}
+
+func TestNewErrorEnvelope_StringMessage_Kept(t *testing.T) {
+	status, envelope := NewErrorEnvelope(echo.NewHTTPError(http.StatusBadRequest, "row 2: order_id is required"), LocaleRu)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, ErrorMessageBadRequest.Code, envelope.Code)
+	assert.Equal(t, "row 2: order_id is required", envelope.Message)
+}
+
+func TestNewErrorEnvelope_FieldErrors_Localized(t *testing.T) {
+	validate := validator.New()
+	assert.NoError(t, RegisterValidationTranslations(validate))
+
+	req := struct {
+		OrderId string `json:"order_id" validate:"required"`
+		Amount  int    `json:"amount" validate:"gte=1"`
+	}{}
+	err := validate.Struct(req)
+	assert.Error(t, err)
+
+	messages := map[string]map[string]string{
+		LocaleEn: {"order_id": "order_id is a required field", "amount": "amount must be 1 or greater"},
+		LocaleRu: {"order_id": "order_id является обязательным полем", "amount": "amount должно быть больше или равно 1"},
+		LocaleDe: {"order_id": "order_id ist ein Pflichtfeld", "amount": "amount muss größer oder gleich 1 sein"},
+	}
+
+	for locale, expected := range messages {
+		_, envelope := NewErrorEnvelope(NewValidationHTTPError(err), locale)
+		assert.Len(t, envelope.Errors, len(expected), locale)
+
+		for _, fieldErr := range envelope.Errors {
+			assert.Equal(t, expected[fieldErr.Field], fieldErr.Message, locale)
+		}
+	}
+}
@@ -179,6 +179,52 @@ var (
 	ErrorMessageMerchantDocumentUploadFailed      = NewManagementApiResponseError("ma000115", "unable to upload file")
 	ErrorMessageMerchantDocumentDownloadFailed    = NewManagementApiResponseError("ma000116", "unable to download document file")
 
+	ErrorMessageIdempotencyKeyInvalid    = NewManagementApiResponseError("ma000117", "idempotency key is too long")
+	ErrorMessageIdempotencyKeyReused     = NewManagementApiResponseError("ma000118", "idempotency key was already used for another request")
+	ErrorMessageIdempotencyKeyInProgress = NewManagementApiResponseError("ma000119", "request with the same idempotency key is still in progress")
+	ErrorMessageTooManyRequests          = NewManagementApiResponseError("ma000120", "too many requests, try again later")
+
+	ErrorMessageBadRequest       = NewManagementApiResponseError("ma000121", "bad request")
+	ErrorMessageUnauthorized     = NewManagementApiResponseError("ma000122", "unauthorized")
+	ErrorMessageNotFound         = NewManagementApiResponseError("ma000123", "not found")
+	ErrorMessageMethodNotAllowed = NewManagementApiResponseError("ma000124", "method not allowed")
+
+	ErrorMessageS2SSignatureHeadersNotFound = NewManagementApiResponseError("ma000125", "request signature headers not found")
+	ErrorMessageS2STimestampInvalid         = NewManagementApiResponseError("ma000126", "request timestamp is invalid or out of allowed clock skew")
+	ErrorMessageS2SNonceInvalid             = NewManagementApiResponseError("ma000127", "request nonce is invalid")
+	ErrorMessageS2SNonceReused              = NewManagementApiResponseError("ma000128", "request nonce was already used")
+	ErrorMessageS2SSignatureInvalid         = NewManagementApiResponseError("ma000129", "request signature is invalid")
+	ErrorMessageS2SBasicAuthNotAllowed      = NewManagementApiResponseError("ma000130", "basic authentication is not allowed for the project, sign the request")
+
+	ErrorMessageApiKeyNotFound      = NewManagementApiResponseError("ma000131", "api key not found")
+	ErrorMessageApiKeyExpired       = NewManagementApiResponseError("ma000132", "api key is expired")
+	ErrorMessageApiKeyScopeDenied   = NewManagementApiResponseError("ma000133", "api key has no scope for the request")
+	ErrorMessageApiKeyExpiryInvalid = NewManagementApiResponseError("ma000134", "api key expiration time must be in the future")
+
+	ErrorMessageMerchantNotMember = NewManagementApiResponseError("ma000135", "user is not a member of the requested merchant")
+
+	ErrorMessageApprovalNotFound        = NewManagementApiResponseError("ma000136", "approval request not found")
+	ErrorMessageApprovalAlreadyDecided  = NewManagementApiResponseError("ma000137", "approval request is already approved or rejected")
+	ErrorMessageApprovalMakerNotAllowed = NewManagementApiResponseError("ma000138", "approval request can't be approved or rejected by its maker")
+
+	ErrorMessageSessionRevoked  = NewManagementApiResponseError("ma000139", "session is revoked, sign in again")
+	ErrorMessageSessionNotFound = NewManagementApiResponseError("ma000140", "session not found")
+	ErrorMessageSessionUnknown  = NewManagementApiResponseError("ma000150", "session can't be checked, try again later")
+
+	ErrorMessageTokenProjectMismatch = NewManagementApiResponseError("ma000151", "token request project doesn't match the project of the signature")
+
+	ErrorMessageCursorInvalid = NewManagementApiResponseError("ma000141", "page cursor is invalid or expired")
+
+	ErrorMessageIdempotencyKeyRequired = NewManagementApiResponseError("ma000142", "idempotency key is required for the request")
+	ErrorMessageRefundAmountExceeded   = NewManagementApiResponseError("ma000143", "refund amount exceeds the refundable amount of the order")
+
+	ErrorMessageBulkRefundRowsInvalid   = NewManagementApiResponseError("ma000144", "bulk refund rows are invalid")
+	ErrorMessageBulkRefundTooManyRows   = NewManagementApiResponseError("ma000145", "bulk refund has too many rows")
+	ErrorMessageBulkRefundJobNotFound   = NewManagementApiResponseError("ma000146", "bulk refund job not found")
+	ErrorMessageBulkRefundJobInProgress = NewManagementApiResponseError("ma000147", "bulk refund job is still in progress")
+	ErrorMessageBulkRefundShutdown      = NewManagementApiResponseError("ma000148", "server is shutting down, submit the bulk refund again")
+	ErrorMessageRefundInProgress        = NewManagementApiResponseError("ma000149", "another refund of the order is being created, try again later")
+
 	ValidationErrors = map[string]*billingpb.ResponseErrorMessage{
 		UserProfileFieldNumberOfEmployees: ErrorMessageIncorrectNumberOfEmployees,
 		UserProfileFieldAnnualIncome:      ErrorMessageIncorrectAnnualIncome,
@@ -0,0 +1,250 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var (
+	errIdempotencyKeyBusy = errors.New("idempotency key is concurrently reserved and released")
+)
+
+// IdempotencyRecord
+type IdempotencyRecord struct {
+	// Hash of the request which was first processed with the key
+	RequestHash string `bson:"request_hash"`
+	// Completed is false while the first request with the key is still in progress
+	Completed bool        `bson:"completed"`
+	Status    int         `bson:"status"`
+	Header    http.Header `bson:"header"`
+	Body      []byte      `bson:"body"`
+	CreatedAt time.Time   `bson:"created_at"`
+}
+
+// IdempotencyStore is a storage backend for the responses of requests with Idempotency-Key header
+type IdempotencyStore interface {
+	// Reserve locks the key for the request with the given hash. If the key is already known
+	// the existing record returns and reserved is false.
+	Reserve(key, requestHash string) (record *IdempotencyRecord, reserved bool, err error)
+	// Complete saves the response for the previously reserved key
+	Complete(key string, record *IdempotencyRecord) error
+	// Release removes the reservation, so the request with the key can be retried
+	Release(key string) error
+}
+
+// IdempotencyKey builds the storage key scoped to the owner of the key
+func IdempotencyKey(scope, key string) string {
+	return scope + ":" + key
+}
+
+// IsMutatingMethod
+func IsMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RequireIdempotencyKey denies the mutating requests without Idempotency-Key header,
+// the route must be in the group with the idempotency middleware
+func RequireIdempotencyKey(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if ctx.Request().Header.Get(HeaderIdempotencyKey) == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, ErrorMessageIdempotencyKeyRequired)
+		}
+
+		return next(ctx)
+	}
+}
+
+// IdempotencyRequestHash identifies the request by the method, path, query and body,
+// the query parameters are sorted so their order doesn't matter
+func IdempotencyRequestHash(method, path string, query url.Values, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write([]byte(query.Encode()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// mongoIdempotencyRecord is the stored record, the document is removed after expireAt
+type mongoIdempotencyRecord struct {
+	Key               string `bson:"_id"`
+	IdempotencyRecord `bson:",inline"`
+	ExpireAt          time.Time `bson:"expire_at"`
+}
+
+type mongoIdempotencyStore struct {
+	lease   time.Duration
+	ttl     time.Duration
+	records mongoCollection
+}
+
+// NewMongoIdempotencyStore returns the storage shared by all instances, so the repeated request is replayed
+// by any instance. The reservation expires after lease, so the key of the lost request may be retried,
+// the completed records expire after ttl.
+func NewMongoIdempotencyStore(db *mgo.Database, lease, ttl time.Duration) (IdempotencyStore, error) {
+	s := &mongoIdempotencyStore{
+		lease:   lease,
+		ttl:     ttl,
+		records: mongoCollection{db: db, name: collectionIdempotencyKeys},
+	}
+
+	if err := s.records.ensureIndexes(mgo.Index{Key: []string{"expire_at"}, ExpireAfter: time.Second}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoIdempotencyStore) Reserve(key, requestHash string) (*IdempotencyRecord, bool, error) {
+	now := time.Now().UTC()
+	reserved := &mongoIdempotencyRecord{
+		Key:               key,
+		IdempotencyRecord: IdempotencyRecord{RequestHash: requestHash, CreatedAt: now},
+		ExpireAt:          now.Add(s.lease),
+	}
+
+	// the expired record may be still in the collection, it's removed and the key is reserved again
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.records.with(func(c *mgo.Collection) error {
+			return c.Insert(reserved)
+		})
+
+		if err == nil {
+			return nil, true, nil
+		}
+
+		if !mgo.IsDup(err) {
+			return nil, false, err
+		}
+
+		record := &mongoIdempotencyRecord{}
+		err = s.records.with(func(c *mgo.Collection) error {
+			return c.FindId(key).One(record)
+		})
+
+		if isMongoNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		if record.ExpireAt.After(now) {
+			return &record.IdempotencyRecord, false, nil
+		}
+
+		err = s.records.with(func(c *mgo.Collection) error {
+			return c.Remove(bson.M{"_id": key, "expire_at": record.ExpireAt})
+		})
+
+		if err != nil && !isMongoNotFound(err) {
+			return nil, false, err
+		}
+	}
+
+	return nil, false, errIdempotencyKeyBusy
+}
+
+func (s *mongoIdempotencyStore) Complete(key string, record *IdempotencyRecord) error {
+	record.Completed = true
+
+	return s.records.with(func(c *mgo.Collection) error {
+		_, err := c.UpsertId(key, &mongoIdempotencyRecord{
+			Key:               key,
+			IdempotencyRecord: *record,
+			ExpireAt:          record.CreatedAt.Add(s.ttl),
+		})
+		return err
+	})
+}
+
+func (s *mongoIdempotencyStore) Release(key string) error {
+	err := s.records.with(func(c *mgo.Collection) error {
+		return c.RemoveId(key)
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+type memoryIdempotencyStore struct {
+	mx      sync.Mutex
+	lease   time.Duration
+	ttl     time.Duration
+	records map[string]*IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore returns the in-memory storage for tests, the reservations expire after lease
+// and the completed records expire after ttl
+func NewMemoryIdempotencyStore(lease, ttl time.Duration) IdempotencyStore {
+	return &memoryIdempotencyStore{
+		lease:   lease,
+		ttl:     ttl,
+		records: make(map[string]*IdempotencyRecord),
+	}
+}
+
+func (s *memoryIdempotencyStore) Reserve(key, requestHash string) (*IdempotencyRecord, bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now()
+	s.evict(now)
+
+	if record, ok := s.records[key]; ok {
+		return record, false, nil
+	}
+
+	s.records[key] = &IdempotencyRecord{RequestHash: requestHash, CreatedAt: now}
+	return nil, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(key string, record *IdempotencyRecord) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	record.Completed = true
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(key string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) evict(now time.Time) {
+	for key, record := range s.records {
+		ttl := s.ttl
+
+		if !record.Completed {
+			ttl = s.lease
+		}
+
+		if ttl > 0 && now.Sub(record.CreatedAt) > ttl {
+			delete(s.records, key)
+		}
+	}
+}
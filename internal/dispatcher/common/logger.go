@@ -1,5 +1,10 @@
 package common
 
+import (
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
 // RequestResponseHeadersToString
 func RequestResponseHeadersToString(headers map[string][]string) string {
 	var out string
@@ -8,3 +13,12 @@ func RequestResponseHeadersToString(headers map[string][]string) string {
 	}
 	return out
 }
+
+// RequestLogger returns the logger with the request scoped fields (trace id etc.)
+func RequestLogger(ctx echo.Context, log logger.Logger) logger.Logger {
+	fields := ExtractLogFieldsContext(ctx)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.WithFields(fields)
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"strconv"
+	"strings"
+)
+
+const (
+	LocaleEn = "en"
+	LocaleRu = "ru"
+	LocaleDe = "de"
+
+	// separates the code and the english message in the catalog keys of the codes shared by several errors
+	messageKeySeparator = "|"
+)
+
+// messageCatalog contains translated error messages by locale and error code. English messages are
+// the ones used in error definitions, so english is the fallback for absent translations.
+var messageCatalog = map[string]map[string]string{
+	LocaleRu: messagesRu,
+	LocaleDe: messagesDe,
+}
+
+// LocalizeMessage returns the message for the error code in the locale, original message if translation is absent
+func LocalizeMessage(locale, code, message string) string {
+	messages, ok := messageCatalog[locale]
+
+	if !ok {
+		return message
+	}
+
+	if translated, ok := messages[code+messageKeySeparator+message]; ok {
+		return translated
+	}
+
+	if translated, ok := messages[code]; ok {
+		return translated
+	}
+
+	return message
+}
+
+// LocalizeError returns the copy of error with the message in the locale
+func LocalizeError(locale string, rspErr *billingpb.ResponseErrorMessage) *billingpb.ResponseErrorMessage {
+	return NewManagementApiResponseError(rspErr.Code, LocalizeMessage(locale, rspErr.Code, rspErr.Message), rspErr.Details)
+}
+
+// RequestLocale returns the locale of the error messages. The user profile locale is preferred,
+// then the first supported language of Accept-Language header, otherwise english.
+func RequestLocale(ctx echo.Context) string {
+	if locale := SupportedLocale(ExtractUserContext(ctx).Locale); locale != "" {
+		return locale
+	}
+
+	if locale := AcceptLanguageLocale(ctx.Request().Header.Get(HeaderAcceptLanguage)); locale != "" {
+		return locale
+	}
+
+	return LocaleEn
+}
+
+// SupportedLocale returns the language of the locale (i.e. "ru" for "ru-RU") if it's supported, empty string otherwise
+func SupportedLocale(locale string) string {
+	lang := strings.ToLower(strings.SplitN(strings.SplitN(strings.TrimSpace(locale), "-", 2)[0], "_", 2)[0])
+
+	if lang == LocaleEn {
+		return lang
+	}
+
+	if _, ok := messageCatalog[lang]; ok {
+		return lang
+	}
+
+	return ""
+}
+
+// AcceptLanguageLocale returns the supported locale with the highest weight in Accept-Language header
+func AcceptLanguageLocale(header string) string {
+	var (
+		locale string
+		weight float64
+	)
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		q := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if lang := SupportedLocale(params[0]); lang != "" && q > weight {
+			locale, weight = lang, q
+		}
+	}
+
+	return locale
+}
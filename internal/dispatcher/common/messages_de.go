@@ -0,0 +1,182 @@
+package common
+
+var messagesDe = map[string]string{
+	"ma000001": "unbekannter Fehler. Versuchen Sie es später erneut",
+	"ma000002": "Validierung fehlgeschlagen",
+	"ma000003": "interner Fehler",
+	"ma000004": "Zugriff verweigert",
+	"ma000005": "Kennung darf nicht leer sein",
+	"ma000006": "ungültige Händlerkennung",
+	"ma000007": "ungültige Benachrichtigungskennung",
+	"ma000008": "ungültige Bestellkennung",
+	"ma000009": "ungültige Produktkennung",
+	"ma000010": "ungültige Länderkennung",
+	"ma000011": "ungültige Währungskennung",
+	"ma000012": "keine Bestellungen gefunden",
+	"ma000013": "Land nicht gefunden",
+	"ma000014": "Währung nicht gefunden",
+	"ma000015": "Benachrichtigung nicht gefunden",
+	"ma000020": "Vereinbarung kann für ungeprüfte Händlerdaten nicht erstellt werden",
+	"ma000021": "Vereinbarung für den Händler wurde noch nicht erstellt",
+	"ma000022": "Header mit der Anfragesignatur darf nicht leer sein",
+	"ma000023": "ungültige Anfrageparameter",
+	"ma000024": "ungültige E-Mail",
+	"ma000026": "ungültige Anfragedaten",
+	"ma000027": "Fehler beim Abrufen der Länderliste",
+	"ma000028": "Datei für den angegebenen Schlüssel existiert nicht",
+	"ma000029": "kein multipart boundary Parameter im Content-Type",
+	"ma000030": "Hochladen fehlgeschlagen",
+	"ma000031": "ungültige Projektkennung",
+	"ma000032": "ungültige Zahlungsmethodenkennung",
+	"ma000033": "ungültige Zahlungslinkkennung",
+	"ma000034": "Authorization-Header nicht gefunden",
+	"ma000035": "Autorisierungstoken nicht gefunden",
+	"ma000036": "Informationen über den autorisierten Benutzer nicht gefunden",
+	"ma000037": "Parameter status hat einen ungültigen Typ",
+	"ma000038": "Vereinbarung für den Händler nicht gefunden",
+	"ma000039": "maximale Uploadgröße des Vereinbarungsdokuments überschritten",
+	"ma000040": "Vereinbarungsdokument muss ein PDF sein",
+	"ma000041": "Parameter Vereinbarungstyp hat einen ungültigen Typ",
+	"ma000042": "Parameter Händlersignatur hat einen ungültigen Typ",
+	"ma000043": "Parameter PaySuper-Signatur hat einen ungültigen Typ",
+	"ma000044": "Parameter Versand der Vereinbarung per E-Mail hat einen ungültigen Typ",
+	"ma000045": "Parameter Sendungsverfolgungslink hat einen ungültigen Typ",
+	"ma000046": "Parameter name hat einen ungültigen Typ",
+	"ma000047": "Parameter image hat einen ungültigen Typ",
+	"ma000048": "Parameter Callback-Währung hat einen ungültigen Typ",
+	"ma000049": "Parameter Callback-Protokoll hat einen ungültigen Typ",
+	"ma000050": "Parameter erlaubte URLs zur Bestellerstellung hat einen ungültigen Typ",
+	"ma000051": "Parameter dynamische Benachrichtigungs-URLs erlauben hat einen ungültigen Typ",
+	"ma000052": "Parameter dynamische Weiterleitungs-URLs erlauben hat einen ungültigen Typ",
+	"ma000053": "Parameter Limitwährung hat einen ungültigen Typ",
+	"ma000054": "Parameter minimaler Zahlungsbetrag hat einen ungültigen Typ",
+	"ma000055": "Parameter maximaler Zahlungsbetrag hat einen ungültigen Typ",
+	"ma000056": "Parameter Benachrichtigungs-E-Mails hat einen ungültigen Typ",
+	"ma000057": "Parameter Produkt-Checkout hat einen ungültigen Typ",
+	"ma000058": "Parameter geheimer Schlüssel hat einen ungültigen Typ",
+	"ma000059": "Parameter Signatur erforderlich hat einen ungültigen Typ",
+	"ma000060": "Parameter Benachrichtigungs-E-Mail senden hat einen ungültigen Typ",
+	"ma000061": "Parameter URL Kontoprüfung hat einen ungültigen Typ",
+	"ma000062": "Parameter URL Zahlungsverarbeitung hat einen ungültigen Typ",
+	"ma000063": "Parameter URL Weiterleitung bei Fehler hat einen ungültigen Typ",
+	"ma000064": "Parameter URL Weiterleitung bei Erfolg hat einen ungültigen Typ",
+	"ma000065": "Parameter URL Rückbuchung hat einen ungültigen Typ",
+	"ma000066": "Parameter URL Zahlungsstornierung hat einen ungültigen Typ",
+	"ma000067": "Parameter URL betrügerische Zahlung hat einen ungültigen Typ",
+	"ma000068": "Parameter URL Zahlungserstattung hat einen ungültigen Typ",
+	"ma000069": "Preisgruppe für das Land konnte nicht abgerufen werden",
+	"ma000070": "Währungen der Preisgruppe konnten nicht abgerufen werden",
+	"ma000071": "Währung der Preisgruppe für die Region konnte nicht abgerufen werden",
+
+	"ma000072|unable to get price group recommended prices": "empfohlene Preise der Preisgruppe konnten nicht abgerufen werden",
+	"ma000072|unable to get price of product":               "Produktpreis konnte nicht abgerufen werden",
+	"ma000072|unable to update price of product":            "Produktpreis konnte nicht aktualisiert werden",
+
+	"ma000073": "ungültige Postleitzahl",
+	"ma000074": "ungültige Anzahl der Mitarbeiter",
+	"ma000075": "ungültiges Jahreseinkommen",
+	"ma000076": "ungültiger Firmenname",
+	"ma000077": "ungültige Position",
+	"ma000078": "ungültiger Vorname",
+	"ma000079": "ungültiger Nachname",
+	"ma000080": "ungültige Webseite",
+	"ma000081": "ungültige Tätigkeitsart",
+
+	"ma000082|review must be text with length lower than or equal 500 characters":                         "Bewertung muss ein Text mit höchstens 500 Zeichen sein",
+	"ma000082|key product id is invalid":                                                                  "ungültige Schlüsselprodukt-ID",
+	"ma000083|review page identifier must be one of next values: primary_onboarding, merchant_onboarding": "Seitenkennung der Bewertung muss einer der folgenden Werte sein: primary_onboarding, merchant_onboarding",
+	"ma000083|platform id is invalid":                                                                     "ungültige Plattform-ID",
+
+	"ma000084": "ungültige Marke",
+	"ma000085": "ungültiges Bundesland",
+	"ma000086": "ungültige Stadt",
+	"ma000087": "ungültige Adresse",
+	"ma000088": "Kontaktdaten des bevollmächtigten Ansprechpartners der Firma sind erforderlich",
+	"ma000089": "technische Kontaktdaten der Firma sind erforderlich",
+	"ma000090": "ungültiger Name",
+	"ma000091": "ungültige Telefonnummer",
+	"ma000092": "ungültiger Bankname",
+	"ma000093": "ungültige Bankadresse",
+	"ma000094": "ungültige Kontonummer",
+	"ma000095": "ungültiger SWIFT-Code der Bank",
+	"ma000096": "ungültiges Korrespondenzkonto der Bank",
+	"ma000097": "Datei mit Schlüsseln wurde nicht angegeben",
+	"ma000098": "Datei kann nicht gelesen werden",
+	"ma000099": "ungültiger Zeitraum",
+	"ma000100": "Händler nicht gefunden",
+	"ma000101": "Berichtsdatei konnte nicht erstellt werden",
+	"ma000102": "Berichtsdatei konnte nicht heruntergeladen werden",
+	"ma000103": "lokalisiertes Feld hat einen ungültigen Typ",
+	"ma000104": "Feld cover hat einen ungültigen Typ",
+	"ma000105": "Einladung konnte nicht gesendet werden",
+	"ma000106": "Einladung konnte nicht angenommen werden",
+	"ma000107": "Einladungstoken konnte nicht geprüft werden",
+	"ma000108": "ungültiger Rollentyp",
+	"ma000109": "Benutzer konnte nicht gelöscht werden",
+	"ma000110": "ungültiger Weiterleitungsmodus",
+	"pr000111": "ungültige Art der Weiterleitungsnutzung",
+	"ma000111": "ungültiger Datumsfilter",
+	"ma000112": "Datei in der Upload-Anfrage des Händlerdokuments nicht gefunden",
+	"ma000113": "hochgeladene Datei muss kleiner als 30MB sein",
+	"ma000114": "nicht unterstützter Dateityp",
+	"ma000115": "Datei konnte nicht hochgeladen werden",
+	"ma000116": "Dokumentdatei konnte nicht heruntergeladen werden",
+	"ma000117": "Idempotenzschlüssel ist zu lang",
+	"ma000118": "Idempotenzschlüssel wurde bereits für eine andere Anfrage verwendet",
+	"ma000119": "Anfrage mit demselben Idempotenzschlüssel wird noch verarbeitet",
+	"ma000120": "zu viele Anfragen, versuchen Sie es später erneut",
+	"ma000121": "ungültige Anfrage",
+	"ma000122": "nicht autorisiert",
+	"ma000123": "nicht gefunden",
+	"ma000124": "Methode nicht erlaubt",
+	"ma000125": "Signatur-Header der Anfrage nicht gefunden",
+	"ma000126": "Zeitstempel der Anfrage ist ungültig oder außerhalb der erlaubten Zeitabweichung",
+	"ma000127": "Nonce der Anfrage ist ungültig",
+	"ma000128": "Nonce der Anfrage wurde bereits verwendet",
+	"ma000129": "Signatur der Anfrage ist ungültig",
+	"ma000130": "Basic-Authentifizierung ist für das Projekt nicht erlaubt, signieren Sie die Anfrage",
+	"ma000131": "API-Schlüssel nicht gefunden",
+	"ma000132": "API-Schlüssel ist abgelaufen",
+	"ma000133": "API-Schlüssel hat keinen Bereich für die Anfrage",
+	"ma000134": "Ablaufzeit des API-Schlüssels muss in der Zukunft liegen",
+	"ma000135": "Benutzer ist kein Mitglied des angeforderten Händlers",
+	"ma000136": "Genehmigungsanfrage nicht gefunden",
+	"ma000137": "Genehmigungsanfrage wurde bereits genehmigt oder abgelehnt",
+	"ma000138": "Genehmigungsanfrage kann nicht von ihrem Ersteller genehmigt oder abgelehnt werden",
+	"ma000139": "Sitzung wurde widerrufen, melden Sie sich erneut an",
+	"ma000140": "Sitzung nicht gefunden",
+	"ma000141": "Seitencursor ist ungültig oder abgelaufen",
+	"ma000142": "Idempotenzschlüssel ist für die Anfrage erforderlich",
+	"ma000143": "Rückerstattungsbetrag übersteigt den erstattungsfähigen Betrag der Bestellung",
+	"ma000144": "Zeilen der Sammelrückerstattung sind ungültig",
+	"ma000145": "Sammelrückerstattung hat zu viele Zeilen",
+	"ma000146": "Auftrag der Sammelrückerstattung nicht gefunden",
+	"ma000147": "Auftrag der Sammelrückerstattung wird noch bearbeitet",
+	"ma000148": "Server wird heruntergefahren, senden Sie die Sammelrückerstattung erneut",
+	"ma000149": "eine andere Rückerstattung der Bestellung wird gerade erstellt, versuchen Sie es später erneut",
+	"ma000150": "Sitzung kann nicht geprüft werden, versuchen Sie es später erneut",
+	"ma000151": "Projekt der Token-Anfrage stimmt nicht mit dem Projekt der Signatur überein",
+}
+
+var validationMessagesDe = map[string]string{
+	"required":         "{0} ist ein Pflichtfeld",
+	"len":              "{0} muss die Länge {1} haben",
+	"min":              "{0} muss mindestens {1} sein",
+	"max":              "{0} darf höchstens {1} sein",
+	"eq":               "{0} muss gleich {1} sein",
+	"ne":               "{0} darf nicht gleich {1} sein",
+	"gt":               "{0} muss größer als {1} sein",
+	"gte":              "{0} muss größer oder gleich {1} sein",
+	"lt":               "{0} muss kleiner als {1} sein",
+	"lte":              "{0} muss kleiner oder gleich {1} sein",
+	"oneof":            "{0} muss einer der Werte [{1}] sein",
+	"unique":           "{0} muss eindeutige Werte enthalten",
+	"email":            "{0} muss eine gültige E-Mail-Adresse sein",
+	"url":              "{0} muss eine gültige URL sein",
+	"uuid":             "{0} muss eine gültige UUID sein",
+	"alpha":            "{0} darf nur Buchstaben enthalten",
+	"alphanum":         "{0} darf nur Buchstaben und Ziffern enthalten",
+	"numeric":          "{0} muss eine Zahl sein",
+	"hexadecimal":      "{0} muss eine hexadezimale Zeichenkette sein",
+	"iso3166_1_alpha2": "{0} muss ein Ländercode nach ISO 3166-1 alpha-2 sein",
+}
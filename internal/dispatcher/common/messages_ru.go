@@ -0,0 +1,182 @@
+package common
+
+var messagesRu = map[string]string{
+	"ma000001": "неизвестная ошибка. повторите запрос позже",
+	"ma000002": "ошибка валидации",
+	"ma000003": "внутренняя ошибка",
+	"ma000004": "доступ запрещён",
+	"ma000005": "идентификатор не может быть пустым",
+	"ma000006": "некорректный идентификатор мерчанта",
+	"ma000007": "некорректный идентификатор уведомления",
+	"ma000008": "некорректный идентификатор заказа",
+	"ma000009": "некорректный идентификатор продукта",
+	"ma000010": "некорректный идентификатор страны",
+	"ma000011": "некорректный идентификатор валюты",
+	"ma000012": "заказы не найдены",
+	"ma000013": "страна не найдена",
+	"ma000014": "валюта не найдена",
+	"ma000015": "уведомление не найдено",
+	"ma000020": "соглашение не может быть сформировано для непроверенных данных мерчанта",
+	"ma000021": "соглашение для мерчанта ещё не сформировано",
+	"ma000022": "заголовок с подписью запроса не может быть пустым",
+	"ma000023": "некорректные параметры запроса",
+	"ma000024": "некорректный email",
+	"ma000026": "некорректные данные запроса",
+	"ma000027": "ошибка получения списка стран",
+	"ma000028": "файл с указанным ключом не существует",
+	"ma000029": "в Content-Type отсутствует параметр boundary",
+	"ma000030": "ошибка загрузки",
+	"ma000031": "некорректный идентификатор проекта",
+	"ma000032": "некорректный идентификатор платёжного метода",
+	"ma000033": "некорректный идентификатор платёжной ссылки",
+	"ma000034": "заголовок авторизации не найден",
+	"ma000035": "токен авторизации не найден",
+	"ma000036": "информация об авторизованном пользователе не найдена",
+	"ma000037": "параметр status имеет некорректный тип",
+	"ma000038": "соглашение для мерчанта не найдено",
+	"ma000039": "превышен максимальный размер документа соглашения",
+	"ma000040": "документ соглашения должен быть в формате pdf",
+	"ma000041": "параметр типа соглашения имеет некорректный тип",
+	"ma000042": "параметр подписи мерчанта имеет некорректный тип",
+	"ma000043": "параметр подписи paysuper имеет некорректный тип",
+	"ma000044": "параметр отправки соглашения по email имеет некорректный тип",
+	"ma000045": "параметр ссылки отслеживания почты имеет некорректный тип",
+	"ma000046": "параметр name имеет некорректный тип",
+	"ma000047": "параметр image имеет некорректный тип",
+	"ma000048": "параметр валюты callback имеет некорректный тип",
+	"ma000049": "параметр протокола callback имеет некорректный тип",
+	"ma000050": "параметр разрешённых url создания заказа имеет некорректный тип",
+	"ma000051": "параметр разрешения динамических url уведомлений имеет некорректный тип",
+	"ma000052": "параметр разрешения динамических url перенаправления имеет некорректный тип",
+	"ma000053": "параметр валюты лимитов имеет некорректный тип",
+	"ma000054": "параметр минимальной суммы платежа имеет некорректный тип",
+	"ma000055": "параметр максимальной суммы платежа имеет некорректный тип",
+	"ma000056": "параметр email для уведомлений имеет некорректный тип",
+	"ma000057": "параметр оплаты продуктов имеет некорректный тип",
+	"ma000058": "параметр секретного ключа имеет некорректный тип",
+	"ma000059": "параметр обязательности подписи имеет некорректный тип",
+	"ma000060": "параметр отправки email уведомлений имеет некорректный тип",
+	"ma000061": "параметр url проверки аккаунта имеет некорректный тип",
+	"ma000062": "параметр url обработки платежа имеет некорректный тип",
+	"ma000063": "параметр url перенаправления при ошибке имеет некорректный тип",
+	"ma000064": "параметр url перенаправления при успехе имеет некорректный тип",
+	"ma000065": "параметр url чарджбэка имеет некорректный тип",
+	"ma000066": "параметр url отмены платежа имеет некорректный тип",
+	"ma000067": "параметр url мошеннического платежа имеет некорректный тип",
+	"ma000068": "параметр url возврата платежа имеет некорректный тип",
+	"ma000069": "не удалось получить ценовую группу по стране",
+	"ma000070": "не удалось получить валюты ценовой группы",
+	"ma000071": "не удалось получить валюту ценовой группы по региону",
+
+	"ma000072|unable to get price group recommended prices": "не удалось получить рекомендованные цены ценовой группы",
+	"ma000072|unable to get price of product":               "не удалось получить цену продукта",
+	"ma000072|unable to update price of product":            "не удалось обновить цену продукта",
+
+	"ma000073": "некорректный почтовый индекс",
+	"ma000074": "некорректное количество сотрудников",
+	"ma000075": "некорректный годовой доход",
+	"ma000076": "некорректное название компании",
+	"ma000077": "некорректная должность",
+	"ma000078": "некорректное имя",
+	"ma000079": "некорректная фамилия",
+	"ma000080": "некорректный веб-сайт",
+	"ma000081": "некорректный вид деятельности",
+
+	"ma000082|review must be text with length lower than or equal 500 characters":                         "отзыв должен быть текстом длиной не более 500 символов",
+	"ma000082|key product id is invalid":                                                                  "некорректный идентификатор ключевого продукта",
+	"ma000083|review page identifier must be one of next values: primary_onboarding, merchant_onboarding": "идентификатор страницы отзыва должен быть одним из значений: primary_onboarding, merchant_onboarding",
+	"ma000083|platform id is invalid":                                                                     "некорректный идентификатор платформы",
+
+	"ma000084": "некорректный бренд",
+	"ma000085": "некорректный регион",
+	"ma000086": "некорректный город",
+	"ma000087": "некорректный адрес",
+	"ma000088": "необходимо указать контактные данные уполномоченного лица компании",
+	"ma000089": "необходимо указать технические контактные данные компании",
+	"ma000090": "некорректное имя",
+	"ma000091": "некорректный телефон",
+	"ma000092": "некорректное название банка",
+	"ma000093": "некорректный адрес банка",
+	"ma000094": "некорректный номер банковского счёта",
+	"ma000095": "некорректный swift код банка",
+	"ma000096": "некорректный корреспондентский счёт банка",
+	"ma000097": "не указан файл с ключами",
+	"ma000098": "не удалось прочитать файл",
+	"ma000099": "некорректный период",
+	"ma000100": "мерчант не найден",
+	"ma000101": "не удалось создать файл отчёта",
+	"ma000102": "не удалось скачать файл отчёта",
+	"ma000103": "локализованное поле имеет некорректный тип",
+	"ma000104": "поле обложки имеет некорректный тип",
+	"ma000105": "не удалось отправить приглашение",
+	"ma000106": "не удалось принять приглашение",
+	"ma000107": "не удалось проверить токен приглашения",
+	"ma000108": "некорректный тип роли",
+	"ma000109": "не удалось удалить пользователя",
+	"ma000110": "некорректный режим перенаправления",
+	"pr000111": "некорректный тип использования перенаправления",
+	"ma000111": "некорректный фильтр по дате",
+	"ma000112": "файл не найден в запросе загрузки документа мерчанта",
+	"ma000113": "размер загружаемого файла должен быть меньше 30МБ",
+	"ma000114": "неподдерживаемый тип файла",
+	"ma000115": "не удалось загрузить файл",
+	"ma000116": "не удалось скачать файл документа",
+	"ma000117": "ключ идемпотентности слишком длинный",
+	"ma000118": "ключ идемпотентности уже использован для другого запроса",
+	"ma000119": "запрос с тем же ключом идемпотентности ещё обрабатывается",
+	"ma000120": "слишком много запросов, повторите позже",
+	"ma000121": "некорректный запрос",
+	"ma000122": "требуется авторизация",
+	"ma000123": "не найдено",
+	"ma000124": "метод не поддерживается",
+	"ma000125": "не найдены заголовки подписи запроса",
+	"ma000126": "время запроса некорректно или выходит за допустимое расхождение часов",
+	"ma000127": "некорректный nonce запроса",
+	"ma000128": "nonce запроса уже был использован",
+	"ma000129": "некорректная подпись запроса",
+	"ma000130": "basic-аутентификация запрещена для проекта, подпишите запрос",
+	"ma000131": "ключ API не найден",
+	"ma000132": "срок действия ключа API истёк",
+	"ma000133": "у ключа API нет доступа к запросу",
+	"ma000134": "срок действия ключа API должен быть в будущем",
+	"ma000135": "пользователь не состоит в запрошенном мерчанте",
+	"ma000136": "запрос на подтверждение не найден",
+	"ma000137": "запрос на подтверждение уже подтверждён или отклонён",
+	"ma000138": "автор запроса на подтверждение не может подтвердить или отклонить его",
+	"ma000139": "сессия отозвана, войдите заново",
+	"ma000140": "сессия не найдена",
+	"ma000141": "курсор страницы недействителен или истёк",
+	"ma000142": "ключ идемпотентности обязателен для запроса",
+	"ma000143": "сумма возврата превышает доступную для возврата сумму заказа",
+	"ma000144": "строки массового возврата недействительны",
+	"ma000145": "массовый возврат содержит слишком много строк",
+	"ma000146": "задание массового возврата не найдено",
+	"ma000147": "задание массового возврата ещё выполняется",
+	"ma000148": "сервер останавливается, отправьте массовый возврат ещё раз",
+	"ma000149": "создаётся другой возврат заказа, повторите запрос позже",
+	"ma000150": "не удалось проверить сессию, повторите запрос позже",
+	"ma000151": "проект запроса токена не совпадает с проектом подписи",
+}
+
+var validationMessagesRu = map[string]string{
+	"required":         "{0} является обязательным полем",
+	"len":              "{0} должно иметь длину {1}",
+	"min":              "{0} должно быть не меньше {1}",
+	"max":              "{0} должно быть не больше {1}",
+	"eq":               "{0} должно быть равно {1}",
+	"ne":               "{0} не должно быть равно {1}",
+	"gt":               "{0} должно быть больше {1}",
+	"gte":              "{0} должно быть больше или равно {1}",
+	"lt":               "{0} должно быть меньше {1}",
+	"lte":              "{0} должно быть меньше или равно {1}",
+	"oneof":            "{0} должно быть одним из [{1}]",
+	"unique":           "{0} должно содержать уникальные значения",
+	"email":            "{0} должно быть корректным email адресом",
+	"url":              "{0} должно быть корректным URL",
+	"uuid":             "{0} должно быть корректным UUID",
+	"alpha":            "{0} может содержать только буквы",
+	"alphanum":         "{0} может содержать только буквы и цифры",
+	"numeric":          "{0} должно быть числом",
+	"hexadecimal":      "{0} должно быть шестнадцатеричной строкой",
+	"iso3166_1_alpha2": "{0} должно быть кодом страны ISO 3166-1 alpha-2",
+}
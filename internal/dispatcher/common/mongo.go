@@ -0,0 +1,48 @@
+package common
+
+import (
+	"github.com/globalsign/mgo"
+)
+
+const (
+	collectionApiKeys            = "management_api_keys"
+	collectionSessions           = "management_sessions"
+	collectionSessionRevocations = "management_session_revocations"
+	collectionApprovals          = "management_approvals"
+	collectionIdempotencyKeys    = "management_idempotency_keys"
+	collectionNonces             = "management_s2s_nonces"
+	collectionBulkRefunds        = "management_bulk_refunds"
+	collectionAudit              = "management_audit"
+	collectionRefundLocks        = "management_refund_locks"
+	collectionRefundReferences   = "management_refund_references"
+)
+
+// mongoCollection runs every operation on the copy of the session, so concurrent requests
+// don't share the socket of the root session
+type mongoCollection struct {
+	db   *mgo.Database
+	name string
+}
+
+func (c mongoCollection) with(fn func(collection *mgo.Collection) error) error {
+	session := c.db.Session.Copy()
+	defer session.Close()
+
+	return fn(c.db.With(session).C(c.name))
+}
+
+func (c mongoCollection) ensureIndexes(indexes ...mgo.Index) error {
+	return c.with(func(collection *mgo.Collection) error {
+		for _, index := range indexes {
+			if err := collection.EnsureIndex(index); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// isMongoNotFound
+func isMongoNotFound(err error) bool {
+	return err == mgo.ErrNotFound
+}
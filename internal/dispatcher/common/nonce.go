@@ -0,0 +1,92 @@
+package common
+
+import (
+	"github.com/globalsign/mgo"
+	"sync"
+	"time"
+)
+
+// NonceStore remembers the nonces of signed requests to reject the replayed ones
+type NonceStore interface {
+	// Add saves the nonce for ttl, added is false if the nonce is already known
+	Add(nonce string, ttl time.Duration) (added bool, err error)
+}
+
+// mongoNonce is the stored nonce, the document is removed after expireAt
+type mongoNonce struct {
+	Nonce    string    `bson:"_id"`
+	ExpireAt time.Time `bson:"expire_at"`
+}
+
+type mongoNonceStore struct {
+	nonces mongoCollection
+}
+
+// NewMongoNonceStore returns the storage shared by all instances, so the request replayed to another instance
+// is rejected too
+func NewMongoNonceStore(db *mgo.Database) (NonceStore, error) {
+	s := &mongoNonceStore{
+		nonces: mongoCollection{db: db, name: collectionNonces},
+	}
+
+	if err := s.nonces.ensureIndexes(mgo.Index{Key: []string{"expire_at"}, ExpireAfter: time.Second}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoNonceStore) Add(nonce string, ttl time.Duration) (bool, error) {
+	err := s.nonces.with(func(c *mgo.Collection) error {
+		return c.Insert(&mongoNonce{Nonce: nonce, ExpireAt: time.Now().UTC().Add(ttl)})
+	})
+
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+type memoryNonceStore struct {
+	mx        sync.Mutex
+	nonces    map[string]time.Time
+	lastEvict time.Time
+}
+
+// NewMemoryNonceStore returns the in-memory nonce storage
+func NewMemoryNonceStore() NonceStore {
+	return &memoryNonceStore{
+		nonces:    make(map[string]time.Time),
+		lastEvict: time.Now(),
+	}
+}
+
+func (s *memoryNonceStore) Add(nonce string, ttl time.Duration) (bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now()
+	s.evict(now)
+
+	if expireAt, ok := s.nonces[nonce]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+
+	s.nonces[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+func (s *memoryNonceStore) evict(now time.Time) {
+	if now.Sub(s.lastEvict) < time.Minute {
+		return
+	}
+
+	for nonce, expireAt := range s.nonces {
+		if !now.Before(expireAt) {
+			delete(s.nonces, nonce)
+		}
+	}
+
+	s.lastEvict = now
+}
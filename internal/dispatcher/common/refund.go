@@ -0,0 +1,179 @@
+package common
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"sync"
+	"time"
+)
+
+// RefundReference is the merchant's reference of the refund created by the merchant's server,
+// billing doesn't keep it
+type RefundReference struct {
+	RefundId          string    `json:"refund_id" bson:"_id"`
+	OrderId           string    `json:"order_id" bson:"order_id"`
+	MerchantReference string    `json:"merchant_reference" bson:"merchant_reference"`
+	CreatedAt         time.Time `json:"created_at" bson:"created_at"`
+}
+
+// RefundStore keeps the merchant's references of the refunds and locks the orders while their refunds are created
+type RefundStore interface {
+	// Lock reserves the order for the refund creation, locked is false if the order is already locked.
+	// The lock is released after ttl if Unlock isn't called.
+	Lock(orderId string, ttl time.Duration) (locked bool, err error)
+	Unlock(orderId string) error
+	AddReference(ref *RefundReference) error
+	// References returns the merchant's references by the refund id, the refunds without the reference are skipped
+	References(refundIds []string) (map[string]string, error)
+}
+
+// mongoRefundLock is the lock of the order, the document is removed after expireAt
+type mongoRefundLock struct {
+	OrderId  string    `bson:"_id"`
+	ExpireAt time.Time `bson:"expire_at"`
+}
+
+type mongoRefundStore struct {
+	locks      mongoCollection
+	references mongoCollection
+}
+
+// NewMongoRefundStore returns the storage shared by all instances, so the refunds of the order
+// are created one by one whichever instance receives them
+func NewMongoRefundStore(db *mgo.Database) (RefundStore, error) {
+	s := &mongoRefundStore{
+		locks:      mongoCollection{db: db, name: collectionRefundLocks},
+		references: mongoCollection{db: db, name: collectionRefundReferences},
+	}
+
+	if err := s.locks.ensureIndexes(mgo.Index{Key: []string{"expire_at"}, ExpireAfter: time.Second}); err != nil {
+		return nil, err
+	}
+
+	indexes := []mgo.Index{
+		{Key: []string{"order_id"}},
+		{Key: []string{"merchant_reference"}},
+	}
+
+	if err := s.references.ensureIndexes(indexes...); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoRefundStore) Lock(orderId string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	err := s.locks.with(func(c *mgo.Collection) error {
+		// the expired lock may be not removed yet by the TTL monitor
+		if err := c.Remove(bson.M{"_id": orderId, "expire_at": bson.M{"$lte": now}}); err != nil && !isMongoNotFound(err) {
+			return err
+		}
+		return c.Insert(&mongoRefundLock{OrderId: orderId, ExpireAt: now.Add(ttl)})
+	})
+
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (s *mongoRefundStore) Unlock(orderId string) error {
+	err := s.locks.with(func(c *mgo.Collection) error {
+		return c.RemoveId(orderId)
+	})
+
+	if isMongoNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *mongoRefundStore) AddReference(ref *RefundReference) error {
+	return s.references.with(func(c *mgo.Collection) error {
+		return c.Insert(ref)
+	})
+}
+
+func (s *mongoRefundStore) References(refundIds []string) (map[string]string, error) {
+	var refs []*RefundReference
+
+	err := s.references.with(func(c *mgo.Collection) error {
+		return c.Find(bson.M{"_id": bson.M{"$in": refundIds}}).All(&refs)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	references := make(map[string]string, len(refs))
+
+	for _, ref := range refs {
+		references[ref.RefundId] = ref.MerchantReference
+	}
+
+	return references, nil
+}
+
+type memoryRefundStore struct {
+	mx         sync.Mutex
+	locks      map[string]time.Time
+	references map[string]*RefundReference
+}
+
+// NewMemoryRefundStore returns the in-memory refund storage
+func NewMemoryRefundStore() RefundStore {
+	return &memoryRefundStore{
+		locks:      make(map[string]time.Time),
+		references: make(map[string]*RefundReference),
+	}
+}
+
+func (s *memoryRefundStore) Lock(orderId string, ttl time.Duration) (bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now()
+
+	if expireAt, ok := s.locks[orderId]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+
+	s.locks[orderId] = now.Add(ttl)
+	return true, nil
+}
+
+func (s *memoryRefundStore) Unlock(orderId string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	delete(s.locks, orderId)
+	return nil
+}
+
+func (s *memoryRefundStore) AddReference(ref *RefundReference) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	copyRef := *ref
+	s.references[ref.RefundId] = &copyRef
+	return nil
+}
+
+func (s *memoryRefundStore) References(refundIds []string) (map[string]string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	references := make(map[string]string)
+
+	for _, id := range refundIds {
+		if ref, ok := s.references[id]; ok {
+			references[id] = ref.MerchantReference
+		}
+	}
+
+	return references, nil
+}
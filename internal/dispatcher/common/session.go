@@ -0,0 +1,410 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session is the access token of the user seen by the API, the token itself isn't kept
+type Session struct {
+	Id        string `json:"id"`
+	UserId    string `json:"-"`
+	UserAgent string `json:"user_agent"`
+	RemoteIp  string `json:"remote_ip"`
+	// CreatedAt is the issue time of the token or the time it was seen first if the token isn't JWT
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current is true for the session of the request
+	Current bool `json:"current"`
+}
+
+// SessionStore keeps the active sessions of the users and the revoked ones
+type SessionStore interface {
+	// Touch saves the session or updates its last seen time, revoked is true if the session was revoked
+	Touch(session *Session) (revoked bool, err error)
+	// List returns the active sessions of the user, the latest seen first
+	List(userId string) ([]*Session, error)
+	// Revoke revokes the session of the user, revoked is false if the user has no such session
+	Revoke(userId, sessionId string) (revoked bool, err error)
+	// RevokeUser revokes all sessions of the user except the given one. Without the exception the tokens
+	// issued before the call are rejected even if they weren't seen yet.
+	RevokeUser(userId, exceptSessionId string) error
+}
+
+// SessionId returns the identifier of the session of the token
+func SessionId(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:16])
+}
+
+// NewSession
+func NewSession(token, userId, userAgent, remoteIp string) *Session {
+	now := time.Now().UTC()
+	createdAt, ok := TokenIssuedAt(token)
+
+	if !ok || createdAt.After(now) {
+		createdAt = now
+	}
+
+	return &Session{
+		Id:         SessionId(token),
+		UserId:     userId,
+		UserAgent:  userAgent,
+		RemoteIp:   remoteIp,
+		CreatedAt:  createdAt,
+		LastSeenAt: now,
+	}
+}
+
+// TokenIssuedAt reads the iat claim of JWT, the token must be verified by the caller
+func TokenIssuedAt(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	claims := struct {
+		IssuedAt int64 `json:"iat"`
+	}{}
+
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.IssuedAt <= 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.IssuedAt, 0).UTC(), true
+}
+
+// mongoSession is the stored session, the document is removed after expireAt
+type mongoSession struct {
+	Id         string     `bson:"_id"`
+	UserId     string     `bson:"user_id"`
+	UserAgent  string     `bson:"user_agent"`
+	RemoteIp   string     `bson:"remote_ip"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	LastSeenAt time.Time  `bson:"last_seen_at"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty"`
+	ExpireAt   time.Time  `bson:"expire_at"`
+}
+
+// mongoUserRevocation is the time of the last revocation of all sessions of the user
+type mongoUserRevocation struct {
+	UserId    string    `bson:"_id"`
+	RevokedAt time.Time `bson:"revoked_at"`
+	ExpireAt  time.Time `bson:"expire_at"`
+}
+
+type mongoSessionStore struct {
+	ttl         time.Duration
+	sessions    mongoCollection
+	revocations mongoCollection
+}
+
+// NewMongoSessionStore returns the session storage shared by all instances, so the revocation is applied by
+// every instance at once. The sessions not seen for ttl and the revocations older than ttl are removed by Mongo.
+func NewMongoSessionStore(db *mgo.Database, ttl time.Duration) (SessionStore, error) {
+	s := &mongoSessionStore{
+		ttl:         ttl,
+		sessions:    mongoCollection{db: db, name: collectionSessions},
+		revocations: mongoCollection{db: db, name: collectionSessionRevocations},
+	}
+
+	expire := mgo.Index{Key: []string{"expire_at"}, ExpireAfter: time.Second}
+
+	if err := s.sessions.ensureIndexes(expire, mgo.Index{Key: []string{"user_id", "-last_seen_at"}}); err != nil {
+		return nil, err
+	}
+
+	if err := s.revocations.ensureIndexes(expire); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *mongoSessionStore) Touch(session *Session) (bool, error) {
+	now := time.Now().UTC()
+	current := &mongoSession{}
+
+	err := s.sessions.with(func(c *mgo.Collection) error {
+		return c.FindId(session.Id).One(current)
+	})
+
+	if err != nil && !isMongoNotFound(err) {
+		return false, err
+	}
+
+	if err == nil && current.RevokedAt != nil {
+		return true, nil
+	}
+
+	createdAt := session.CreatedAt
+
+	if err == nil {
+		createdAt = current.CreatedAt
+	}
+
+	insert := bson.M{"user_id": session.UserId, "created_at": createdAt}
+	revocation := &mongoUserRevocation{}
+
+	err = s.revocations.with(func(c *mgo.Collection) error {
+		return c.FindId(session.UserId).One(revocation)
+	})
+
+	if err != nil && !isMongoNotFound(err) {
+		return false, err
+	}
+
+	if err == nil && !createdAt.After(revocation.RevokedAt) {
+		insert["user_agent"] = session.UserAgent
+		insert["remote_ip"] = session.RemoteIp
+		insert["last_seen_at"] = now
+
+		err = s.sessions.with(func(c *mgo.Collection) error {
+			_, err := c.UpsertId(session.Id, bson.M{
+				"$set":         bson.M{"revoked_at": revocation.RevokedAt, "expire_at": now.Add(s.ttl)},
+				"$setOnInsert": insert,
+			})
+			return err
+		})
+
+		return true, err
+	}
+
+	err = s.sessions.with(func(c *mgo.Collection) error {
+		_, err := c.UpsertId(session.Id, bson.M{
+			"$set": bson.M{
+				"user_agent":   session.UserAgent,
+				"remote_ip":    session.RemoteIp,
+				"last_seen_at": now,
+				"expire_at":    now.Add(s.ttl),
+			},
+			"$setOnInsert": insert,
+		})
+		return err
+	})
+
+	return false, err
+}
+
+func (s *mongoSessionStore) List(userId string) ([]*Session, error) {
+	var stored []*mongoSession
+
+	err := s.sessions.with(func(c *mgo.Collection) error {
+		return c.Find(bson.M{"user_id": userId, "revoked_at": nil}).Sort("-last_seen_at").All(&stored)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(stored))
+
+	for _, session := range stored {
+		sessions = append(sessions, &Session{
+			Id:         session.Id,
+			UserId:     session.UserId,
+			UserAgent:  session.UserAgent,
+			RemoteIp:   session.RemoteIp,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+func (s *mongoSessionStore) Revoke(userId, sessionId string) (bool, error) {
+	now := time.Now().UTC()
+
+	err := s.sessions.with(func(c *mgo.Collection) error {
+		return c.Update(
+			bson.M{"_id": sessionId, "user_id": userId, "revoked_at": nil},
+			bson.M{"$set": bson.M{"revoked_at": now, "expire_at": now.Add(s.ttl)}},
+		)
+	})
+
+	if isMongoNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *mongoSessionStore) RevokeUser(userId, exceptSessionId string) error {
+	now := time.Now().UTC()
+
+	err := s.sessions.with(func(c *mgo.Collection) error {
+		_, err := c.UpdateAll(
+			bson.M{"user_id": userId, "_id": bson.M{"$ne": exceptSessionId}, "revoked_at": nil},
+			bson.M{"$set": bson.M{"revoked_at": now, "expire_at": now.Add(s.ttl)}},
+		)
+		return err
+	})
+
+	if err != nil || exceptSessionId != "" {
+		return err
+	}
+
+	return s.revocations.with(func(c *mgo.Collection) error {
+		_, err := c.UpsertId(userId, bson.M{"$set": bson.M{"revoked_at": now, "expire_at": now.Add(s.ttl)}})
+		return err
+	})
+}
+
+type memorySession struct {
+	Session
+	revokedAt *time.Time
+}
+
+type memorySessionStore struct {
+	mx       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*memorySession
+	// revokedAt is the time of the last revocation of all sessions of the user
+	revokedAt map[string]time.Time
+	lastEvict time.Time
+}
+
+// NewMemorySessionStore returns the in-memory session storage for tests, the sessions not seen for ttl and
+// the revocations older than ttl are forgotten, so ttl must exceed the lifetime of the access token
+func NewMemorySessionStore(ttl time.Duration) SessionStore {
+	return &memorySessionStore{
+		ttl:       ttl,
+		sessions:  make(map[string]*memorySession),
+		revokedAt: make(map[string]time.Time),
+		lastEvict: time.Now(),
+	}
+}
+
+func (s *memorySessionStore) Touch(session *Session) (bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now().UTC()
+	s.evict(now)
+
+	current, ok := s.sessions[session.Id]
+
+	if !ok {
+		cp := *session
+		cp.Current = false
+		current = &memorySession{Session: cp}
+		s.sessions[session.Id] = current
+	}
+
+	if current.revokedAt == nil {
+		if revokedAt, ok := s.revokedAt[current.UserId]; ok && !current.CreatedAt.After(revokedAt) {
+			current.revokedAt = &revokedAt
+		}
+	}
+
+	if current.revokedAt != nil {
+		return true, nil
+	}
+
+	current.UserAgent = session.UserAgent
+	current.RemoteIp = session.RemoteIp
+	current.LastSeenAt = now
+
+	return false, nil
+}
+
+func (s *memorySessionStore) List(userId string) ([]*Session, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	sessions := []*Session{}
+
+	for _, session := range s.sessions {
+		if session.UserId == userId && session.revokedAt == nil {
+			cp := session.Session
+			sessions = append(sessions, &cp)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *memorySessionStore) Revoke(userId, sessionId string) (bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	session, ok := s.sessions[sessionId]
+
+	if !ok || session.UserId != userId || session.revokedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	session.revokedAt = &now
+
+	return true, nil
+}
+
+func (s *memorySessionStore) RevokeUser(userId, exceptSessionId string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now().UTC()
+
+	for id, session := range s.sessions {
+		if session.UserId == userId && id != exceptSessionId && session.revokedAt == nil {
+			session.revokedAt = &now
+		}
+	}
+
+	if exceptSessionId == "" {
+		s.revokedAt[userId] = now
+	}
+
+	return nil
+}
+
+func (s *memorySessionStore) evict(now time.Time) {
+	if now.Sub(s.lastEvict) < time.Minute {
+		return
+	}
+
+	for id, session := range s.sessions {
+		lastAt := session.LastSeenAt
+
+		if session.revokedAt != nil && session.revokedAt.After(lastAt) {
+			lastAt = *session.revokedAt
+		}
+
+		if now.Sub(lastAt) >= s.ttl {
+			delete(s.sessions, id)
+		}
+	}
+
+	for userId, revokedAt := range s.revokedAt {
+		if now.Sub(revokedAt) >= s.ttl {
+			delete(s.revokedAt, userId)
+		}
+	}
+
+	s.lastEvict = now
+}
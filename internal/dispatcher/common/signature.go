@@ -0,0 +1,36 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// S2SCanonicalRequest builds the string signed by the merchant server:
+// method, path, sorted query, timestamp, nonce and hex encoded SHA-256 of the body separated by new lines
+func S2SCanonicalRequest(method, path string, query url.Values, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		query.Encode(),
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// S2SSignature returns hex encoded HMAC-SHA256 of the canonical request with the project secret key
+func S2SSignature(secret, canonicalRequest string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonicalRequest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// S2SSignatureEqual compares the signatures in constant time
+func S2SSignatureEqual(expected, actual string) bool {
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(actual)))
+}
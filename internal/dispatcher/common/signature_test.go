@@ -0,0 +1,53 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	signatureTestBody      = `{"amount":10}`
+	signatureTestSignature = "3212c5cfe941597ded6bfafb05c61f724b1c2fccef7f69188d198e87f561e2f8"
+)
+
+func TestS2SCanonicalRequest(t *testing.T) {
+	query := url.Values{"b": []string{"2"}, "a": []string{"1"}}
+	canonical := S2SCanonicalRequest("post", "/api/v1/s2s/order/refunds", query, "1600000000", "nonce-1", []byte(signatureTestBody))
+
+	assert.Equal(
+		t,
+		"POST\n/api/v1/s2s/order/refunds\na=1&b=2\n1600000000\nnonce-1\n"+
+			"a8b88b82fe90a16048eb8851fe382405395cd395dafaa7ca9be90ec00f82a72b",
+		canonical,
+	)
+}
+
+func TestS2SSignature(t *testing.T) {
+	query := url.Values{"a": []string{"1"}, "b": []string{"2"}}
+	canonical := S2SCanonicalRequest("POST", "/api/v1/s2s/order/refunds", query, "1600000000", "nonce-1", []byte(signatureTestBody))
+
+	assert.Equal(t, signatureTestSignature, S2SSignature("project_secret", canonical))
+	assert.NotEqual(t, signatureTestSignature, S2SSignature("another_secret", canonical))
+}
+
+func TestS2SSignatureEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   string
+		expected bool
+	}{
+		{name: "equal", actual: signatureTestSignature, expected: true},
+		{name: "upper case", actual: strings.ToUpper(signatureTestSignature), expected: true},
+		{name: "another signature", actual: strings.Repeat("0", len(signatureTestSignature)), expected: false},
+		{name: "truncated", actual: signatureTestSignature[1:], expected: false},
+		{name: "empty", actual: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, S2SSignatureEqual(signatureTestSignature, tt.actual))
+		})
+	}
+}
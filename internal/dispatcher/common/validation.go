@@ -0,0 +1,140 @@
+package common
+
+import (
+	"fmt"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"gopkg.in/go-playground/validator.v9"
+	enTranslations "gopkg.in/go-playground/validator.v9/translations/en"
+	"reflect"
+	"strings"
+)
+
+const (
+	ValidationDefaultLocale = "en"
+)
+
+var (
+	validationTranslators = ut.New(en.New(), en.New(), ru.New(), de.New())
+
+	// validationMessages contains the messages of the validation tags by locale, {0} is the field and {1}
+	// is the tag parameter. English messages are the default ones of the validator.
+	validationMessages = map[string]map[string]string{
+		LocaleRu: validationMessagesRu,
+		LocaleDe: validationMessagesDe,
+	}
+)
+
+// RegisterValidationTranslations registers json field names and localized messages of the validation errors
+func RegisterValidationTranslations(validate *validator.Validate) error {
+	validate.RegisterTagNameFunc(ValidationJsonFieldName)
+
+	if err := enTranslations.RegisterDefaultTranslations(validate, ValidationTranslator(ValidationDefaultLocale)); err != nil {
+		return err
+	}
+
+	for locale, messages := range validationMessages {
+		trans := ValidationTranslator(locale)
+
+		for tag, message := range messages {
+			if err := validate.RegisterTranslation(tag, trans, registerValidationMessage(tag, message), translateValidationMessage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func registerValidationMessage(tag, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translateValidationMessage(trans ut.Translator, fe validator.FieldError) string {
+	message, err := trans.T(fe.Tag(), fe.Field(), fe.Param())
+
+	if err != nil {
+		return fe.Error()
+	}
+
+	return message
+}
+
+// ValidationTranslator returns the translator for the locale, the default one if locale is unknown
+func ValidationTranslator(locale string) ut.Translator {
+	trans, _ := validationTranslators.FindTranslator(locale, ValidationDefaultLocale)
+	return validationTranslator{trans}
+}
+
+// validationTranslator skips the translations which are already added. The translators are shared by
+// the validators, every validator registers the same translations when it's created.
+type validationTranslator struct {
+	ut.Translator
+}
+
+func (t validationTranslator) Add(key interface{}, text string, override bool) error {
+	return skipConflictingTranslation(t.Translator.Add(key, text, override))
+}
+
+func (t validationTranslator) AddCardinal(key interface{}, text string, rule locales.PluralRule, override bool) error {
+	return skipConflictingTranslation(t.Translator.AddCardinal(key, text, rule, override))
+}
+
+func skipConflictingTranslation(err error) error {
+	if _, ok := err.(*ut.ErrConflictingTranslation); ok {
+		return nil
+	}
+	return err
+}
+
+// ValidationJsonFieldName returns the field name in request JSON
+func ValidationJsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// NewFieldErrors returns all failed fields with the messages translated by trans
+func NewFieldErrors(vErrs validator.ValidationErrors, trans ut.Translator) []*FieldError {
+	errs := make([]*FieldError, 0, len(vErrs))
+
+	for _, vErr := range vErrs {
+		path := ValidationFieldPath(vErr)
+		message := vErr.Translate(trans)
+
+		// tags without translation (custom validators) return the raw validator error
+		if message == vErr.Error() {
+			message = fmt.Sprintf(ErrorMessageMask, path, vErr.Tag())
+		}
+
+		errs = append(errs, &FieldError{
+			Field:   path,
+			Tag:     vErr.Tag(),
+			Param:   vErr.Param(),
+			Message: message,
+		})
+	}
+
+	return errs
+}
+
+// ValidationFieldPath returns the path of the failed field in request JSON, i.e. "company.name"
+func ValidationFieldPath(vErr validator.FieldError) string {
+	namespace := vErr.Namespace()
+
+	// the first namespace element is the name of the validated struct type
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
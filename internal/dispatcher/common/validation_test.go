@@ -0,0 +1,26 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/go-playground/validator.v9"
+	"testing"
+)
+
+func TestRegisterValidationTranslations_SeveralValidators(t *testing.T) {
+	req := struct {
+		Name string `json:"name" validate:"required,min=3"`
+	}{Name: "ab"}
+
+	for i := 0; i < 2; i++ {
+		validate := validator.New()
+		assert.NoError(t, RegisterValidationTranslations(validate))
+
+		err := validate.Struct(req)
+		assert.Error(t, err)
+
+		fields := NewFieldErrors(err.(validator.ValidationErrors), ValidationTranslator(LocaleEn))
+		assert.Len(t, fields, 1)
+		assert.Equal(t, "name", fields[0].Field)
+		assert.Equal(t, "name must be at least 3 characters in length", fields[0].Message)
+	}
+}
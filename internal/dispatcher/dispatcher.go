@@ -10,14 +10,18 @@ import (
 	"github.com/alexeyco/simpletable"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/micro/go-micro/client"
+	"github.com/paysuper/paysuper-management-api/internal/casbin"
 	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
 	"github.com/paysuper/paysuper-management-api/pkg/micro"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
 	"html/template"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // Dispatcher
@@ -28,6 +32,13 @@ type Dispatcher struct {
 	provider.LMT
 	globalCfg *common.Config
 	ms        *micro.Micro
+
+	healthClient     client.Client
+	healthClientOnce sync.Once
+	// set to 1 when the server is draining before shutdown
+	draining int32
+	// embedded casbin enforcer, nil if the policy is enforced by the remote service only
+	enforcer *casbin.LocalEnforcer
 }
 
 // dispatch
@@ -37,19 +48,35 @@ func (d *Dispatcher) Dispatch(echoHttp *echo.Echo) error {
 		return e
 	}
 	echoHttp.Renderer = common.NewTemplate(t)
+
+	trustedProxies, e := parseTrustedProxies(d.globalCfg.RateLimitTrustedProxies)
+	if e != nil {
+		return e
+	}
+	echoHttp.HTTPErrorHandler = d.HTTPErrorHandler
+
+	if !d.globalCfg.DisableCasbinPolicy && (d.globalCfg.CasbinMode == common.CasbinModeLocal || d.globalCfg.CasbinShadow) {
+		if e := d.initLocalEnforcer(); e != nil {
+			return e
+		}
+	}
+
 	echoHttp.Binder = &common.Binder{
 		LimitDefault:  int64(d.globalCfg.LimitDefault),
 		OffsetDefault: int64(d.globalCfg.OffsetDefault),
 		LimitMax:      int64(d.globalCfg.LimitMax),
 	}
 	// Called after routes
+	echoHttp.Use(d.RequestIdMiddleware) // 6
 	echoHttp.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
 		Output: logger.NewLevelWriter(d.L(), logger.LevelInfo),
 		Format: `{"id":"${id}","remote_ip":"${remote_ip}",` +
 			`"host":"${host}","method":"${method}","uri":"${uri}","user_agent":"${user_agent}",` +
 			`"status":${status},"error":"${error}","latency":${latency},"latency_human":"${latency_human}"` +
 			`,"bytes_in":${bytes_in},"bytes_out":${bytes_out}}`,
-	})) // 3
+	})) // 5
+	echoHttp.Use(d.MetricsMiddleware) // 4
+	echoHttp.Use(d.TracingMiddleware) // 3
 
 	allowOrigins := strings.Split(d.globalCfg.AllowOrigin, ",")
 
@@ -57,31 +84,41 @@ func (d *Dispatcher) Dispatch(echoHttp *echo.Echo) error {
 	echoHttp.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     allowOrigins,
 		AllowCredentials: true,
-		AllowHeaders:     []string{"authorization", "content-type"},
-		ExposeHeaders:    []string{"authorization", "content-type", "set-cookie", "cookie"},
+		AllowHeaders:     []string{"authorization", "content-type", "idempotency-key", "traceparent", "x-request-id", "x-merchant-id"},
+		ExposeHeaders: []string{
+			"authorization", "content-type", "set-cookie", "cookie", "retry-after",
+			"x-ratelimit-limit", "x-ratelimit-remaining", "x-ratelimit-reset", "x-request-id", "link",
+		},
 	})) // 1
 	// Called before routes
 	echoHttp.Use(d.RawBodyPreMiddleware)         // 2
+	echoHttp.Use(d.PageCursorPreMiddleware)      // 1
 	echoHttp.Use(d.LimitOffsetSortPreMiddleware) // 1
 	// init group routes
 	grp := &common.Groups{
-		AuthProject: echoHttp.Group(common.AuthProjectGroupPath),
-		AuthUser:    echoHttp.Group(common.AuthUserGroupPath),
-		WebHooks:    echoHttp.Group(common.WebHookGroupPath),
-		Common:      echoHttp.Group(common.NoAuthGroupPath),
-		SystemUser:  echoHttp.Group(common.SystemUserGroupPath),
-		MerchantS2S: echoHttp.Group(common.MerchantS2SGroupPath),
+		AuthProject:  echoHttp.Group(common.AuthProjectGroupPath),
+		AuthUser:     echoHttp.Group(common.AuthUserGroupPath),
+		WebHooks:     echoHttp.Group(common.WebHookGroupPath),
+		Common:       echoHttp.Group(common.NoAuthGroupPath),
+		SystemUser:   echoHttp.Group(common.SystemUserGroupPath),
+		MerchantS2S:  echoHttp.Group(common.MerchantS2SGroupPath),
+		Idempotency:  d.IdempotencyMiddleware,
+		S2SSignature: d.S2SSignatureMiddleware,
 	}
 	d.authProjectGroup(grp.AuthProject)
 	d.authUserGroup(grp.AuthUser)
 	d.systemUserGroup(grp.SystemUser)
 	d.webHookGroup(grp.WebHooks)
 	d.merchantS2SGroup(grp.MerchantS2S)
+	d.commonGroup(grp.Common, trustedProxies)
 
 	// init routes
 	for _, handler := range d.appSet.Handlers {
 		handler.Route(grp)
 	}
+	echoHttp.GET(common.LivenessPath, d.LivenessHandler)
+	echoHttp.GET(common.ReadinessPath, d.ReadinessHandler)
+
 	if d.cfg.PathRouteDump != "" {
 		d.dumpRoutesToFile(echoHttp)
 	}
@@ -160,8 +197,11 @@ func (d *Dispatcher) authUserGroup(grp *echo.Group) {
 	if !d.globalCfg.DisableAuthMiddleware {
 		grp.Use(d.GetUserDetailsMiddleware)
 		grp.Use(d.AuthOneMerchantPreMiddleware())
+		grp.Use(d.MerchantSelectorMiddleware)
 	}
 
+	grp.Use(d.RateLimitMiddleware(d.globalCfg.RateLimitAuthUser, d.globalCfg.RateLimitAuthUserBurst, RateLimitKeyMerchant))
+
 	if !d.globalCfg.DisableCasbinPolicy {
 		grp.Use(d.CasbinMiddleware(func(c echo.Context) string {
 			user := common.ExtractUserContext(c)
@@ -170,6 +210,7 @@ func (d *Dispatcher) authUserGroup(grp *echo.Group) {
 	}
 
 	grp.Use(d.MerchantBinderPreMiddleware)
+	grp.Use(d.IdempotencyMiddleware)
 }
 
 func (d *Dispatcher) systemUserGroup(grp *echo.Group) {
@@ -178,13 +219,20 @@ func (d *Dispatcher) systemUserGroup(grp *echo.Group) {
 		grp.Use(d.GetUserDetailsMiddleware)
 	}
 
+	grp.Use(d.RateLimitMiddleware(d.globalCfg.RateLimitSystemUser, d.globalCfg.RateLimitSystemUserBurst, RateLimitKeyUser))
+
+	var enforce echo.MiddlewareFunc
+
 	if !d.globalCfg.DisableCasbinPolicy {
-		grp.Use(d.CasbinMiddleware(func(c echo.Context) string {
+		enforce = d.CasbinMiddleware(func(c echo.Context) string {
 			user := common.ExtractUserContext(c)
 			return user.Id
-		}))
+		})
+		grp.Use(enforce)
 	}
 
+	grp.Use(d.AuditMiddleware())
+	grp.Use(d.ApprovalMiddleware(enforce))
 	grp.Use(d.SystemBinderPreMiddleware)
 }
 
@@ -198,7 +246,18 @@ func (d *Dispatcher) merchantS2SGroup(grp *echo.Group) {
 	if !d.globalCfg.DisableAuthMiddleware {
 		grp.Use(d.S2SAuthPreMiddleware()) // 1
 	}
-	grp.Use(d.MerchantBinderPreMiddleware) // 2
+	rateLimit := d.RateLimitMiddleware(d.globalCfg.RateLimitMerchantS2S, d.globalCfg.RateLimitMerchantS2SBurst, RateLimitKeyProject)
+	grp.Use(rateLimit)                     // 2
+	grp.Use(d.MerchantBinderPreMiddleware) // 3
+	grp.Use(d.IdempotencyMiddleware)       // 4
+}
+
+func (d *Dispatcher) commonGroup(grp *echo.Group, trustedProxies []*net.IPNet) {
+	// Called before routes
+	// The requests aren't authenticated before routes, so the idempotency keys have no owner here,
+	// the routes apply Groups.Idempotency after the request is authenticated
+	rateLimit := d.RateLimitMiddleware(d.globalCfg.RateLimitCommon, d.globalCfg.RateLimitCommonBurst, RateLimitKeyClientIp(trustedProxies))
+	grp.Use(rateLimit) // 1
 }
 
 // Config
@@ -221,9 +280,17 @@ func (c *Config) Reload(ctx context.Context) {
 
 // AppSet
 type AppSet struct {
-	Handlers    common.Handlers
-	Services    common.Services
-	JwtVerifier *jwtverifier.JwtVerifier
+	Handlers         common.Handlers
+	Services         common.Services
+	JwtVerifier      *jwtverifier.JwtVerifier
+	IdempotencyStore common.IdempotencyStore
+	NonceStore       common.NonceStore
+	ApiKeyStore      common.ApiKeyStore
+	AuthCache        common.AuthCache
+	SessionStore     common.SessionStore
+	AuditStore       common.AuditStore
+	ApprovalStore    common.ApprovalStore
+	BackgroundTasks  *common.BackgroundTasks
 }
 
 // New
@@ -0,0 +1,43 @@
+package dispatcher
+
+import (
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"net/http"
+)
+
+// HTTPErrorHandler sends every error as JSON envelope, browsers get the HTML error page
+func (d *Dispatcher) HTTPErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		return
+	}
+
+	locale := common.RequestLocale(ctx)
+	status, envelope := common.NewErrorEnvelope(err, locale)
+	envelope.RequestId = common.ExtractRequestId(ctx)
+	ctx.Response().Header().Set(common.HeaderContentLanguage, locale)
+
+	if _, ok := err.(*echo.HTTPError); !ok && status >= http.StatusInternalServerError {
+		common.RequestLogger(ctx, d.L()).Error(
+			common.InternalErrorTemplate,
+			logger.PairArgs("path", ctx.Path()),
+			logger.WithPrettyFields(logger.Fields{"err": err}),
+		)
+	}
+
+	var e error
+
+	switch {
+	case ctx.Request().Method == http.MethodHead:
+		e = ctx.NoContent(status)
+	case common.PrefersHTML(ctx.Request().Header.Get(echo.HeaderAccept)):
+		e = ctx.Render(status, common.ErrorTemplateName, envelope)
+	default:
+		e = ctx.JSON(status, envelope)
+	}
+
+	if e != nil {
+		common.RequestLogger(ctx, d.L()).Error("error response send failed", logger.WithPrettyFields(logger.Fields{"err": e}))
+	}
+}
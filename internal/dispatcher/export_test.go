@@ -0,0 +1,75 @@
+package dispatcher
+
+import (
+	jwtverifier "github.com/ProtocolONE/authone-jwt-verifier-golang"
+	"github.com/labstack/echo/v4"
+	"github.com/micro/go-micro/client"
+	"github.com/paysuper/paysuper-management-api/internal/casbin"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"time"
+)
+
+// AppSetForTest gives the tests access to the stores of the dispatcher
+func (d *Dispatcher) AppSetForTest() *AppSet {
+	return &d.appSet
+}
+
+// GlobalConfigForTest
+func (d *Dispatcher) GlobalConfigForTest() *common.Config {
+	return d.globalCfg
+}
+
+// CacheUserForTest makes the token valid for the user without the introspection
+func (d *Dispatcher) CacheUserForTest(token string, user *jwtverifier.UserInfo) {
+	d.appSet.AuthCache.Set(common.AuthCacheTokenKey(token), &cachedToken{user: user}, time.Hour)
+}
+
+// SetEnforcerForTest replaces the embedded casbin enforcer
+func (d *Dispatcher) SetEnforcerForTest(enforcer *casbin.LocalEnforcer) {
+	d.enforcer = enforcer
+}
+
+// CasbinMiddlewareForTest builds the casbin middleware with the remote enforcer middleware given by the test
+func (d *Dispatcher) CasbinMiddlewareForTest(fn func(c echo.Context) string, remote echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return d.casbinMiddleware(fn, remote)
+}
+
+// CasbinShadowDisagreementsForTest returns the value of the shadow mode disagreements counter
+func CasbinShadowDisagreementsForTest() float64 {
+	return testutil.ToFloat64(casbinShadowDisagreementsTotal)
+}
+
+// RateLimitAllowedForTest takes the token of the key at every time from the new limiter and returns the decisions
+func RateLimitAllowedForTest(rate float64, burst int, key string, times ...time.Time) []bool {
+	limiter := newRateLimiter(rate, burst)
+	allowed := make([]bool, 0, len(times))
+
+	for _, at := range times {
+		allowed = append(allowed, limiter.take(key, at).allowed)
+	}
+
+	return allowed
+}
+
+// RateLimitKeyClientIpForTest returns the key of the request forwarded by the comma separated trusted proxies
+func RateLimitKeyClientIpForTest(proxies string, ctx echo.Context) (string, error) {
+	trusted, err := parseTrustedProxies(proxies)
+
+	if err != nil {
+		return "", err
+	}
+
+	return RateLimitKeyClientIp(trusted)(ctx), nil
+}
+
+// SetHealthClientForTest replaces the client which pings the services without the typed client
+func (d *Dispatcher) SetHealthClientForTest(c client.Client) {
+	d.healthClientOnce.Do(func() {})
+	d.healthClient = c
+}
+
+// ServiceRespondedForTest
+func ServiceRespondedForTest(err error) error {
+	return serviceResponded(err)
+}
@@ -0,0 +1,211 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	geoip "github.com/ProtocolONE/geoip-service/pkg"
+	"github.com/ProtocolONE/geoip-service/pkg/proto"
+	"github.com/labstack/echo/v4"
+	"github.com/micro/go-micro/client"
+	microErrors "github.com/micro/go-micro/errors"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"github.com/paysuper/paysuper-proto/go/reporterpb"
+	"github.com/paysuper/paysuper-proto/go/taxpb"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	healthStatusOk       = "ok"
+	healthStatusFail     = "fail"
+	healthStatusDraining = "draining"
+
+	healthCheckTimeoutDefault = 2 * time.Second
+
+	// casbinpb client created with empty name uses the proto package name as service name
+	casbinServiceName = "casbinpb"
+	// go-micro services respond to the debug handler, the response itself is not used
+	microHealthEndpoint = "Debug.Health"
+	// id of the errors raised by go-micro client before the service was reached
+	microClientErrorId = "go.micro.client"
+	// any public address is enough to check geo service responds
+	healthGeoIp = "8.8.8.8"
+)
+
+var (
+	errorHealthNotConfigured = errors.New("not configured")
+)
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// HealthDependency
+type HealthDependency struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthResponse
+type HealthResponse struct {
+	Status       string                       `json:"status"`
+	Dependencies map[string]*HealthDependency `json:"dependencies,omitempty"`
+}
+
+// LivenessHandler responds while the process is able to serve requests
+func (d *Dispatcher) LivenessHandler(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, &HealthResponse{Status: healthStatusOk})
+}
+
+// Drain fails the readiness probe, it's called before the server shutdown
+func (d *Dispatcher) Drain() {
+	atomic.StoreInt32(&d.draining, 1)
+}
+
+// Wait stops the background tasks and waits for them, it's called after the server shutdown
+func (d *Dispatcher) Wait(ctx context.Context) error {
+	return d.appSet.BackgroundTasks.Shutdown(ctx)
+}
+
+// ReadinessHandler checks the dependencies concurrently, responds 503 if any of them failed
+// or the server is going to shut down
+func (d *Dispatcher) ReadinessHandler(ctx echo.Context) error {
+	if atomic.LoadInt32(&d.draining) == 1 {
+		return ctx.JSON(http.StatusServiceUnavailable, &HealthResponse{Status: healthStatusDraining})
+	}
+
+	timeout := d.globalCfg.HealthCheckTimeout
+
+	if timeout <= 0 {
+		timeout = healthCheckTimeoutDefault
+	}
+
+	checks := d.healthChecks()
+	rsp := &HealthResponse{Status: healthStatusOk, Dependencies: make(map[string]*HealthDependency, len(checks))}
+
+	var (
+		wg sync.WaitGroup
+		mx sync.Mutex
+	)
+
+	for _, hc := range checks {
+		wg.Add(1)
+
+		go func(hc healthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx.Request().Context(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := hc.check(checkCtx)
+			dep := &HealthDependency{Status: healthStatusOk, Latency: time.Since(start).String()}
+
+			if err != nil {
+				dep.Status = healthStatusFail
+				dep.Error = err.Error()
+			}
+
+			mx.Lock()
+			rsp.Dependencies[hc.name] = dep
+			mx.Unlock()
+		}(hc)
+	}
+
+	wg.Wait()
+
+	for _, dep := range rsp.Dependencies {
+		if dep.Status != healthStatusOk {
+			rsp.Status = healthStatusFail
+			return ctx.JSON(http.StatusServiceUnavailable, rsp)
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, rsp)
+}
+
+func (d *Dispatcher) healthChecks() []healthCheck {
+	services := d.appSet.Services
+	checks := []healthCheck{
+		{billingpb.ServiceName, func(ctx context.Context) error {
+			_, err := services.Billing.GetCountriesList(ctx, &billingpb.EmptyRequest{})
+			return serviceResponded(err)
+		}},
+		{taxpb.ServiceName, func(ctx context.Context) error {
+			_, err := services.Tax.GetRates(ctx, &taxpb.GetRatesRequest{Limit: 1})
+			return serviceResponded(err)
+		}},
+		{geoip.ServiceName, func(ctx context.Context) error {
+			_, err := services.Geo.GetIpData(ctx, &proto.GeoIpDataRequest{IP: healthGeoIp})
+			return serviceResponded(err)
+		}},
+		{reporterpb.ServiceName, d.pingService(reporterpb.ServiceName)},
+		{"s3_agreement", configured(d.globalCfg.AwsBucketAgreement, d.globalCfg.AwsRegionAgreement)},
+		{"s3_reporter", configured(d.globalCfg.AwsBucketReporter, d.globalCfg.AwsRegionReporter)},
+		{"s3_merchant_docs", configured(d.globalCfg.AwsBucketMerchantDocs, d.globalCfg.AwsRegionMerchantDocs)},
+	}
+
+	// the embedded enforcer keeps working with the last loaded policy while the casbin service is down
+	if !d.globalCfg.DisableCasbinPolicy && d.globalCfg.CasbinMode != common.CasbinModeLocal {
+		checks = append(checks, healthCheck{casbinServiceName, d.pingService(casbinServiceName)})
+	}
+
+	if logs := d.globalCfg.LogsSettings; logs != nil {
+		checks = append(checks, healthCheck{"cloudwatch", configured(
+			logs.AwsCloudWatchAccessKeyId,
+			logs.AwsCloudWatchSecretAccessKey,
+			logs.AwsCloudWatchRegion,
+			logs.AwsCloudWatchLogGroupBillingServer,
+			logs.AwsCloudWatchLogGroupManagementApi,
+			logs.AwsCloudWatchLogGroupWebhookNotifier,
+		)})
+	} else {
+		checks = append(checks, healthCheck{"cloudwatch", configured("")})
+	}
+
+	return checks
+}
+
+// pingService is used for the services without cheap read-only method in the typed client
+func (d *Dispatcher) pingService(service string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		d.healthClientOnce.Do(func() {
+			d.healthClient = d.ms.Client("", "")
+		})
+
+		req := d.healthClient.NewRequest(service, microHealthEndpoint, &billingpb.EmptyRequest{})
+		err := d.healthClient.Call(ctx, req, &billingpb.EmptyRequest{}, client.WithRetries(0))
+
+		return serviceResponded(err)
+	}
+}
+
+// serviceResponded treats errors returned by the called service as successful check,
+// only the errors raised before the service was reached (discovery, transport, timeout) are failures
+func serviceResponded(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if microErr := microErrors.Parse(err.Error()); microErr.Id != "" && microErr.Id != microClientErrorId {
+		return nil
+	}
+
+	return err
+}
+
+func configured(values ...string) func(ctx context.Context) error {
+	return func(_ context.Context) error {
+		for _, v := range values {
+			if v == "" {
+				return errorHealthNotConfigured
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,231 @@
+package dispatcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	geoip "github.com/ProtocolONE/geoip-service/pkg"
+	"github.com/ProtocolONE/geoip-service/pkg/proto"
+	"github.com/micro/go-micro/client"
+	microMock "github.com/micro/go-micro/client/mock"
+	microErrors "github.com/micro/go-micro/errors"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	billMock "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
+	"github.com/paysuper/paysuper-proto/go/reporterpb"
+	"github.com/paysuper/paysuper-proto/go/taxpb"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	healthEndpoint = "Debug.Health"
+)
+
+// healthTaxService responds to the readiness check only
+type healthTaxService struct {
+	taxpb.TaxService
+	err error
+}
+
+func (s *healthTaxService) GetRates(context.Context, *taxpb.GetRatesRequest, ...client.CallOption) (*taxpb.GetRatesResponse, error) {
+	return &taxpb.GetRatesResponse{}, s.err
+}
+
+// healthGeoService responds to the readiness check only
+type healthGeoService struct {
+	proto.GeoIpService
+	err error
+}
+
+func (s *healthGeoService) GetIpData(context.Context, *proto.GeoIpDataRequest, ...client.CallOption) (*proto.GeoIpDataResponse, error) {
+	return &proto.GeoIpDataResponse{}, s.err
+}
+
+type HealthTestSuite struct {
+	suite.Suite
+	dispatcher *dispatcher.Dispatcher
+	billing    *billMock.BillingService
+	tax        *healthTaxService
+	geo        *healthGeoService
+}
+
+func Test_Health(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
+
+func (suite *HealthTestSuite) SetupTest() {
+	suite.dispatcher = newTestDispatcher()
+	suite.billing = &billMock.BillingService{}
+	suite.tax = &healthTaxService{}
+	suite.geo = &healthGeoService{}
+
+	services := &suite.dispatcher.AppSetForTest().Services
+	services.Billing = suite.billing
+	services.Tax = suite.tax
+	services.Geo = suite.geo
+
+	cfg := suite.dispatcher.GlobalConfigForTest()
+	cfg.AwsBucketMerchantDocs = "merchant_docs"
+	cfg.LogsSettings = &common.LogsSettings{
+		AwsCloudWatchAccessKeyId:             "key_id",
+		AwsCloudWatchSecretAccessKey:         "secret",
+		AwsCloudWatchRegion:                  "eu-west-1",
+		AwsCloudWatchLogGroupBillingServer:   "billing",
+		AwsCloudWatchLogGroupManagementApi:   "management",
+		AwsCloudWatchLogGroupWebhookNotifier: "notifier",
+	}
+
+	suite.setReporter(microMock.MockResponse{Endpoint: healthEndpoint, Response: billingpb.EmptyRequest{}})
+}
+
+func (suite *HealthTestSuite) TearDownTest() {}
+
+func (suite *HealthTestSuite) setBilling(err error) {
+	suite.billing.On("GetCountriesList", mock2.Anything, mock2.Anything).Return(nil, err)
+}
+
+func (suite *HealthTestSuite) setReporter(response microMock.MockResponse) {
+	suite.dispatcher.SetHealthClientForTest(microMock.NewClient(
+		microMock.Response(reporterpb.ServiceName, []microMock.MockResponse{response}),
+	))
+}
+
+func (suite *HealthTestSuite) readiness() (int, *dispatcher.HealthResponse) {
+	ctx, rec := newTestContext(httptest.NewRequest(http.MethodGet, common.ReadinessPath, nil))
+	assert.NoError(suite.T(), suite.dispatcher.ReadinessHandler(ctx))
+
+	rsp := &dispatcher.HealthResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), rsp))
+
+	return rec.Code, rsp
+}
+
+func (suite *HealthTestSuite) TestReadiness_Ok() {
+	suite.setBilling(nil)
+
+	code, rsp := suite.readiness()
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), "ok", rsp.Status)
+
+	for _, name := range []string{
+		billingpb.ServiceName, taxpb.ServiceName, geoip.ServiceName, reporterpb.ServiceName,
+		"s3_agreement", "s3_reporter", "s3_merchant_docs", "cloudwatch",
+	} {
+		if assert.Contains(suite.T(), rsp.Dependencies, name) {
+			assert.Equal(suite.T(), "ok", rsp.Dependencies[name].Status, name)
+			assert.Empty(suite.T(), rsp.Dependencies[name].Error, name)
+		}
+	}
+}
+
+func (suite *HealthTestSuite) TestReadiness_ServiceError_Healthy() {
+	suite.setBilling(microErrors.InternalServerError(billingpb.ServiceName, "database is locked"))
+	suite.tax.err = microErrors.BadRequest(taxpb.ServiceName, "limit is required")
+	suite.setReporter(microMock.MockResponse{
+		Endpoint: healthEndpoint,
+		Error:    microErrors.NotFound(reporterpb.ServiceName, "unknown endpoint"),
+	})
+
+	code, rsp := suite.readiness()
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), "ok", rsp.Status)
+	assert.Equal(suite.T(), "ok", rsp.Dependencies[billingpb.ServiceName].Status)
+	assert.Equal(suite.T(), "ok", rsp.Dependencies[taxpb.ServiceName].Status)
+	assert.Equal(suite.T(), "ok", rsp.Dependencies[reporterpb.ServiceName].Status)
+}
+
+func (suite *HealthTestSuite) TestReadiness_ServiceUnreachable_Error() {
+	suite.setBilling(nil)
+	suite.tax.err = microErrors.InternalServerError("go.micro.client", "service %s: not found", taxpb.ServiceName)
+	suite.geo.err = errors.New("connection refused")
+	suite.setReporter(microMock.MockResponse{
+		Endpoint: healthEndpoint,
+		Error:    microErrors.Timeout("go.micro.client", "request timeout"),
+	})
+
+	code, rsp := suite.readiness()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, code)
+	assert.Equal(suite.T(), "fail", rsp.Status)
+	assert.Equal(suite.T(), "ok", rsp.Dependencies[billingpb.ServiceName].Status)
+
+	for _, name := range []string{taxpb.ServiceName, geoip.ServiceName, reporterpb.ServiceName} {
+		assert.Equal(suite.T(), "fail", rsp.Dependencies[name].Status, name)
+		assert.NotEmpty(suite.T(), rsp.Dependencies[name].Error, name)
+	}
+
+	assert.Contains(suite.T(), rsp.Dependencies[taxpb.ServiceName].Error, "not found")
+}
+
+func (suite *HealthTestSuite) TestReadiness_NotConfigured_Error() {
+	suite.setBilling(nil)
+
+	cfg := suite.dispatcher.GlobalConfigForTest()
+	cfg.AwsBucketMerchantDocs = ""
+	cfg.LogsSettings.AwsCloudWatchLogGroupBillingServer = ""
+
+	code, rsp := suite.readiness()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, code)
+	assert.Equal(suite.T(), "fail", rsp.Status)
+	assert.Equal(suite.T(), "ok", rsp.Dependencies["s3_agreement"].Status)
+	assert.Equal(suite.T(), "fail", rsp.Dependencies["s3_merchant_docs"].Status)
+	assert.Equal(suite.T(), "not configured", rsp.Dependencies["s3_merchant_docs"].Error)
+	assert.Equal(suite.T(), "fail", rsp.Dependencies["cloudwatch"].Status)
+	assert.Equal(suite.T(), "not configured", rsp.Dependencies["cloudwatch"].Error)
+}
+
+func (suite *HealthTestSuite) TestReadiness_CloudWatchMissing_Error() {
+	suite.setBilling(nil)
+	suite.dispatcher.GlobalConfigForTest().LogsSettings = nil
+
+	code, rsp := suite.readiness()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, code)
+	assert.Equal(suite.T(), "fail", rsp.Dependencies["cloudwatch"].Status)
+	assert.Equal(suite.T(), "not configured", rsp.Dependencies["cloudwatch"].Error)
+}
+
+func (suite *HealthTestSuite) TestReadiness_Draining() {
+	suite.dispatcher.Drain()
+
+	code, rsp := suite.readiness()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, code)
+	assert.Equal(suite.T(), "draining", rsp.Status)
+	assert.Empty(suite.T(), rsp.Dependencies)
+	suite.billing.AssertNotCalled(suite.T(), "GetCountriesList", mock2.Anything, mock2.Anything)
+
+	ctx, rec := newTestContext(httptest.NewRequest(http.MethodGet, common.LivenessPath, nil))
+	assert.NoError(suite.T(), suite.dispatcher.LivenessHandler(ctx))
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+}
+
+func TestServiceResponded(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		healthy bool
+	}{
+		{name: "no error", err: nil, healthy: true},
+		{name: "service error", err: microErrors.InternalServerError(billingpb.ServiceName, "internal"), healthy: true},
+		{name: "service not found error", err: microErrors.NotFound(reporterpb.ServiceName, "order not found"), healthy: true},
+		{name: "service not discovered", err: microErrors.InternalServerError("go.micro.client", "service billingpb: not found"), healthy: false},
+		{name: "client timeout", err: microErrors.Timeout("go.micro.client", "request timeout"), healthy: false},
+		{name: "transport error", err: errors.New("connection refused"), healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dispatcher.ServiceRespondedForTest(tt.err)
+
+			if tt.healthy {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
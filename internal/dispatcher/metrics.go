@@ -0,0 +1,75 @@
+package dispatcher
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of the processed HTTP requests.",
+		},
+		[]string{"group", "method", "route", "status"},
+	)
+	httpRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of the processed HTTP requests.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"group", "method", "route", "status"},
+	)
+	authCacheRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_cache_requests_total",
+			Help: "Number of the auth cache lookups by result, hit or miss.",
+		},
+		[]string{"cache", "result"},
+	)
+	casbinShadowDisagreementsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "casbin_shadow_disagreements_total",
+			Help: "Number of the requests with different decisions of the local and remote casbin enforcers.",
+		},
+	)
+)
+
+// MetricsMiddleware
+func (d *Dispatcher) MetricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		start := time.Now()
+		err := next(ctx)
+
+		route := ctx.Path()
+
+		if route == "" {
+			route = "unknown"
+		}
+
+		labels := []string{common.RouteGroupName(route), ctx.Request().Method, route, strconv.Itoa(responseStatus(ctx, err))}
+		httpRequestsTotal.WithLabelValues(labels...).Inc()
+		httpRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// responseStatus returns the status which will be sent by the error handler if the handler failed
+func responseStatus(ctx echo.Context, err error) int {
+	if err == nil {
+		return ctx.Response().Status
+	}
+
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
@@ -2,18 +2,25 @@ package dispatcher
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"fmt"
 	jwtverifier "github.com/ProtocolONE/authone-jwt-verifier-golang"
 	jwtMiddleware "github.com/ProtocolONE/authone-jwt-verifier-golang/middleware/echo"
 	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	casbinMiddleware "github.com/paysuper/echo-casbin-middleware"
+	"github.com/micro/go-micro/metadata"
 	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
 	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"io"
 	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // RecoverMiddleware
@@ -26,7 +33,7 @@ func (d *Dispatcher) RecoverMiddleware() echo.MiddlewareFunc {
 					if !ok {
 						err = fmt.Errorf("%v", r)
 					}
-					d.L().Critical("[PANIC RECOVER] %s", logger.Args(err.Error()), logger.Stack("stacktrace"))
+					common.RequestLogger(c, d.L()).Critical("[PANIC RECOVER] %s", logger.Args(err.Error()), logger.Stack("stacktrace"))
 					c.Error(err)
 				}
 			}()
@@ -35,47 +42,106 @@ func (d *Dispatcher) RecoverMiddleware() echo.MiddlewareFunc {
 	}
 }
 
-// GetUserDetailsMiddleware
+// GetUserDetailsMiddleware authenticates the user by the access token and rejects the revoked sessions
 func (d *Dispatcher) GetUserDetailsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		auth := ctx.Request().Header.Get(echo.HeaderAuthorization)
 
 		if auth == "" {
-			return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizationHeaderNotFound.Message)
+			return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizationHeaderNotFound)
 		}
 
 		match := common.TokenRegex.FindStringSubmatch(auth)
 
 		if len(match) < 1 {
-			return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizationTokenNotFound.Message)
+			return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizationTokenNotFound)
 		}
 
-		reqCtx := ctx.Request().Context()
-		u, err := d.appSet.JwtVerifier.GetUserInfo(reqCtx, match[1])
+		u, err := d.getUserInfo(ctx, match[1])
 
 		if err != nil {
-			return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizedUserNotFound.Message)
+			return err
+		}
+
+		session := common.NewSession(match[1], u.UserID, ctx.Request().UserAgent(), ctx.RealIP())
+		revoked, err := d.appSet.SessionStore.Touch(session)
+
+		// the revoked sessions must not pass while the store is unavailable
+		if err != nil {
+			common.RequestLogger(ctx, d.L()).Error("session touch failed", logger.PairArgs("err", err.Error()))
+			return echo.NewHTTPError(http.StatusServiceUnavailable, common.ErrorMessageSessionUnknown)
+		}
+
+		if revoked {
+			return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageSessionRevoked)
 		}
 
 		user := common.ExtractUserContext(ctx)
 		user.Id = u.UserID
 		user.Email = u.Email
 
-		req := &billingpb.GetUserProfileRequest{
-			UserId: u.UserID,
+		profile := d.getUserProfile(ctx, u.UserID)
+		user.ProfileId = profile.profileId
+		user.Locale = profile.locale
+
+		common.SetUserContext(ctx, user)
+
+		return next(ctx)
+	}
+}
+
+// RequestIdMiddleware accepts X-Request-ID header of the request or generates the new one. The id is sent
+// in the response, attached to the request logs and passed to the go-micro calls in the metadata.
+func (d *Dispatcher) RequestIdMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		id := req.Header.Get(echo.HeaderXRequestID)
+
+		if !common.RequestIdMatch.MatchString(id) {
+			id = uuid.New().String()
+			req.Header.Set(echo.HeaderXRequestID, id)
 		}
-		rsp, err := d.appSet.Services.Billing.GetUserProfile(reqCtx, req)
 
-		if err == nil && (rsp != nil && rsp.Status == http.StatusOK) {
-			user.ProfileId = rsp.Item.Id
+		ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+		common.AddLogFieldsContext(ctx, logger.Fields{common.LogFieldRequestId: id})
+
+		md := metadata.Metadata{}
+
+		if current, ok := metadata.FromContext(req.Context()); ok {
+			for k, v := range current {
+				md[k] = v
+			}
 		}
 
-		common.SetUserContext(ctx, user)
+		md[echo.HeaderXRequestID] = id
+		ctx.SetRequest(req.WithContext(metadata.NewContext(req.Context(), md)))
 
 		return next(ctx)
 	}
 }
 
+// PageCursorPreMiddleware replaces the query of the listing request with the query kept in the page cursor,
+// the cursor is valid only for the path it was issued for
+func (d *Dispatcher) PageCursorPreMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		value := c.QueryParam(common.QueryParameterNameCursor)
+
+		if c.Request().Method != http.MethodGet || value == "" {
+			return next(c)
+		}
+
+		cursor, err := common.DecodePageCursor(d.globalCfg, value)
+
+		if err != nil || cursor.Path != c.Request().URL.Path {
+			return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageCursorInvalid)
+		}
+
+		common.SetQueryParams(c, cursor.Query)
+		common.SetPageCursorContext(c, cursor)
+		return next(c)
+	}
+}
+
 // LimitOffsetSortPreMiddleware
 func (d *Dispatcher) LimitOffsetSortPreMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -119,17 +185,6 @@ func (d *Dispatcher) RawBodyPreMiddleware(next echo.HandlerFunc) echo.HandlerFun
 	}
 }
 
-// RawBodyPreMiddleware
-func (d *Dispatcher) CasbinMiddleware(fn func(c echo.Context) string) echo.MiddlewareFunc {
-	cfg := casbinMiddleware.Config{
-		Skipper:          middleware.DefaultSkipper,
-		Mode:             casbinMiddleware.EnforceModeEnforcing,
-		Logger:           d.L(),
-		CtxUserExtractor: fn,
-	}
-	return casbinMiddleware.MiddlewareWithConfig(d.ms.Client("", ""), cfg)
-}
-
 // BodyDumpMiddleware
 func (d *Dispatcher) BodyDumpMiddleware() echo.MiddlewareFunc {
 	return middleware.BodyDump(func(ctx echo.Context, reqBody, resBody []byte) {
@@ -139,7 +194,7 @@ func (d *Dispatcher) BodyDumpMiddleware() echo.MiddlewareFunc {
 			"response_headers": common.RequestResponseHeadersToString(ctx.Response().Header()),
 			"response_body":    string(resBody),
 		}
-		d.L().Info(ctx.Path(), logger.WithFields(data))
+		common.RequestLogger(ctx, d.L()).Info(ctx.Path(), logger.WithFields(data))
 	})
 }
 
@@ -173,24 +228,6 @@ func (d *Dispatcher) AuthOneMerchantPreMiddleware() echo.MiddlewareFunc {
 			func(ui *jwtverifier.UserInfo) {
 				user := common.ExtractUserContext(c)
 				user.Name = "Merchant User"
-
-				res, err := d.appSet.Services.Billing.GetMerchantsForUser(
-					c.Request().Context(),
-					&billingpb.GetMerchantsForUserRequest{UserId: user.Id},
-				)
-
-				if err != nil {
-					d.L().Error(c.Path(), logger.Args(err.Error()), logger.Stack("stacktrace"))
-					return
-				}
-
-				if len(res.Merchants) < 1 {
-					d.L().Error(c.Path(), logger.Args("user_id", user.Id))
-					return
-				}
-
-				user.Role = res.Merchants[0].Role
-				user.MerchantId = res.Merchants[0].Id
 				common.SetUserContext(c, user)
 			},
 		)(next)
@@ -198,37 +235,449 @@ func (d *Dispatcher) AuthOneMerchantPreMiddleware() echo.MiddlewareFunc {
 	})
 }
 
-// Middleware to check access for S2S requests from merchant's server
-func (d *Dispatcher) S2SAuthPreMiddleware() echo.MiddlewareFunc {
-	return middleware.BasicAuth(func(projectId, projectSecret string, ctx echo.Context) (bool, error) {
-		req := &billingpb.GetProjectRequest{
-			ProjectId: projectId,
+// MerchantSelectorMiddleware sets the active merchant and the user role in it. The merchant is chosen
+// by the merchant_id path parameter or X-Merchant-Id header, the first merchant of the user is used by default.
+func (d *Dispatcher) MerchantSelectorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := common.ExtractUserContext(c)
+		merchantId := c.Param(common.RequestParameterMerchantId)
+
+		if merchantId == "" {
+			merchantId = c.Request().Header.Get(common.HeaderXMerchantId)
+		}
+
+		res, err := d.getMerchantsForUser(c, user.Id)
+
+		if err != nil {
+			common.RequestLogger(c, d.L()).Error(c.Path(), logger.Args(err.Error()), logger.Stack("stacktrace"))
+
+			if merchantId != "" {
+				return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+			}
+
+			return next(c)
 		}
-		rsp, err := d.appSet.Services.Billing.GetProject(ctx.Request().Context(), req)
 
-		if err != nil || (rsp != nil && rsp.Status != billingpb.ResponseStatusOk) {
-			if err == nil {
-				err = rsp.Message
+		if merchantId == "" {
+			if len(res.Merchants) < 1 {
+				common.RequestLogger(c, d.L()).Error(c.Path(), logger.Args("user_id", user.Id))
+				return next(c)
 			}
 
-			d.L().Error(
-				ctx.Path(),
-				logger.Args(err.Error()),
-				logger.Args("request", common.ExtractRawBodyContext(ctx)),
-				logger.Stack("stacktrace"),
-			)
-			return false, err
+			merchantId = res.Merchants[0].Id
 		}
 
-		if rsp.Item.SecretKey != projectSecret {
-			return false, nil
+		for _, merchant := range res.Merchants {
+			if merchant.Id == merchantId {
+				user.Role = merchant.Role
+				user.MerchantId = merchant.Id
+				common.SetUserContext(c, user)
+
+				return next(c)
+			}
 		}
 
-		user := common.ExtractUserContext(ctx)
-		user.Name = "Merchant User"
-		user.MerchantId = rsp.Item.MerchantId
-		common.SetUserContext(ctx, user)
+		return echo.NewHTTPError(http.StatusForbidden, common.ErrorMessageMerchantNotMember)
+	}
+}
+
+// S2SAuthPreMiddleware checks access for S2S requests from merchant's server.
+// Requests are signed with the project secret, Basic auth stays available unless it's disabled for the project in config
+func (d *Dispatcher) S2SAuthPreMiddleware() echo.MiddlewareFunc {
+	basicAuth := middleware.BasicAuth(d.s2sBasicAuthValidator)
 
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		basicAuthNext := basicAuth(next)
+		signatureNext := d.S2SSignatureMiddleware(next)
+
+		return func(ctx echo.Context) error {
+			if ctx.Request().Header.Get(common.HeaderXS2SSignature) == "" {
+				return basicAuthNext(ctx)
+			}
+
+			return signatureNext(ctx)
+		}
+	}
+}
+
+// S2SSignatureMiddleware checks the S2S signature of the request, Basic auth isn't accepted
+func (d *Dispatcher) S2SSignatureMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		project, apiKey, err := d.s2sVerifySignature(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		d.s2sSetUser(ctx, project, apiKey)
+		return next(ctx)
+	}
+}
+
+func (d *Dispatcher) s2sBasicAuthValidator(projectId, projectSecret string, ctx echo.Context) (bool, error) {
+	if d.s2sBasicAuthDisabled(projectId) {
+		return false, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2SBasicAuthNotAllowed)
+	}
+
+	project, err := d.s2sProject(ctx, projectId)
+
+	if err != nil {
+		return false, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(project.SecretKey), []byte(projectSecret)) == 1 {
+		d.s2sSetUser(ctx, project, nil)
 		return true, nil
-	})
+	}
+
+	keys, err := d.appSet.ApiKeyStore.List(project.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, apiKey := range keys {
+		if subtle.ConstantTimeCompare([]byte(apiKey.Secret), []byte(projectSecret)) != 1 {
+			continue
+		}
+
+		if apiKey.IsExpired(time.Now()) {
+			return false, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageApiKeyExpired)
+		}
+
+		d.s2sSetUser(ctx, project, apiKey)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (d *Dispatcher) s2sBasicAuthDisabled(projectId string) bool {
+	for _, disabled := range strings.Split(d.globalCfg.S2SBasicAuthDisabledProjects, ",") {
+		disabled = strings.TrimSpace(disabled)
+
+		if disabled == common.S2SBasicAuthAllProjects || (disabled != "" && disabled == projectId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Dispatcher) s2sVerifySignature(ctx echo.Context) (*billingpb.Project, *common.ApiKey, error) {
+	req := ctx.Request()
+	projectId := req.Header.Get(common.HeaderXS2SProjectId)
+	timestamp := req.Header.Get(common.HeaderXS2STimestamp)
+	nonce := req.Header.Get(common.HeaderXS2SNonce)
+	signature := req.Header.Get(common.HeaderXS2SSignature)
+	apiKeyId := req.Header.Get(common.HeaderXS2SApiKeyId)
+
+	if projectId == "" || timestamp == "" || nonce == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2SSignatureHeadersNotFound)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2STimestampInvalid)
+	}
+
+	skew := time.Since(time.Unix(unix, 0))
+
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > d.globalCfg.S2SSignatureMaxSkew {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2STimestampInvalid)
+	}
+
+	if len(nonce) > common.S2SNonceMaxLength {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2SNonceInvalid)
+	}
+
+	project, err := d.s2sProject(ctx, projectId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret := project.SecretKey
+	var apiKey *common.ApiKey
+
+	if apiKeyId != "" {
+		apiKey, err = d.appSet.ApiKeyStore.Get(project.Id, apiKeyId)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if apiKey == nil {
+			return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageApiKeyNotFound)
+		}
+
+		if apiKey.IsExpired(time.Now()) {
+			return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageApiKeyExpired)
+		}
+
+		secret = apiKey.Secret
+	}
+
+	canonical := common.S2SCanonicalRequest(req.Method, req.URL.Path, common.ExtractRawQueryContext(ctx), timestamp, nonce, common.ExtractRawBodyContext(ctx))
+
+	if !common.S2SSignatureEqual(common.S2SSignature(secret, canonical), signature) {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2SSignatureInvalid)
+	}
+
+	// the nonce is remembered only for the valid signatures, so it can't be burned by a third party
+	added, err := d.appSet.NonceStore.Add(project.Id+":"+nonce, 2*d.globalCfg.S2SSignatureMaxSkew)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !added {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageS2SNonceReused)
+	}
+
+	return project, apiKey, nil
+}
+
+func (d *Dispatcher) s2sProject(ctx echo.Context, projectId string) (*billingpb.Project, error) {
+	req := &billingpb.GetProjectRequest{
+		ProjectId: projectId,
+	}
+	rsp, err := d.appSet.Services.Billing.GetProject(ctx.Request().Context(), req)
+
+	if err != nil || (rsp != nil && rsp.Status != billingpb.ResponseStatusOk) {
+		if err == nil {
+			err = rsp.Message
+		}
+
+		common.RequestLogger(ctx, d.L()).Error(
+			ctx.Path(),
+			logger.Args(err.Error()),
+			logger.Args("request", common.ExtractRawBodyContext(ctx)),
+			logger.Stack("stacktrace"),
+		)
+		return nil, err
+	}
+
+	return rsp.Item, nil
+}
+
+func (d *Dispatcher) s2sSetUser(ctx echo.Context, project *billingpb.Project, apiKey *common.ApiKey) {
+	user := common.ExtractUserContext(ctx)
+	user.Name = "Merchant User"
+	user.MerchantId = project.MerchantId
+	user.ProjectId = project.Id
+
+	if apiKey != nil {
+		user.ApiKeyId = apiKey.Id
+		user.Scopes = apiKey.Scopes
+
+		if err := d.appSet.ApiKeyStore.Touch(project.Id, apiKey.Id, time.Now().UTC()); err != nil {
+			common.RequestLogger(ctx, d.L()).Error("api key last used time update failed", logger.PairArgs("err", err.Error()))
+		}
+	}
+
+	common.SetUserContext(ctx, user)
+}
+
+// IdempotencyMiddleware replays the stored response for repeated mutating requests with the same Idempotency-Key
+func (d *Dispatcher) IdempotencyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		key := req.Header.Get(common.HeaderIdempotencyKey)
+
+		if key == "" || !common.IsMutatingMethod(req.Method) {
+			return next(ctx)
+		}
+
+		if len(key) > common.IdempotencyKeyMaxLength {
+			return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageIdempotencyKeyInvalid)
+		}
+
+		scope := idempotencyScope(common.ExtractUserContext(ctx))
+
+		if scope == "" {
+			return next(ctx)
+		}
+
+		storeKey := common.IdempotencyKey(scope, key)
+		hash := common.IdempotencyRequestHash(req.Method, req.URL.Path, req.URL.Query(), common.ExtractRawBodyContext(ctx))
+
+		record, reserved, err := d.appSet.IdempotencyStore.Reserve(storeKey, hash)
+
+		if err != nil {
+			common.RequestLogger(ctx, d.L()).Error("idempotency key reserve failed", logger.PairArgs("key", storeKey), logger.WithPrettyFields(logger.Fields{"err": err}))
+			return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
+		}
+
+		if !reserved {
+			if record.RequestHash != hash {
+				return echo.NewHTTPError(http.StatusConflict, common.ErrorMessageIdempotencyKeyReused)
+			}
+
+			if !record.Completed {
+				return echo.NewHTTPError(http.StatusConflict, common.ErrorMessageIdempotencyKeyInProgress)
+			}
+
+			for name, values := range record.Header {
+				for _, value := range values {
+					ctx.Response().Header().Add(name, value)
+				}
+			}
+
+			ctx.Response().Header().Set(common.HeaderIdempotentReplayed, "true")
+			return ctx.Blob(record.Status, record.Header.Get(echo.HeaderContentType), record.Body)
+		}
+
+		resBody := new(bytes.Buffer)
+		writer := &idempotencyResponseWriter{
+			Writer:         io.MultiWriter(ctx.Response().Writer, resBody),
+			ResponseWriter: ctx.Response().Writer,
+		}
+		ctx.Response().Writer = writer
+
+		err = next(ctx)
+		status := ctx.Response().Status
+
+		// Failed requests are not stored, so the client is able to retry them
+		if err != nil || !ctx.Response().Committed || status >= http.StatusInternalServerError {
+			if e := d.appSet.IdempotencyStore.Release(storeKey); e != nil {
+				common.RequestLogger(ctx, d.L()).Error("idempotency key release failed", logger.PairArgs("key", storeKey), logger.WithPrettyFields(logger.Fields{"err": e}))
+			}
+			return err
+		}
+
+		header := make(http.Header)
+
+		if contentType := ctx.Response().Header().Get(echo.HeaderContentType); contentType != "" {
+			header.Set(echo.HeaderContentType, contentType)
+		}
+
+		if location := ctx.Response().Header().Get(echo.HeaderLocation); location != "" {
+			header.Set(echo.HeaderLocation, location)
+		}
+
+		record = &common.IdempotencyRecord{
+			RequestHash: hash,
+			Status:      status,
+			Header:      header,
+			Body:        resBody.Bytes(),
+			CreatedAt:   time.Now(),
+		}
+
+		if e := d.appSet.IdempotencyStore.Complete(storeKey, record); e != nil {
+			common.RequestLogger(ctx, d.L()).Error("idempotency key complete failed", logger.PairArgs("key", storeKey), logger.WithPrettyFields(logger.Fields{"err": e}))
+		}
+
+		return nil
+	}
+}
+
+// idempotencyScope returns the owner of the idempotency keys: the project of S2S request or the merchant of the user,
+// the keys of the anonymous requests aren't stored
+func idempotencyScope(user *common.AuthUser) string {
+	if user.ProjectId != "" {
+		return "project:" + user.ProjectId
+	}
+
+	if user.MerchantId != "" {
+		return "merchant:" + user.MerchantId
+	}
+
+	return ""
+}
+
+type idempotencyResponseWriter struct {
+	io.Writer
+	http.ResponseWriter
+}
+
+func (w *idempotencyResponseWriter) WriteHeader(code int) {
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	return w.Writer.Write(b)
+}
+
+// RateLimitMiddleware throttles requests using separate token bucket for every key returned by keyFn
+func (d *Dispatcher) RateLimitMiddleware(rate float64, burst int, keyFn func(c echo.Context) string) echo.MiddlewareFunc {
+	if rate <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	limiter := newRateLimiter(rate, burst)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			res := limiter.take(keyFn(ctx), time.Now())
+			header := ctx.Response().Header()
+
+			header.Set(common.HeaderXRateLimitLimit, strconv.Itoa(res.limit))
+			header.Set(common.HeaderXRateLimitRemaining, strconv.Itoa(res.remaining))
+			header.Set(common.HeaderXRateLimitReset, strconv.Itoa(int(math.Ceil(res.reset.Seconds()))))
+
+			if !res.allowed {
+				header.Set(common.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(res.retryAfter.Seconds()))))
+				return echo.NewHTTPError(http.StatusTooManyRequests, common.ErrorMessageTooManyRequests)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// RateLimitKeyMerchant
+func RateLimitKeyMerchant(ctx echo.Context) string {
+	user := common.ExtractUserContext(ctx)
+
+	if user.MerchantId != "" {
+		return "merchant:" + user.MerchantId
+	}
+
+	if user.Id != "" {
+		return "user:" + user.Id
+	}
+
+	return RateLimitKeyRemoteIp(ctx)
+}
+
+// RateLimitKeyUser
+func RateLimitKeyUser(ctx echo.Context) string {
+	user := common.ExtractUserContext(ctx)
+
+	if user.Id != "" {
+		return "user:" + user.Id
+	}
+
+	return RateLimitKeyRemoteIp(ctx)
+}
+
+// RateLimitKeyProject
+func RateLimitKeyProject(ctx echo.Context) string {
+	user := common.ExtractUserContext(ctx)
+
+	if user.ProjectId != "" {
+		return "project:" + user.ProjectId
+	}
+
+	return RateLimitKeyRemoteIp(ctx)
+}
+
+// RateLimitKeyRemoteIp keys on the peer address, the forwarded headers are set by the client and aren't trusted
+func RateLimitKeyRemoteIp(ctx echo.Context) string {
+	return "ip:" + clientIp(ctx.Request(), nil)
+}
+
+// RateLimitKeyClientIp keys on the client address forwarded by the trusted proxies
+func RateLimitKeyClientIp(trusted []*net.IPNet) func(ctx echo.Context) string {
+	return func(ctx echo.Context) string {
+		return "ip:" + clientIp(ctx.Request(), trusted)
+	}
 }
@@ -0,0 +1,487 @@
+package dispatcher_test
+
+import (
+	"context"
+	"errors"
+	jwtverifier "github.com/ProtocolONE/authone-jwt-verifier-golang"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/mock"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	billMock "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	middlewaresUserId        = "ffffffffffffffffffffffff"
+	middlewaresProjectId     = "eeeeeeeeeeeeeeeeeeeeeeee"
+	middlewaresProjectSecret = "project_secret"
+	middlewaresS2SPath       = common.MerchantS2SGroupPath + "/order/refunds?a=1"
+	middlewaresS2SBody       = `{"amount":10}`
+)
+
+type MiddlewaresTestSuite struct {
+	suite.Suite
+	dispatcher *dispatcher.Dispatcher
+}
+
+func Test_Middlewares(t *testing.T) {
+	suite.Run(t, new(MiddlewaresTestSuite))
+}
+
+func (suite *MiddlewaresTestSuite) SetupTest() {
+	suite.dispatcher = newTestDispatcher()
+}
+
+func (suite *MiddlewaresTestSuite) TearDownTest() {}
+
+// newTestDispatcher builds the dispatcher with the in-memory stores and the billing mock
+func newTestDispatcher() *dispatcher.Dispatcher {
+	srv := common.Services{
+		Billing: mock.NewBillingServerOkMock(),
+	}
+
+	d, _, err := test.BuildDispatcher(
+		context.Background(),
+		test.DefaultSettings(),
+		srv,
+		common.Handlers{},
+		common.NewMemoryApprovalStore(),
+		nil,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// newTestContext
+func newTestContext(req *http.Request) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+// serveMiddleware runs the request through the middleware, the next handler responds 200
+func serveMiddleware(mw echo.MiddlewareFunc, ctx echo.Context) error {
+	return mw(func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})(ctx)
+}
+
+func (suite *MiddlewaresTestSuite) authRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, common.AuthUserGroupPath+"/user/profile", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	return req
+}
+
+func (suite *MiddlewaresTestSuite) TestGetUserDetails_Ok() {
+	token := "active_session_token"
+	suite.dispatcher.CacheUserForTest(token, &jwtverifier.UserInfo{UserID: middlewaresUserId})
+
+	ctx, _ := newTestContext(suite.authRequest(token))
+	err := serveMiddleware(suite.dispatcher.GetUserDetailsMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), middlewaresUserId, common.ExtractUserContext(ctx).Id)
+
+	sessions, err := suite.dispatcher.AppSetForTest().SessionStore.List(middlewaresUserId)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), sessions, 1)
+	assert.Equal(suite.T(), common.SessionId(token), sessions[0].Id)
+}
+
+func (suite *MiddlewaresTestSuite) TestGetUserDetails_ProfileLocale() {
+	bill := &billMock.BillingService{}
+	bill.On("GetUserProfile", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetUserProfileResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.UserProfile{Id: "profile_id", Locale: "ru-RU"},
+		}, nil)
+	suite.dispatcher.AppSetForTest().Services.Billing = bill
+
+	token := "profile_locale_token"
+	suite.dispatcher.CacheUserForTest(token, &jwtverifier.UserInfo{UserID: middlewaresUserId})
+
+	ctx, _ := newTestContext(suite.authRequest(token))
+	err := serveMiddleware(suite.dispatcher.GetUserDetailsMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "profile_id", common.ExtractUserContext(ctx).ProfileId)
+	assert.Equal(suite.T(), "ru-RU", common.ExtractUserContext(ctx).Locale)
+	assert.Equal(suite.T(), common.LocaleRu, common.RequestLocale(ctx))
+}
+
+func (suite *MiddlewaresTestSuite) TestGetUserDetails_RevokedSession() {
+	token := "revoked_session_token"
+	suite.dispatcher.CacheUserForTest(token, &jwtverifier.UserInfo{UserID: middlewaresUserId})
+
+	sessions := suite.dispatcher.AppSetForTest().SessionStore
+	_, err := sessions.Touch(common.NewSession(token, middlewaresUserId, "", ""))
+	assert.NoError(suite.T(), err)
+
+	revoked, err := sessions.Revoke(middlewaresUserId, common.SessionId(token))
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), revoked)
+
+	ctx, _ := newTestContext(suite.authRequest(token))
+	err = serveMiddleware(suite.dispatcher.GetUserDetailsMiddleware, ctx)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusUnauthorized, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageSessionRevoked, httpErr.Message)
+	assert.Equal(suite.T(), "ma000139", common.ErrorMessageSessionRevoked.Code)
+}
+
+// failedSessionStore fails to check the sessions as the unavailable database does
+type failedSessionStore struct {
+	common.SessionStore
+}
+
+func (s *failedSessionStore) Touch(session *common.Session) (bool, error) {
+	return false, errors.New("no reachable servers")
+}
+
+func (suite *MiddlewaresTestSuite) TestGetUserDetails_SessionStoreFailed() {
+	token := "failed_session_token"
+	suite.dispatcher.CacheUserForTest(token, &jwtverifier.UserInfo{UserID: middlewaresUserId})
+	suite.dispatcher.AppSetForTest().SessionStore = &failedSessionStore{}
+
+	ctx, _ := newTestContext(suite.authRequest(token))
+	err := serveMiddleware(suite.dispatcher.GetUserDetailsMiddleware, ctx)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageSessionUnknown, httpErr.Message)
+	assert.Empty(suite.T(), common.ExtractUserContext(ctx).Id)
+}
+
+func (suite *MiddlewaresTestSuite) TestGetUserDetails_UserSessionsRevoked() {
+	token := "revoked_user_token"
+	suite.dispatcher.CacheUserForTest(token, &jwtverifier.UserInfo{UserID: middlewaresUserId})
+
+	ctx, _ := newTestContext(suite.authRequest(token))
+	err := serveMiddleware(suite.dispatcher.GetUserDetailsMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+
+	err = suite.dispatcher.AppSetForTest().SessionStore.RevokeUser(middlewaresUserId, "")
+	assert.NoError(suite.T(), err)
+
+	ctx, _ = newTestContext(suite.authRequest(token))
+	err = serveMiddleware(suite.dispatcher.GetUserDetailsMiddleware, ctx)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusUnauthorized, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageSessionRevoked, httpErr.Message)
+}
+
+// idempotentRequest runs the request with the idempotency key on behalf of the user, calls counts the handler runs
+func (suite *MiddlewaresTestSuite) idempotentRequest(user *common.AuthUser, target, body string, calls *int) (*httptest.ResponseRecorder, error) {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set(common.HeaderIdempotencyKey, "key-1")
+
+	ctx, rec := newTestContext(req)
+	common.SetUserContext(ctx, user)
+	common.SetRawBodyContext(ctx, []byte(body))
+
+	err := suite.dispatcher.IdempotencyMiddleware(func(ctx echo.Context) error {
+		*calls++
+		return ctx.String(http.StatusCreated, strconv.Itoa(*calls))
+	})(ctx)
+
+	return rec, err
+}
+
+func (suite *MiddlewaresTestSuite) TestIdempotency_Replay() {
+	calls := 0
+	user := &common.AuthUser{MerchantId: middlewaresUserId}
+
+	_, err := suite.idempotentRequest(user, "/refunds", `{"amount": 10}`, &calls)
+	assert.NoError(suite.T(), err)
+
+	rec, err := suite.idempotentRequest(user, "/refunds", `{"amount": 10}`, &calls)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, calls)
+	assert.Equal(suite.T(), "1", rec.Body.String())
+	assert.Equal(suite.T(), "true", rec.Header().Get(common.HeaderIdempotentReplayed))
+}
+
+func (suite *MiddlewaresTestSuite) TestIdempotency_ProjectScope() {
+	calls := 0
+
+	_, err := suite.idempotentRequest(&common.AuthUser{MerchantId: middlewaresUserId, ProjectId: "project_1"}, "/refunds", "", &calls)
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.idempotentRequest(&common.AuthUser{MerchantId: middlewaresUserId, ProjectId: "project_2"}, "/refunds", "", &calls)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, calls)
+}
+
+func (suite *MiddlewaresTestSuite) TestIdempotency_AnonymousNotStored() {
+	calls := 0
+
+	for i := 0; i < 2; i++ {
+		rec, err := suite.idempotentRequest(&common.AuthUser{}, "/tokens", "", &calls)
+		assert.NoError(suite.T(), err)
+		assert.Empty(suite.T(), rec.Header().Get(common.HeaderIdempotentReplayed))
+	}
+
+	assert.Equal(suite.T(), 2, calls)
+}
+
+func (suite *MiddlewaresTestSuite) TestIdempotency_AnotherQuery_Error() {
+	calls := 0
+	user := &common.AuthUser{MerchantId: middlewaresUserId}
+
+	_, err := suite.idempotentRequest(user, "/refunds?notify=true", "", &calls)
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.idempotentRequest(user, "/refunds?notify=false", "", &calls)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusConflict, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageIdempotencyKeyReused, httpErr.Message)
+	assert.Equal(suite.T(), 1, calls)
+}
+
+func (suite *MiddlewaresTestSuite) TestIdempotency_ReservationLease() {
+	store := common.NewMemoryIdempotencyStore(10*time.Millisecond, time.Hour)
+
+	_, reserved, err := store.Reserve("project:project_1:key-1", "hash")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), reserved)
+
+	_, reserved, err = store.Reserve("project:project_1:key-1", "hash")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), reserved)
+
+	// the request is lost, so the key is available for the retry after the lease
+	time.Sleep(20 * time.Millisecond)
+
+	_, reserved, err = store.Reserve("project:project_1:key-1", "hash")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), reserved)
+
+	// the completed record outlives the lease
+	assert.NoError(suite.T(), store.Complete("project:project_1:key-1", &common.IdempotencyRecord{
+		RequestHash: "hash",
+		Status:      http.StatusCreated,
+		CreatedAt:   time.Now(),
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	record, reserved, err := store.Reserve("project:project_1:key-1", "hash")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), reserved)
+	assert.True(suite.T(), record.Completed)
+}
+
+// s2sRequest builds the S2S request signed with the secret at the time, the billing mock returns the test project
+func (suite *MiddlewaresTestSuite) s2sRequest(secret string, at time.Time, nonce string) echo.Context {
+	bill := &billMock.BillingService{}
+	bill.On("GetProject", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetProjectResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item: &billingpb.Project{
+				Id:         middlewaresProjectId,
+				MerchantId: middlewaresUserId,
+				SecretKey:  middlewaresProjectSecret,
+			},
+		}, nil)
+	suite.dispatcher.AppSetForTest().Services.Billing = bill
+
+	req := httptest.NewRequest(http.MethodPost, middlewaresS2SPath, strings.NewReader(middlewaresS2SBody))
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	canonical := common.S2SCanonicalRequest(req.Method, req.URL.Path, req.URL.Query(), timestamp, nonce, []byte(middlewaresS2SBody))
+
+	req.Header.Set(common.HeaderXS2SProjectId, middlewaresProjectId)
+	req.Header.Set(common.HeaderXS2STimestamp, timestamp)
+	req.Header.Set(common.HeaderXS2SNonce, nonce)
+	req.Header.Set(common.HeaderXS2SSignature, common.S2SSignature(secret, canonical))
+
+	ctx, _ := newTestContext(req)
+	common.SetRawBodyContext(ctx, []byte(middlewaresS2SBody))
+
+	return ctx
+}
+
+func (suite *MiddlewaresTestSuite) assertS2SError(err error, expected *billingpb.ResponseErrorMessage) {
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusUnauthorized, httpErr.Code)
+	assert.Equal(suite.T(), expected, httpErr.Message)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_Signature_Ok() {
+	ctx := suite.s2sRequest(middlewaresProjectSecret, time.Now(), "nonce-1")
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), ctx)
+	assert.NoError(suite.T(), err)
+
+	user := common.ExtractUserContext(ctx)
+	assert.Equal(suite.T(), middlewaresProjectId, user.ProjectId)
+	assert.Equal(suite.T(), middlewaresUserId, user.MerchantId)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_SignatureInvalid_Error() {
+	ctx := suite.s2sRequest("another_secret", time.Now(), "nonce-1")
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), ctx)
+	suite.assertS2SError(err, common.ErrorMessageS2SSignatureInvalid)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_StaleTimestamp_Error() {
+	at := time.Now().Add(-2 * suite.dispatcher.GlobalConfigForTest().S2SSignatureMaxSkew)
+	ctx := suite.s2sRequest(middlewaresProjectSecret, at, "nonce-1")
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), ctx)
+	suite.assertS2SError(err, common.ErrorMessageS2STimestampInvalid)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_NonceReused_Error() {
+	now := time.Now()
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), suite.s2sRequest(middlewaresProjectSecret, now, "nonce-1"))
+	assert.NoError(suite.T(), err)
+
+	err = serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), suite.s2sRequest(middlewaresProjectSecret, now, "nonce-1"))
+	suite.assertS2SError(err, common.ErrorMessageS2SNonceReused)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_BasicAuthNotAllowed_Error() {
+	suite.dispatcher.GlobalConfigForTest().S2SBasicAuthDisabledProjects = "5dbac6a9120a810001a8fe40, " + middlewaresProjectId
+
+	ctx := suite.s2sRequest(middlewaresProjectSecret, time.Now(), "nonce-1")
+	ctx.Request().Header.Del(common.HeaderXS2SSignature)
+	ctx.Request().SetBasicAuth(middlewaresProjectId, middlewaresProjectSecret)
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), ctx)
+	suite.assertS2SError(err, common.ErrorMessageS2SBasicAuthNotAllowed)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_BasicAuthDisabledForAll_Error() {
+	suite.dispatcher.GlobalConfigForTest().S2SBasicAuthDisabledProjects = common.S2SBasicAuthAllProjects
+
+	ctx := suite.s2sRequest(middlewaresProjectSecret, time.Now(), "nonce-1")
+	ctx.Request().Header.Del(common.HeaderXS2SSignature)
+	ctx.Request().SetBasicAuth(middlewaresProjectId, middlewaresProjectSecret)
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), ctx)
+	suite.assertS2SError(err, common.ErrorMessageS2SBasicAuthNotAllowed)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SAuth_BasicAuthAllowed_Ok() {
+	suite.dispatcher.GlobalConfigForTest().S2SBasicAuthDisabledProjects = "5dbac6a9120a810001a8fe40"
+
+	ctx := suite.s2sRequest(middlewaresProjectSecret, time.Now(), "nonce-1")
+	ctx.Request().Header.Del(common.HeaderXS2SSignature)
+	ctx.Request().SetBasicAuth(middlewaresProjectId, middlewaresProjectSecret)
+
+	err := serveMiddleware(suite.dispatcher.S2SAuthPreMiddleware(), ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), middlewaresProjectId, common.ExtractUserContext(ctx).ProjectId)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SSignature_Ok() {
+	ctx := suite.s2sRequest(middlewaresProjectSecret, time.Now(), "nonce-1")
+
+	err := serveMiddleware(suite.dispatcher.S2SSignatureMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), middlewaresProjectId, common.ExtractUserContext(ctx).ProjectId)
+}
+
+func (suite *MiddlewaresTestSuite) TestS2SSignature_BasicAuth_Error() {
+	ctx := suite.s2sRequest(middlewaresProjectSecret, time.Now(), "nonce-1")
+	ctx.Request().Header.Del(common.HeaderXS2SSignature)
+	ctx.Request().Header.Del(common.HeaderXS2SProjectId)
+	ctx.Request().SetBasicAuth(middlewaresProjectId, middlewaresProjectSecret)
+
+	err := serveMiddleware(suite.dispatcher.S2SSignatureMiddleware, ctx)
+	suite.assertS2SError(err, common.ErrorMessageS2SSignatureHeadersNotFound)
+}
+
+// merchantSelectorContext builds the request of the user which is the member of two merchants
+func (suite *MiddlewaresTestSuite) merchantSelectorContext(header, param string) echo.Context {
+	bill := &billMock.BillingService{}
+	bill.On("GetMerchantsForUser", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetMerchantsForUserResponse{
+			Status: billingpb.ResponseStatusOk,
+			Merchants: []*billingpb.MerchantForUserInfo{
+				{Id: "5dbac6a9120a810001a8fe41", Role: "merchant_owner"},
+				{Id: "5dbac6a9120a810001a8fe42", Role: "merchant_view_only"},
+			},
+		}, nil)
+	suite.dispatcher.AppSetForTest().Services.Billing = bill
+
+	req := httptest.NewRequest(http.MethodGet, common.AuthUserGroupPath+"/order", nil)
+
+	if header != "" {
+		req.Header.Set(common.HeaderXMerchantId, header)
+	}
+
+	ctx, _ := newTestContext(req)
+	common.SetUserContext(ctx, &common.AuthUser{Id: middlewaresUserId})
+
+	if param != "" {
+		ctx.SetParamNames(common.RequestParameterMerchantId)
+		ctx.SetParamValues(param)
+	}
+
+	return ctx
+}
+
+func (suite *MiddlewaresTestSuite) TestMerchantSelector_Default_FirstMerchant() {
+	ctx := suite.merchantSelectorContext("", "")
+	err := serveMiddleware(suite.dispatcher.MerchantSelectorMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "5dbac6a9120a810001a8fe41", common.ExtractUserContext(ctx).MerchantId)
+	assert.Equal(suite.T(), "merchant_owner", common.ExtractUserContext(ctx).Role)
+}
+
+func (suite *MiddlewaresTestSuite) TestMerchantSelector_Header_Ok() {
+	ctx := suite.merchantSelectorContext("5dbac6a9120a810001a8fe42", "")
+	err := serveMiddleware(suite.dispatcher.MerchantSelectorMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "5dbac6a9120a810001a8fe42", common.ExtractUserContext(ctx).MerchantId)
+	assert.Equal(suite.T(), "merchant_view_only", common.ExtractUserContext(ctx).Role)
+}
+
+func (suite *MiddlewaresTestSuite) TestMerchantSelector_ParamOverHeader_Ok() {
+	ctx := suite.merchantSelectorContext("5dbac6a9120a810001a8fe42", "5dbac6a9120a810001a8fe41")
+	err := serveMiddleware(suite.dispatcher.MerchantSelectorMiddleware, ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "5dbac6a9120a810001a8fe41", common.ExtractUserContext(ctx).MerchantId)
+	assert.Equal(suite.T(), "merchant_owner", common.ExtractUserContext(ctx).Role)
+}
+
+func (suite *MiddlewaresTestSuite) TestMerchantSelector_NotMember_Error() {
+	ctx := suite.merchantSelectorContext("5dbac6a9120a810001a8fe43", "")
+	err := serveMiddleware(suite.dispatcher.MerchantSelectorMiddleware, ctx)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusForbidden, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageMerchantNotMember, httpErr.Message)
+	assert.Equal(suite.T(), "ma000135", common.ErrorMessageMerchantNotMember.Code)
+	assert.Empty(suite.T(), common.ExtractUserContext(ctx).MerchantId)
+}
@@ -2,12 +2,14 @@ package dispatcher
 
 import (
 	"context"
+	"fmt"
 	jwtverifier "github.com/ProtocolONE/authone-jwt-verifier-golang"
 	geoip "github.com/ProtocolONE/geoip-service/pkg"
 	"github.com/ProtocolONE/geoip-service/pkg/proto"
 	"github.com/ProtocolONE/go-core/v2/pkg/config"
 	"github.com/ProtocolONE/go-core/v2/pkg/invoker"
 	"github.com/ProtocolONE/go-core/v2/pkg/provider"
+	"github.com/globalsign/mgo"
 	"github.com/google/wire"
 	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
 	"github.com/paysuper/paysuper-management-api/internal/validators"
@@ -17,6 +19,7 @@ import (
 	"github.com/paysuper/paysuper-proto/go/reporterpb"
 	"github.com/paysuper/paysuper-proto/go/taxpb"
 	"gopkg.in/go-playground/validator.v9"
+	"os"
 )
 
 // ProviderCfg
@@ -47,6 +50,119 @@ func ProviderJwtVerifier(cfg *common.Config) *jwtverifier.JwtVerifier {
 	})
 }
 
+// ProviderIdempotencyStore
+func ProviderIdempotencyStore(cfg *common.Config, db *mgo.Database) (common.IdempotencyStore, error) {
+	return common.NewMongoIdempotencyStore(db, cfg.IdempotencyKeyLease, cfg.IdempotencyKeyTtl)
+}
+
+// ProviderTestIdempotencyStore
+func ProviderTestIdempotencyStore(cfg *common.Config) common.IdempotencyStore {
+	return common.NewMemoryIdempotencyStore(cfg.IdempotencyKeyLease, cfg.IdempotencyKeyTtl)
+}
+
+// ProviderMongo
+func ProviderMongo(cfg *common.Config) (*mgo.Database, func(), error) {
+	session, err := mgo.Dial(cfg.MongoDsn)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session.DB(""), session.Close, nil
+}
+
+// ProviderApiKeyStore
+func ProviderApiKeyStore(db *mgo.Database) (common.ApiKeyStore, error) {
+	return common.NewMongoApiKeyStore(db)
+}
+
+// ProviderTestApiKeyStore
+func ProviderTestApiKeyStore() common.ApiKeyStore {
+	return common.NewMemoryApiKeyStore()
+}
+
+// ProviderAuthCache
+func ProviderAuthCache(cfg *common.Config) common.AuthCache {
+	return common.NewMemoryAuthCache(cfg.AuthCacheSize)
+}
+
+// ProviderApprovalStore
+func ProviderApprovalStore(db *mgo.Database) (common.ApprovalStore, error) {
+	return common.NewMongoApprovalStore(db)
+}
+
+// ProviderSessionStore
+func ProviderSessionStore(cfg *common.Config, db *mgo.Database) (common.SessionStore, error) {
+	return common.NewMongoSessionStore(db, cfg.SessionTtl)
+}
+
+// ProviderTestSessionStore
+func ProviderTestSessionStore(cfg *common.Config) common.SessionStore {
+	return common.NewMemorySessionStore(cfg.SessionTtl)
+}
+
+// ProviderBulkRefundStore
+func ProviderBulkRefundStore(cfg *common.Config, db *mgo.Database) (common.BulkRefundStore, error) {
+	return common.NewMongoBulkRefundStore(db, cfg.BulkRefundJobTtl)
+}
+
+// ProviderRefundStore
+func ProviderRefundStore(db *mgo.Database) (common.RefundStore, error) {
+	return common.NewMongoRefundStore(db)
+}
+
+// ProviderBackgroundTasks
+func ProviderBackgroundTasks() *common.BackgroundTasks {
+	return common.NewBackgroundTasks()
+}
+
+// ProviderNonceStore
+func ProviderNonceStore(db *mgo.Database) (common.NonceStore, error) {
+	return common.NewMongoNonceStore(db)
+}
+
+// ProviderTestNonceStore
+func ProviderTestNonceStore() common.NonceStore {
+	return common.NewMemoryNonceStore()
+}
+
+// ProviderAuditStore
+func ProviderAuditStore(cfg *common.Config, ms *micro.Micro, db *mgo.Database) (common.AuditStore, error) {
+	sink, err := auditSink(cfg, ms)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return common.NewMongoAuditStore(db, sink)
+}
+
+// ProviderTestAuditStore
+func ProviderTestAuditStore(cfg *common.Config, ms *micro.Micro) (common.AuditStore, error) {
+	sink, err := auditSink(cfg, ms)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return common.NewMemoryAuditStore(cfg.AuditStoreSize, sink), nil
+}
+
+func auditSink(cfg *common.Config, ms *micro.Micro) (common.AuditSink, error) {
+	switch cfg.AuditSink {
+	case common.AuditSinkStdout:
+		return common.NewWriterAuditSink(os.Stdout), nil
+	case common.AuditSinkFile:
+		return common.NewFileAuditSink(cfg.AuditFilePath)
+	case common.AuditSinkBroker:
+		return common.NewBrokerAuditSink(ms.Broker(), cfg.AuditBrokerTopic), nil
+	case common.AuditSinkNone:
+		return common.NewNopAuditSink(), nil
+	}
+
+	return nil, fmt.Errorf("unknown audit sink %q", cfg.AuditSink)
+}
+
 // ProviderServices
 func ProviderServices(srv *micro.Micro, cfg *micro.Config) common.Services {
 	return common.Services{
@@ -114,6 +230,9 @@ func ProviderValidators(v *validators.ValidatorSet) (validate *validator.Validat
 	if err = validate.RegisterValidation("datetime_rfc3339", v.DateTimeRFC3339Validator); err != nil {
 		return
 	}
+	if err = common.RegisterValidationTranslations(validate); err != nil {
+		return
+	}
 	return validate, func() {}, nil
 }
 
@@ -129,15 +248,33 @@ var (
 		ProviderDispatcher,
 		ProviderServices,
 		ProviderJwtVerifier,
+		ProviderIdempotencyStore,
+		ProviderNonceStore,
+		ProviderApiKeyStore,
+		ProviderAuthCache,
+		ProviderSessionStore,
+		ProviderAuditStore,
+		ProviderApprovalStore,
+		ProviderBulkRefundStore,
+		ProviderRefundStore,
+		ProviderBackgroundTasks,
 		ProviderValidators,
 		ProviderCfg,
 		ProviderGlobalCfg,
+		ProviderMongo,
 		wire.Struct(new(AppSet), "*"),
 	)
-	// Dependencies: go-shared/provider.AwareSet, internal/*validators.ValidatorSet, common.Services, common.Handlers, go-shared/config.Configurator
+	// Dependencies: go-shared/provider.AwareSet, internal/*validators.ValidatorSet, common.Services, common.Handlers, common.ApprovalStore, go-shared/config.Configurator
 	WireTestSet = wire.NewSet(
 		ProviderDispatcher,
 		ProviderJwtVerifier,
+		ProviderTestIdempotencyStore,
+		ProviderTestNonceStore,
+		ProviderTestApiKeyStore,
+		ProviderAuthCache,
+		ProviderTestSessionStore,
+		ProviderTestAuditStore,
+		ProviderBackgroundTasks,
 		ProviderValidators,
 		ProviderCfg,
 		ProviderGlobalCfg,
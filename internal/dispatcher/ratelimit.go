@@ -0,0 +1,189 @@
+package dispatcher
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rateLimiterCleanupInterval = time.Minute
+)
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a token bucket limiter with separate bucket per key
+type rateLimiter struct {
+	mx          sync.Mutex
+	rate        float64
+	burst       int
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+type rateLimitResult struct {
+	allowed    bool
+	limit      int
+	remaining  int
+	retryAfter time.Duration
+	reset      time.Duration
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+
+	return &rateLimiter{
+		rate:        rate,
+		burst:       burst,
+		buckets:     make(map[string]*tokenBucket),
+		lastCleanup: time.Now(),
+	}
+}
+
+func (l *rateLimiter) take(key string, now time.Time) rateLimitResult {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.cleanup(now)
+
+	bucket, ok := l.buckets[key]
+
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(float64(l.burst), bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	res := rateLimitResult{limit: l.burst}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		res.allowed = true
+	} else {
+		res.retryAfter = l.duration(1 - bucket.tokens)
+	}
+
+	res.remaining = int(math.Floor(bucket.tokens))
+	res.reset = l.duration(float64(l.burst) - bucket.tokens)
+
+	return res
+}
+
+// cleanup removes buckets which are already refilled, they are equal to the new ones
+func (l *rateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < rateLimiterCleanupInterval {
+		return
+	}
+
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastCleanup = now
+}
+
+func (l *rateLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// parseTrustedProxies parses the comma separated IPs and CIDRs of the proxies
+func parseTrustedProxies(value string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+
+		if item == "" {
+			continue
+		}
+
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q is not an IP or CIDR", item)
+			}
+
+			bits := 8 * net.IPv4len
+
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(item)
+
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not an IP or CIDR", item)
+		}
+
+		proxies = append(proxies, ipNet)
+	}
+
+	return proxies, nil
+}
+
+// clientIp returns the address of the client. X-Forwarded-For is read from the right while the hops are trusted
+// proxies, the rest of the header is set by the client and may be spoofed. X-Real-IP is never trusted.
+func clientIp(req *http.Request, trusted []*net.IPNet) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+
+	if !isTrustedProxy(ip, trusted) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(req.Header[echo.HeaderXForwardedFor], ","), ",")
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+
+		if hop == "" || net.ParseIP(hop) == nil {
+			break
+		}
+
+		ip = hop
+
+		if !isTrustedProxy(hop, trusted) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+
+	if parsed == nil {
+		return false
+	}
+
+	for _, proxy := range trusted {
+		if proxy.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
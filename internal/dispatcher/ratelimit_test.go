@@ -0,0 +1,167 @@
+package dispatcher_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	dispatcher *dispatcher.Dispatcher
+}
+
+func Test_RateLimit(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+func (suite *RateLimitTestSuite) SetupTest() {
+	suite.dispatcher = newTestDispatcher()
+}
+
+func (suite *RateLimitTestSuite) TearDownTest() {}
+
+// serve runs the request of the merchant through the limiter
+func (suite *RateLimitTestSuite) serve(mw echo.MiddlewareFunc, merchantId string) (*httptest.ResponseRecorder, error) {
+	ctx, rec := newTestContext(httptest.NewRequest(http.MethodGet, common.AuthUserGroupPath+"/order", nil))
+	common.SetUserContext(ctx, &common.AuthUser{Id: middlewaresUserId, MerchantId: merchantId})
+
+	return rec, serveMiddleware(mw, ctx)
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_Burst() {
+	// the bucket isn't refilled during the test
+	mw := suite.dispatcher.RateLimitMiddleware(0.01, 3, dispatcher.RateLimitKeyMerchant)
+
+	for i := 0; i < 3; i++ {
+		rec, err := suite.serve(mw, "merchant_1")
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), "3", rec.Header().Get(common.HeaderXRateLimitLimit))
+		assert.Equal(suite.T(), strconv.Itoa(2-i), rec.Header().Get(common.HeaderXRateLimitRemaining))
+	}
+
+	rec, err := suite.serve(mw, "merchant_1")
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusTooManyRequests, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageTooManyRequests, httpErr.Message)
+	assert.Equal(suite.T(), "0", rec.Header().Get(common.HeaderXRateLimitRemaining))
+	// the token is refilled in 100 seconds with 0.01 tokens per second
+	assert.Equal(suite.T(), "100", rec.Header().Get(common.HeaderRetryAfter))
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_KeysIsolated() {
+	mw := suite.dispatcher.RateLimitMiddleware(0.01, 1, dispatcher.RateLimitKeyMerchant)
+
+	_, err := suite.serve(mw, "merchant_1")
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.serve(mw, "merchant_1")
+	assert.Error(suite.T(), err)
+
+	rec, err := suite.serve(mw, "merchant_2")
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), rec.Header().Get(common.HeaderRetryAfter))
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_Disabled() {
+	mw := suite.dispatcher.RateLimitMiddleware(0, 1, dispatcher.RateLimitKeyMerchant)
+
+	for i := 0; i < 3; i++ {
+		rec, err := suite.serve(mw, "merchant_1")
+		assert.NoError(suite.T(), err)
+		assert.Empty(suite.T(), rec.Header().Get(common.HeaderXRateLimitLimit))
+	}
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_Refill() {
+	now := time.Now()
+	allowed := dispatcher.RateLimitAllowedForTest(
+		2,
+		2,
+		"merchant_1",
+		now,
+		now,
+		now,
+		now.Add(400*time.Millisecond),
+		now.Add(600*time.Millisecond),
+		now.Add(time.Minute),
+		now.Add(time.Minute),
+		now.Add(time.Minute),
+	)
+
+	// the burst is spent, a token is refilled in 500ms, the bucket never holds more than the burst
+	assert.Equal(suite.T(), []bool{true, true, false, false, true, true, true, false}, allowed)
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_Keys() {
+	ctx, _ := newTestContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.Request().RemoteAddr = "10.0.0.1:1234"
+
+	assert.Equal(suite.T(), "ip:10.0.0.1", dispatcher.RateLimitKeyMerchant(ctx))
+	assert.Equal(suite.T(), "ip:10.0.0.1", dispatcher.RateLimitKeyUser(ctx))
+	assert.Equal(suite.T(), "ip:10.0.0.1", dispatcher.RateLimitKeyProject(ctx))
+
+	common.SetUserContext(ctx, &common.AuthUser{Id: "user_1", MerchantId: "merchant_1", ProjectId: "project_1"})
+	assert.Equal(suite.T(), "merchant:merchant_1", dispatcher.RateLimitKeyMerchant(ctx))
+	assert.Equal(suite.T(), "user:user_1", dispatcher.RateLimitKeyUser(ctx))
+	assert.Equal(suite.T(), "project:project_1", dispatcher.RateLimitKeyProject(ctx))
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_SpoofedHeaders_Ignored() {
+	ctx, _ := newTestContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.Request().RemoteAddr = "10.0.0.1:1234"
+	ctx.Request().Header.Set(echo.HeaderXForwardedFor, "1.1.1.1")
+	ctx.Request().Header.Set(echo.HeaderXRealIP, "2.2.2.2")
+
+	assert.Equal(suite.T(), "ip:10.0.0.1", dispatcher.RateLimitKeyRemoteIp(ctx))
+
+	// the peer isn't the trusted proxy
+	key, err := dispatcher.RateLimitKeyClientIpForTest("10.1.0.0/16", ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "ip:10.0.0.1", key)
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_TrustedProxy_ForwardedClient() {
+	tests := []struct {
+		name      string
+		proxies   string
+		forwarded []string
+		key       string
+	}{
+		{"single hop", "10.0.0.1", []string{"3.3.3.3"}, "ip:3.3.3.3"},
+		{"spoofed hops before the client", "10.0.0.0/8", []string{"1.1.1.1, 3.3.3.3"}, "ip:3.3.3.3"},
+		{"chain of proxies", "10.0.0.0/8, 192.168.0.1", []string{"1.1.1.1, 3.3.3.3, 192.168.0.1", "10.0.0.2"}, "ip:3.3.3.3"},
+		{"invalid hop", "10.0.0.0/8", []string{"3.3.3.3, not_ip, 10.0.0.2"}, "ip:10.0.0.2"},
+		{"no header", "10.0.0.0/8", nil, "ip:10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		ctx, _ := newTestContext(httptest.NewRequest(http.MethodGet, "/", nil))
+		ctx.Request().RemoteAddr = "10.0.0.1:1234"
+
+		for _, value := range tt.forwarded {
+			ctx.Request().Header.Add(echo.HeaderXForwardedFor, value)
+		}
+
+		key, err := dispatcher.RateLimitKeyClientIpForTest(tt.proxies, ctx)
+		assert.NoError(suite.T(), err, tt.name)
+		assert.Equal(suite.T(), tt.key, key, tt.name)
+	}
+}
+
+func (suite *RateLimitTestSuite) TestRateLimit_TrustedProxy_Invalid() {
+	ctx, _ := newTestContext(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	_, err := dispatcher.RateLimitKeyClientIpForTest("10.0.0.1, proxy.local", ctx)
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,76 @@
+package dispatcher
+
+import (
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/pkg/traceparent"
+	"net/http"
+)
+
+// TracingMiddleware starts the span for every routed request. Parent span is taken from W3C traceparent
+// header, otherwise from the tracer native headers. The span is passed to the go-micro client calls
+// via request context and the trace id is attached to the request logs.
+func (d *Dispatcher) TracingMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		route := ctx.Path()
+
+		if route == "" {
+			route = "unknown"
+		}
+
+		var (
+			opts   []opentracing.StartSpanOption
+			parent opentracing.SpanContext
+		)
+
+		if sc, ok := traceparent.Parse(req.Header.Get(traceparent.Header)); ok {
+			parent = sc
+		} else if sc, err := d.T().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header)); err == nil {
+			parent = sc
+		}
+
+		if parent != nil {
+			opts = append(opts, opentracing.ChildOf(parent))
+		}
+
+		span := d.T().StartSpan(req.Method+" "+route, opts...)
+		defer span.Finish()
+
+		ext.SpanKindRPCServer.Set(span)
+		ext.HTTPMethod.Set(span, req.Method)
+		ext.HTTPUrl.Set(span, req.URL.String())
+		span.SetTag("http.route", route)
+		span.SetTag("http.route_group", common.RouteGroupName(route))
+
+		ctx.SetRequest(req.WithContext(opentracing.ContextWithSpan(req.Context(), span)))
+
+		traceId := traceparent.TraceId(span.Context())
+
+		if traceId == "" && parent != nil {
+			traceId = traceparent.TraceId(parent)
+		}
+
+		if traceId != "" {
+			common.AddLogFieldsContext(ctx, logger.Fields{common.LogFieldTraceId: traceId})
+		}
+
+		err := next(ctx)
+		status := responseStatus(ctx, err)
+
+		ext.HTTPStatusCode.Set(span, uint16(status))
+
+		if status >= http.StatusInternalServerError {
+			ext.Error.Set(span, true)
+		}
+
+		if err != nil {
+			span.LogKV("event", "error", "message", err.Error())
+		}
+
+		return err
+	}
+}
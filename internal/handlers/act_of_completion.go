@@ -60,8 +60,7 @@ func (h *ActOfCompletionApiV1) list(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Billing.GetActsOfCompletionList(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetActsOfCompletionList", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetActsOfCompletionList")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -13,6 +13,7 @@ import (
 type AdminUsersRoute struct {
 	dispatch common.HandlerSet
 	cfg      common.Config
+	sessions common.SessionStore
 	provider.LMT
 }
 
@@ -24,12 +25,13 @@ const (
 	adminUserRole     = "/users/roles/:role_id"
 )
 
-func NewAdminUsersRoute(set common.HandlerSet, cfg *common.Config) *AdminUsersRoute {
+func NewAdminUsersRoute(set common.HandlerSet, sessions common.SessionStore, cfg *common.Config) *AdminUsersRoute {
 	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "AdminUsersRoute"})
 	return &AdminUsersRoute{
 		dispatch: set,
 		LMT:      &set.AwareSet,
 		cfg:      *cfg,
+		sessions: sessions,
 	}
 }
 
@@ -65,13 +67,16 @@ func (h *AdminUsersRoute) changeRole(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ChangeRoleForAdminUser(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeRoleForAdminUser")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeRoleForAdminUser")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
+	// the user signs in again to get the permissions of the new role
+	revokeUserSessions(ctx, h.L(), h.sessions, h.roleUserId(ctx, req.RoleId))
+
 	return ctx.NoContent(http.StatusOK)
 }
 
@@ -89,7 +94,7 @@ func (h *AdminUsersRoute) listUsers(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetAdminUsers(ctx.Request().Context(), &billingpb.EmptyRequest{})
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(&billingpb.EmptyRequest{}, err, billingpb.ServiceName, "GetAdminUsers")
+		return h.dispatch.SrvCallHandler(ctx, &billingpb.EmptyRequest{}, err, billingpb.ServiceName, "GetAdminUsers")
 	}
 
 	if res.Status != http.StatusOK {
@@ -120,7 +125,7 @@ func (h *AdminUsersRoute) sendInvite(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.InviteUserAdmin(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "InviteUserAdmin")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "InviteUserAdmin")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -151,7 +156,7 @@ func (h *AdminUsersRoute) resendInvite(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ResendInviteAdmin(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ResendInviteAdmin")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ResendInviteAdmin")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -176,7 +181,7 @@ func (h *AdminUsersRoute) listRoles(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetRoleList(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetRoleList")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetRoleList")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -200,16 +205,19 @@ func (h *AdminUsersRoute) deleteUser(ctx echo.Context) error {
 		return err
 	}
 
+	userId := h.roleUserId(ctx, req.RoleId)
 	res, err := h.dispatch.Services.Billing.DeleteAdminUser(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "DeleteAdminUser")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "DeleteAdminUser")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
+	revokeUserSessions(ctx, h.L(), h.sessions, userId)
+
 	return ctx.JSON(http.StatusOK, res)
 }
 
@@ -234,7 +242,7 @@ func (h *AdminUsersRoute) getUser(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetAdminUserRole(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetAdminUserRole")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetAdminUserRole")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -243,3 +251,20 @@ func (h *AdminUsersRoute) getUser(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, res)
 }
+
+// roleUserId returns the user of the role, it's empty if the role is unknown or the invitation isn't accepted yet
+func (h *AdminUsersRoute) roleUserId(ctx echo.Context, roleId string) string {
+	req := &billingpb.AdminRoleRequest{RoleId: roleId}
+	res, err := h.dispatch.Services.Billing.GetAdminUserRole(ctx.Request().Context(), req)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return ""
+	}
+
+	if res.Status != billingpb.ResponseStatusOk || res.UserRole == nil {
+		return ""
+	}
+
+	return res.UserRole.UserId
+}
@@ -14,12 +14,14 @@ import (
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type AdminUsersTestSuite struct {
 	suite.Suite
-	router *AdminUsersRoute
-	caller *test.EchoReqResCaller
+	router   *AdminUsersRoute
+	caller   *test.EchoReqResCaller
+	sessions common.SessionStore
 }
 
 func Test_AdminUsers(t *testing.T) {
@@ -38,7 +40,8 @@ func (suite *AdminUsersTestSuite) SetupTest() {
 	}
 	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
 		mw.Pre(test.PreAuthUserMiddleware(user))
-		suite.router = NewAdminUsersRoute(set.HandlerSet, set.GlobalConfig)
+		suite.sessions = common.NewMemorySessionStore(time.Hour)
+		suite.router = NewAdminUsersRoute(set.HandlerSet, suite.sessions, set.GlobalConfig)
 		return common.Handlers{
 			suite.router,
 		}
@@ -203,10 +206,19 @@ func (suite *AdminUsersTestSuite) TestAdminChangeRole_EmptyBodyError() {
 func (suite *AdminUsersTestSuite) TestAdminChangeRole_Ok() {
 	shouldBe := require.New(suite.T())
 
+	session := common.NewSession("some_access_token", "eeeeeeeeeeeeeeeeeeeeeeee", "", "")
+	revoked, err := suite.sessions.Touch(session)
+	shouldBe.NoError(err)
+	shouldBe.False(revoked)
+
 	billingService := suite.router.dispatch.Services.Billing.(*mocks.BillingService)
 	billingService.On("ChangeRoleForAdminUser", mock2.Anything, mock2.Anything).Return(&billingpb.EmptyResponseWithStatus{
 		Status: 200,
 	}, nil)
+	billingService.On("GetAdminUserRole", mock2.Anything, mock2.Anything).Return(&billingpb.UserRoleResponse{
+		Status:   200,
+		UserRole: &billingpb.UserRole{UserId: session.UserId},
+	}, nil)
 
 	res, err := suite.caller.Builder().
 		Method(http.MethodPut).
@@ -219,4 +231,40 @@ func (suite *AdminUsersTestSuite) TestAdminChangeRole_Ok() {
 	shouldBe.NoError(err)
 	shouldBe.Equal(http.StatusOK, res.Code)
 	shouldBe.Empty(res.Body.String())
+
+	revoked, err = suite.sessions.Touch(session)
+	shouldBe.NoError(err)
+	shouldBe.True(revoked)
+}
+
+func (suite *AdminUsersTestSuite) TestAdminDeleteUser_Ok() {
+	shouldBe := require.New(suite.T())
+
+	session := common.NewSession("some_access_token", "eeeeeeeeeeeeeeeeeeeeeeee", "", "")
+	revoked, err := suite.sessions.Touch(session)
+	shouldBe.NoError(err)
+	shouldBe.False(revoked)
+
+	billingService := suite.router.dispatch.Services.Billing.(*mocks.BillingService)
+	billingService.On("GetAdminUserRole", mock2.Anything, mock2.Anything).Return(&billingpb.UserRoleResponse{
+		Status:   200,
+		UserRole: &billingpb.UserRole{UserId: session.UserId},
+	}, nil)
+	billingService.On("DeleteAdminUser", mock2.Anything, mock2.Anything).Return(&billingpb.EmptyResponseWithStatus{
+		Status: 200,
+	}, nil)
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodDelete).
+		Params(":"+common.RequestRoleId, bson.NewObjectId().Hex()).
+		Path(common.SystemUserGroupPath + adminUserRole).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	shouldBe.NoError(err)
+	shouldBe.Equal(http.StatusOK, res.Code)
+
+	revoked, err = suite.sessions.Touch(session)
+	shouldBe.NoError(err)
+	shouldBe.True(revoked)
 }
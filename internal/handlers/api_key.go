@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/ProtocolONE/go-core/v2/pkg/provider"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"net/http"
+	"time"
+)
+
+const (
+	apiKeysPath       = "/projects/:project_id/api_keys"
+	apiKeysIdPath     = "/projects/:project_id/api_keys/:api_key_id"
+	apiKeysRotatePath = "/projects/:project_id/api_keys/:api_key_id/rotate"
+)
+
+type ApiKeyRoute struct {
+	dispatch common.HandlerSet
+	cfg      common.Config
+	keys     common.ApiKeyStore
+	provider.LMT
+}
+
+type ApiKeyRequest struct {
+	// The name of the API key to distinguish the keys of the project.
+	Name string `json:"name" validate:"required,max=255"`
+	// The list of the scopes available for the key. Available values: read-orders, create-tokens, refunds.
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=read-orders create-tokens refunds"`
+	// The date and time when the key expires. The key never expires if empty.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func NewApiKeyRoute(set common.HandlerSet, keys common.ApiKeyStore, cfg *common.Config) *ApiKeyRoute {
+	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "ApiKeyRoute"})
+	return &ApiKeyRoute{
+		dispatch: set,
+		LMT:      &set.AwareSet,
+		cfg:      *cfg,
+		keys:     keys,
+	}
+}
+
+func (h *ApiKeyRoute) Route(groups *common.Groups) {
+	groups.AuthUser.GET(apiKeysPath, h.listApiKeys)
+	groups.AuthUser.POST(apiKeysPath, h.createApiKey)
+	groups.AuthUser.POST(apiKeysRotatePath, h.rotateApiKey)
+	groups.AuthUser.DELETE(apiKeysIdPath, h.revokeApiKey)
+}
+
+// @summary Get the list of the project API keys
+// @desc Get the list of the project API keys without secrets
+// @id apiKeysPathListApiKeys
+// @tag Project
+// @accept application/json
+// @produce application/json
+// @success 200 {array} common.ApiKey Returns the list of the API keys
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 404 {object} billingpb.ResponseErrorMessage The project not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param project_id path {string} true The unique identifier for the project.
+// @router /admin/api/v1/projects/{project_id}/api_keys [get]
+func (h *ApiKeyRoute) listApiKeys(ctx echo.Context) error {
+	if _, err := h.getProject(ctx); err != nil {
+		return err
+	}
+
+	keys, err := h.keys.List(ctx.Param(common.RequestParameterProjectId))
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	items := make([]*common.ApiKey, 0, len(keys))
+
+	for _, key := range keys {
+		items = append(items, key.Public())
+	}
+
+	return ctx.JSON(http.StatusOK, items)
+}
+
+// @summary Create the project API key
+// @desc Create the API key with the scopes for S2S requests of the project. The secret is returned only in this response.
+// @id apiKeysPathCreateApiKey
+// @tag Project
+// @accept application/json
+// @produce application/json
+// @body ApiKeyRequest
+// @success 201 {object} common.ApiKey Returns the API key with the secret
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 404 {object} billingpb.ResponseErrorMessage The project not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param project_id path {string} true The unique identifier for the project.
+// @router /admin/api/v1/projects/{project_id}/api_keys [post]
+func (h *ApiKeyRoute) createApiKey(ctx echo.Context) error {
+	req := &ApiKeyRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return common.NewValidationHTTPError(err)
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageApiKeyExpiryInvalid)
+	}
+
+	project, err := h.getProject(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	key, err := common.NewApiKey(ctx.Param(common.RequestParameterProjectId), project.MerchantId, req.Name, req.Scopes, req.ExpiresAt)
+
+	if err == nil {
+		err = h.keys.Create(key)
+	}
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	return ctx.JSON(http.StatusCreated, key)
+}
+
+// @summary Rotate the project API key
+// @desc Create the new API key with the same name, scopes and expiration time. The rotated key remains valid for the grace period.
+// @id apiKeysRotatePathRotateApiKey
+// @tag Project
+// @accept application/json
+// @produce application/json
+// @success 201 {object} common.ApiKey Returns the new API key with the secret
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 404 {object} billingpb.ResponseErrorMessage The project or API key not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param project_id path {string} true The unique identifier for the project.
+// @param api_key_id path {string} true The unique identifier for the API key.
+// @router /admin/api/v1/projects/{project_id}/api_keys/{api_key_id}/rotate [post]
+func (h *ApiKeyRoute) rotateApiKey(ctx echo.Context) error {
+	project, err := h.getProject(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	old, err := h.keys.Get(ctx.Param(common.RequestParameterProjectId), ctx.Param(common.RequestParameterApiKeyId))
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if old == nil {
+		return echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageApiKeyNotFound)
+	}
+
+	key, err := common.NewApiKey(ctx.Param(common.RequestParameterProjectId), project.MerchantId, old.Name, old.Scopes, old.ExpiresAt)
+
+	if err == nil {
+		err = h.keys.Create(key)
+	}
+
+	if err == nil {
+		graceEnd := time.Now().UTC().Add(h.cfg.ApiKeyRotationGracePeriod)
+
+		if old.ExpiresAt == nil || old.ExpiresAt.After(graceEnd) {
+			old.ExpiresAt = &graceEnd
+		}
+
+		err = h.keys.Update(old)
+	}
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	return ctx.JSON(http.StatusCreated, key)
+}
+
+// @summary Revoke the project API key
+// @desc Revoke the API key immediately
+// @id apiKeysIdPathRevokeApiKey
+// @tag Project
+// @accept application/json
+// @produce application/json
+// @success 204 "The API key is revoked"
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 404 {object} billingpb.ResponseErrorMessage The project or API key not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param project_id path {string} true The unique identifier for the project.
+// @param api_key_id path {string} true The unique identifier for the API key.
+// @router /admin/api/v1/projects/{project_id}/api_keys/{api_key_id} [delete]
+func (h *ApiKeyRoute) revokeApiKey(ctx echo.Context) error {
+	if _, err := h.getProject(ctx); err != nil {
+		return err
+	}
+
+	deleted, err := h.keys.Delete(ctx.Param(common.RequestParameterProjectId), ctx.Param(common.RequestParameterApiKeyId))
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if !deleted {
+		return echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageApiKeyNotFound)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// getProject returns the project from the path if it belongs to the merchant of the authorized user
+func (h *ApiKeyRoute) getProject(ctx echo.Context) (*billingpb.Project, error) {
+	req := &billingpb.GetProjectRequest{
+		ProjectId:  ctx.Param(common.RequestParameterProjectId),
+		MerchantId: common.ExtractUserContext(ctx).MerchantId,
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return nil, common.NewValidationHTTPError(err)
+	}
+
+	res, err := h.dispatch.Services.Billing.GetProject(ctx.Request().Context(), req)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if res.Status != billingpb.ResponseStatusOk {
+		return nil, echo.NewHTTPError(int(res.Status), res.Message)
+	}
+
+	return res.Item, nil
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/mock"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type ApiKeyTestSuite struct {
+	suite.Suite
+	router    *ApiKeyRoute
+	caller    *test.EchoReqResCaller
+	keys      common.ApiKeyStore
+	projectId string
+}
+
+func Test_ApiKey(t *testing.T) {
+	suite.Run(t, new(ApiKeyTestSuite))
+}
+
+func (suite *ApiKeyTestSuite) SetupTest() {
+	var e error
+	settings := test.DefaultSettings()
+	srv := common.Services{
+		Billing: mock.NewBillingServerOkMock(),
+	}
+	user := &common.AuthUser{
+		Id:         "ffffffffffffffffffffffff",
+		Email:      "test@unit.test",
+		MerchantId: "ffffffffffffffffffffffff",
+	}
+	suite.keys = common.NewMemoryApiKeyStore()
+	suite.projectId = bson.NewObjectId().Hex()
+	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
+		mw.Pre(test.PreAuthUserMiddleware(user))
+		suite.router = NewApiKeyRoute(set.HandlerSet, suite.keys, set.GlobalConfig)
+		return common.Handlers{
+			suite.router,
+		}
+	})
+	if e != nil {
+		panic(e)
+	}
+}
+
+func (suite *ApiKeyTestSuite) TearDownTest() {}
+
+func (suite *ApiKeyTestSuite) TestApiKey_CreateApiKey_Ok() {
+	body := `{"name": "backend", "scopes": ["read-orders", "refunds"]}`
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":"+common.RequestParameterProjectId, suite.projectId).
+		Path(common.AuthUserGroupPath + apiKeysPath).
+		Init(test.ReqInitJSON()).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusCreated, res.Code)
+
+	key := &common.ApiKey{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), key))
+	assert.NotEmpty(suite.T(), key.Id)
+	assert.NotEmpty(suite.T(), key.Secret)
+	assert.Equal(suite.T(), suite.projectId, key.ProjectId)
+	assert.Equal(suite.T(), []string{common.ApiKeyScopeReadOrders, common.ApiKeyScopeRefunds}, key.Scopes)
+
+	res, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterProjectId, suite.projectId).
+		Path(common.AuthUserGroupPath + apiKeysPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	var keys []*common.ApiKey
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), &keys))
+	assert.Len(suite.T(), keys, 1)
+	assert.Equal(suite.T(), key.Id, keys[0].Id)
+	assert.Empty(suite.T(), keys[0].Secret)
+}
+
+func (suite *ApiKeyTestSuite) TestApiKey_CreateApiKey_ValidationError() {
+	body := `{"name": "backend", "scopes": ["read-orders", "everything"]}`
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":"+common.RequestParameterProjectId, suite.projectId).
+		Path(common.AuthUserGroupPath + apiKeysPath).
+		Init(test.ReqInitJSON()).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+}
+
+func (suite *ApiKeyTestSuite) TestApiKey_CreateApiKey_ExpiredError() {
+	body := `{"name": "backend", "scopes": ["read-orders"], "expires_at": "2019-01-01T00:00:00Z"}`
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":"+common.RequestParameterProjectId, suite.projectId).
+		Path(common.AuthUserGroupPath + apiKeysPath).
+		Init(test.ReqInitJSON()).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageApiKeyExpiryInvalid, httpErr.Message)
+}
+
+func (suite *ApiKeyTestSuite) TestApiKey_RotateApiKey_Ok() {
+	old, err := common.NewApiKey(suite.projectId, "ffffffffffffffffffffffff", "backend", []string{common.ApiKeyScopeRefunds}, nil)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.keys.Create(old))
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":"+common.RequestParameterProjectId, suite.projectId, ":"+common.RequestParameterApiKeyId, old.Id).
+		Path(common.AuthUserGroupPath + apiKeysRotatePath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusCreated, res.Code)
+
+	key := &common.ApiKey{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), key))
+	assert.NotEqual(suite.T(), old.Id, key.Id)
+	assert.NotEqual(suite.T(), old.Secret, key.Secret)
+	assert.Equal(suite.T(), old.Scopes, key.Scopes)
+
+	rotated, err := suite.keys.Get(suite.projectId, old.Id)
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), rotated.ExpiresAt)
+	assert.True(suite.T(), rotated.ExpiresAt.After(time.Now()))
+}
+
+func (suite *ApiKeyTestSuite) TestApiKey_RevokeApiKey_Ok() {
+	key, err := common.NewApiKey(suite.projectId, "ffffffffffffffffffffffff", "backend", []string{common.ApiKeyScopeReadOrders}, nil)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.keys.Create(key))
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodDelete).
+		Params(":"+common.RequestParameterProjectId, suite.projectId, ":"+common.RequestParameterApiKeyId, key.Id).
+		Path(common.AuthUserGroupPath + apiKeysIdPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusNoContent, res.Code)
+
+	revoked, err := suite.keys.Get(suite.projectId, key.Id)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), revoked)
+}
+
+func (suite *ApiKeyTestSuite) TestApiKey_RevokeApiKey_NotFound() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodDelete).
+		Params(":"+common.RequestParameterProjectId, suite.projectId, ":"+common.RequestParameterApiKeyId, bson.NewObjectId().Hex()).
+		Path(common.AuthUserGroupPath + apiKeysIdPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusNotFound, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageApiKeyNotFound, httpErr.Message)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/ProtocolONE/go-core/v2/pkg/provider"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"net/http"
+	"net/http/httptest"
+)
+
+const (
+	approvalsPath        = "/approvals"
+	approvalsIdPath      = "/approvals/:approval_id"
+	approvalsApprovePath = "/approvals/:approval_id/approve"
+	approvalsRejectPath  = "/approvals/:approval_id/reject"
+)
+
+type ApprovalRoute struct {
+	dispatch  common.HandlerSet
+	cfg       common.Config
+	approvals common.ApprovalStore
+	provider.LMT
+}
+
+type ApprovalListRequest struct {
+	// The status of the approval requests. Available values: pending, approved, rejected, failed.
+	Status string `json:"status" query:"status" validate:"omitempty,oneof=pending approved rejected failed"`
+	Limit  int    `json:"limit" query:"limit" validate:"omitempty,min=0"`
+	Offset int    `json:"offset" query:"offset" validate:"omitempty,min=0"`
+}
+
+type ApprovalListResponse struct {
+	// The total number of the matching approval requests.
+	Count int `json:"count"`
+	// The list of the approval requests, the newest first.
+	Items []*common.Approval `json:"items"`
+}
+
+type ApprovalDecisionRequest struct {
+	// The comment of the approver.
+	Comment string `json:"comment" validate:"omitempty,max=1000"`
+}
+
+func NewApprovalRoute(set common.HandlerSet, approvals common.ApprovalStore, cfg *common.Config) *ApprovalRoute {
+	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "ApprovalRoute"})
+	return &ApprovalRoute{
+		dispatch:  set,
+		LMT:       &set.AwareSet,
+		cfg:       *cfg,
+		approvals: approvals,
+	}
+}
+
+func (h *ApprovalRoute) Route(groups *common.Groups) {
+	groups.SystemUser.GET(approvalsPath, h.listApprovals)
+	groups.SystemUser.GET(approvalsIdPath, h.getApproval)
+	groups.SystemUser.POST(approvalsApprovePath, h.approve)
+	groups.SystemUser.POST(approvalsRejectPath, h.reject)
+}
+
+// @summary Get the list of the approval requests
+// @desc Get the list of the system users' requests which wait for the approval or were already decided
+// @id approvalsPathListApprovals
+// @tag Approval
+// @accept application/json
+// @produce application/json
+// @success 200 {object} ApprovalListResponse Returns the list of the approval requests
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param status query {string} false The status of the approval requests. Available values: pending, approved, rejected, failed.
+// @param limit query {integer} false The number of approval requests returned in one page. Default value is 100.
+// @param offset query {integer} false The ranking number of the first item on the page.
+// @router /system/api/v1/approvals [get]
+func (h *ApprovalRoute) listApprovals(ctx echo.Context) error {
+	req := &ApprovalListRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return common.NewValidationHTTPError(err)
+	}
+
+	filter := &common.ApprovalFilter{
+		Status: req.Status,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = int(h.cfg.LimitDefault)
+	}
+
+	if filter.Limit > int(h.cfg.LimitMax) {
+		filter.Limit = int(h.cfg.LimitMax)
+	}
+
+	items, count, err := h.approvals.List(filter)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	return ctx.JSON(http.StatusOK, &ApprovalListResponse{Count: count, Items: items})
+}
+
+// @summary Get the approval request
+// @desc Get the approval request with the payload of the original request
+// @id approvalsIdPathGetApproval
+// @tag Approval
+// @accept application/json
+// @produce application/json
+// @success 200 {object} common.Approval Returns the approval request
+// @failure 404 {object} billingpb.ResponseErrorMessage The approval request not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param approval_id path {string} true The unique identifier for the approval request.
+// @router /system/api/v1/approvals/{approval_id} [get]
+func (h *ApprovalRoute) getApproval(ctx echo.Context) error {
+	approval, err := h.approvals.Get(ctx.Param(common.RequestParameterApprovalId))
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if approval == nil {
+		return echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageApprovalNotFound)
+	}
+
+	return ctx.JSON(http.StatusOK, approval)
+}
+
+// @summary Approve the request
+// @desc Approve the pending request and run it on behalf of the system user who made it
+// @id approvalsApprovePathApprove
+// @tag Approval
+// @accept application/json
+// @produce application/json
+// @body ApprovalDecisionRequest
+// @success 200 {object} common.Approval Returns the approval request with the result of the original request
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 403 {object} billingpb.ResponseErrorMessage The request can't be approved by its maker
+// @failure 404 {object} billingpb.ResponseErrorMessage The approval request not found
+// @failure 409 {object} billingpb.ResponseErrorMessage The request is already approved or rejected
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param approval_id path {string} true The unique identifier for the approval request.
+// @router /system/api/v1/approvals/{approval_id}/approve [post]
+func (h *ApprovalRoute) approve(ctx echo.Context) error {
+	approval, err := h.decide(ctx, common.ApprovalStatusApproved)
+
+	if err != nil {
+		return err
+	}
+
+	status, body := h.execute(ctx, approval)
+	approval.Status = common.ApprovalStatusApproved
+	approval.ResultStatus = status
+	approval.Result = common.AuditPayload(body, len(body))
+
+	if status >= http.StatusBadRequest {
+		approval.Status = common.ApprovalStatusFailed
+	}
+
+	if err := h.approvals.Complete(approval.Id, approval.Status, approval.ResultStatus, approval.Result); err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	return ctx.JSON(http.StatusOK, approval)
+}
+
+// @summary Reject the request
+// @desc Reject the pending request, the original request never runs
+// @id approvalsRejectPathReject
+// @tag Approval
+// @accept application/json
+// @produce application/json
+// @body ApprovalDecisionRequest
+// @success 200 {object} common.Approval Returns the rejected approval request
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 403 {object} billingpb.ResponseErrorMessage The request can't be rejected by its maker
+// @failure 404 {object} billingpb.ResponseErrorMessage The approval request not found
+// @failure 409 {object} billingpb.ResponseErrorMessage The request is already approved or rejected
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param approval_id path {string} true The unique identifier for the approval request.
+// @router /system/api/v1/approvals/{approval_id}/reject [post]
+func (h *ApprovalRoute) reject(ctx echo.Context) error {
+	approval, err := h.decide(ctx, common.ApprovalStatusRejected)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, approval)
+}
+
+// decide moves the pending approval to the status, the maker can't decide on own request
+func (h *ApprovalRoute) decide(ctx echo.Context, status string) (*common.Approval, error) {
+	req := &ApprovalDecisionRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return nil, common.NewValidationHTTPError(err)
+	}
+
+	id := ctx.Param(common.RequestParameterApprovalId)
+	approval, err := h.approvals.Get(id)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if approval == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageApprovalNotFound)
+	}
+
+	user := common.ExtractUserContext(ctx)
+
+	if approval.MakerId == user.Id {
+		return nil, echo.NewHTTPError(http.StatusForbidden, common.ErrorMessageApprovalMakerNotAllowed)
+	}
+
+	approval, decided, err := h.approvals.Decide(id, user.Id, status, req.Comment)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if !decided {
+		return nil, echo.NewHTTPError(http.StatusConflict, common.ErrorMessageApprovalAlreadyDecided)
+	}
+
+	return approval, nil
+}
+
+// execute runs the original request through the router with the credentials of the approver,
+// the approval middleware replaces the user with the maker and checks the policy for the maker
+func (h *ApprovalRoute) execute(ctx echo.Context, approval *common.Approval) (int, []byte) {
+	req, err := http.NewRequest(approval.Method, approval.Path, bytes.NewReader(approval.Body))
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return http.StatusInternalServerError, nil
+	}
+
+	if approval.ContentType != "" {
+		req.Header.Set(echo.HeaderContentType, approval.ContentType)
+	}
+
+	for _, name := range []string{echo.HeaderAuthorization, echo.HeaderCookie, echo.HeaderXRequestID} {
+		if value := ctx.Request().Header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	ctx.Echo().ServeHTTP(rec, req.WithContext(common.WithApproval(ctx.Request().Context(), approval)))
+
+	return rec.Code, rec.Body.Bytes()
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/mock"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ApprovalTestSuite struct {
+	suite.Suite
+	router    *ApprovalRoute
+	caller    *test.EchoReqResCaller
+	user      *common.AuthUser
+	approvals common.ApprovalStore
+}
+
+func Test_Approval(t *testing.T) {
+	suite.Run(t, new(ApprovalTestSuite))
+}
+
+func (suite *ApprovalTestSuite) SetupTest() {
+	var e error
+	settings := test.DefaultSettings()
+	settings["dispatcher"].(map[string]interface{})["global"].(map[string]interface{})["approvalRoutes"] = "RoyaltyReportsRoute.changeRoyaltyReport"
+	srv := common.Services{
+		Billing: mock.NewBillingServerOkMock(),
+	}
+	suite.user = &common.AuthUser{
+		Id:    "ffffffffffffffffffffffff",
+		Email: "maker@unit.test",
+	}
+	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
+		mw.Pre(test.PreAuthUserMiddleware(suite.user))
+		suite.approvals = set.ApprovalStore
+		suite.router = NewApprovalRoute(set.HandlerSet, suite.approvals, set.GlobalConfig)
+		return common.Handlers{
+			suite.router,
+			NewRoyaltyReportsRoute(set.HandlerSet, set.GlobalConfig),
+		}
+	})
+	if e != nil {
+		panic(e)
+	}
+}
+
+func (suite *ApprovalTestSuite) TearDownTest() {}
+
+func (suite *ApprovalTestSuite) createApproval() *common.Approval {
+	bodyJson := `{"merchant_id": "5bdc39a95d1e1100019fb7df", "status": "accepted", "correction": {"amount": 100500, "reason": "just for fun :)"}, "payout_id": "5bdc39a95d1e1100019fb7df"}`
+
+	res, err := suite.caller.Builder().
+		Params(":"+common.RequestParameterReportId, bson.NewObjectId().Hex()).
+		Method(http.MethodPost).
+		Path(common.SystemUserGroupPath + royaltyReportsChangePath).
+		Init(test.ReqInitJSON()).
+		BodyString(bodyJson).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusAccepted, res.Code)
+
+	approval := &common.Approval{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), approval))
+	assert.Equal(suite.T(), common.ApprovalStatusPending, approval.Status)
+	assert.Equal(suite.T(), "RoyaltyReportsRoute.changeRoyaltyReport", approval.Action)
+	assert.Equal(suite.T(), suite.user.Id, approval.MakerId)
+	assert.Equal(suite.T(), "5bdc39a95d1e1100019fb7df", approval.MerchantId)
+
+	return approval
+}
+
+func (suite *ApprovalTestSuite) decide(path, id string) (*httptest.ResponseRecorder, error) {
+	return suite.caller.Builder().
+		Params(":"+common.RequestParameterApprovalId, id).
+		Method(http.MethodPost).
+		Path(common.SystemUserGroupPath + path).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+}
+
+func (suite *ApprovalTestSuite) TestApproval_Approve_Ok() {
+	approval := suite.createApproval()
+
+	res, err := suite.caller.Builder().
+		SetQueryParam("status", common.ApprovalStatusPending).
+		Method(http.MethodGet).
+		Path(common.SystemUserGroupPath + approvalsPath).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	list := &ApprovalListResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), list))
+	assert.Equal(suite.T(), 1, list.Count)
+	assert.Equal(suite.T(), approval.Id, list.Items[0].Id)
+
+	suite.user.Id = "eeeeeeeeeeeeeeeeeeeeeeee"
+	res, err = suite.decide(approvalsApprovePath, approval.Id)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	approved := &common.Approval{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), approved))
+	assert.Equal(suite.T(), common.ApprovalStatusApproved, approved.Status)
+	assert.Equal(suite.T(), http.StatusNoContent, approved.ResultStatus)
+	assert.Equal(suite.T(), suite.user.Id, approved.CheckerId)
+
+	_, err = suite.decide(approvalsRejectPath, approval.Id)
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusConflict, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageApprovalAlreadyDecided, httpErr.Message)
+}
+
+func (suite *ApprovalTestSuite) TestApproval_Reject_Ok() {
+	approval := suite.createApproval()
+
+	suite.user.Id = "eeeeeeeeeeeeeeeeeeeeeeee"
+	res, err := suite.decide(approvalsRejectPath, approval.Id)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	stored, err := suite.approvals.Get(approval.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), common.ApprovalStatusRejected, stored.Status)
+	assert.Empty(suite.T(), stored.ResultStatus)
+}
+
+func (suite *ApprovalTestSuite) TestApproval_Approve_MakerNotAllowed() {
+	approval := suite.createApproval()
+
+	_, err := suite.decide(approvalsApprovePath, approval.Id)
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusForbidden, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageApprovalMakerNotAllowed, httpErr.Message)
+}
+
+func (suite *ApprovalTestSuite) TestApproval_Approve_NotFound() {
+	_, err := suite.decide(approvalsApprovePath, bson.NewObjectId().Hex())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusNotFound, httpErr.Code)
+}
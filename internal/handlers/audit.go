@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/ProtocolONE/go-core/v2/pkg/provider"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"net/http"
+	"time"
+)
+
+const (
+	auditPath = "/audit"
+)
+
+type AuditRoute struct {
+	dispatch common.HandlerSet
+	cfg      common.Config
+	audit    common.AuditStore
+	provider.LMT
+}
+
+type AuditListRequest struct {
+	// The unique identifier for the system user who made the action.
+	ActorId string `json:"actor_id" query:"actor_id" validate:"omitempty,max=255"`
+	// The unique identifier for the merchant affected by the action.
+	MerchantId string `json:"merchant_id" query:"merchant_id" validate:"omitempty,hexadecimal,len=24"`
+	// The action name, e.g. replaceCode or OrderRoute.replaceCode.
+	Action string `json:"action" query:"action" validate:"omitempty,max=255"`
+	// The start date of the period in Unix time.
+	DateFrom int64 `json:"date_from" query:"date_from" validate:"omitempty,min=0"`
+	// The end date of the period in Unix time.
+	DateTo int64 `json:"date_to" query:"date_to" validate:"omitempty,min=0"`
+	Limit  int   `json:"limit" query:"limit" validate:"omitempty,min=0"`
+	Offset int   `json:"offset" query:"offset" validate:"omitempty,min=0"`
+}
+
+type AuditListResponse struct {
+	// The total number of the matching records.
+	Count int `json:"count"`
+	// The list of the records, the newest first.
+	Items []*common.AuditRecord `json:"items"`
+}
+
+func NewAuditRoute(set common.HandlerSet, audit common.AuditStore, cfg *common.Config) *AuditRoute {
+	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "AuditRoute"})
+	return &AuditRoute{
+		dispatch: set,
+		LMT:      &set.AwareSet,
+		cfg:      *cfg,
+		audit:    audit,
+	}
+}
+
+func (h *AuditRoute) Route(groups *common.Groups) {
+	groups.SystemUser.GET(auditPath, h.listAudit)
+}
+
+// @summary Get the audit log
+// @desc Get the list of the privileged actions made by the system users. This list can be filtered.
+// @id auditPathListAudit
+// @tag Audit
+// @accept application/json
+// @produce application/json
+// @success 200 {object} AuditListResponse Returns the audit records
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param actor_id query {string} false The unique identifier for the system user who made the action.
+// @param merchant_id query {string} false The unique identifier for the merchant affected by the action.
+// @param action query {string} false The action name.
+// @param date_from query {integer} false The start date of the period.
+// @param date_to query {integer} false The end date of the period.
+// @param limit query {integer} false The number of records returned in one page. Default value is 100.
+// @param offset query {integer} false The ranking number of the first item on the page.
+// @router /system/api/v1/audit [get]
+func (h *AuditRoute) listAudit(ctx echo.Context) error {
+	req := &AuditListRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return common.NewValidationHTTPError(err)
+	}
+
+	filter := &common.AuditFilter{
+		ActorId:    req.ActorId,
+		MerchantId: req.MerchantId,
+		Action:     req.Action,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	}
+
+	if req.DateFrom > 0 {
+		filter.From = time.Unix(req.DateFrom, 0)
+	}
+
+	if req.DateTo > 0 {
+		filter.To = time.Unix(req.DateTo, 0)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = int(h.cfg.LimitDefault)
+	}
+
+	if filter.Limit > int(h.cfg.LimitMax) {
+		filter.Limit = int(h.cfg.LimitMax)
+	}
+
+	items, count, err := h.audit.List(filter)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	return ctx.JSON(http.StatusOK, &AuditListResponse{Count: count, Items: items})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/mock"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+	router *AuditRoute
+	caller *test.EchoReqResCaller
+	audit  common.AuditStore
+}
+
+func Test_Audit(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+func (suite *AuditTestSuite) SetupTest() {
+	var e error
+	settings := test.DefaultSettings()
+	srv := common.Services{
+		Billing: mock.NewBillingServerOkMock(),
+	}
+	user := &common.AuthUser{
+		Id:    "ffffffffffffffffffffffff",
+		Email: "test@unit.test",
+	}
+	suite.audit = common.NewMemoryAuditStore(100, common.NewNopAuditSink())
+	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
+		mw.Pre(test.PreAuthUserMiddleware(user))
+		suite.router = NewAuditRoute(set.HandlerSet, suite.audit, set.GlobalConfig)
+		return common.Handlers{
+			suite.router,
+		}
+	})
+	if e != nil {
+		panic(e)
+	}
+
+	records := []*common.AuditRecord{
+		{ActorId: "actor1", MerchantId: "ffffffffffffffffffffffff", Action: "OnboardingRoute.changeMerchantStatus"},
+		{ActorId: "actor1", MerchantId: "eeeeeeeeeeeeeeeeeeeeeeee", Action: "OrderRoute.replaceCode"},
+		{ActorId: "actor2", MerchantId: "ffffffffffffffffffffffff", Action: "OrderRoute.replaceCode"},
+	}
+
+	for i, record := range records {
+		record.CreatedAt = time.Now().Add(time.Duration(i-len(records)) * time.Hour)
+		assert.NoError(suite.T(), suite.audit.Add(record))
+	}
+}
+
+func (suite *AuditTestSuite) TearDownTest() {}
+
+func (suite *AuditTestSuite) listAudit(query map[string]string) *AuditListResponse {
+	builder := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.SystemUserGroupPath + auditPath)
+
+	for key, value := range query {
+		builder.SetQueryParam(key, value)
+	}
+
+	res, err := builder.Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	rsp := &AuditListResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), rsp))
+
+	return rsp
+}
+
+func (suite *AuditTestSuite) TestAudit_List_Ok() {
+	rsp := suite.listAudit(nil)
+	assert.Equal(suite.T(), 3, rsp.Count)
+	assert.Len(suite.T(), rsp.Items, 3)
+	assert.Equal(suite.T(), "actor2", rsp.Items[0].ActorId)
+}
+
+func (suite *AuditTestSuite) TestAudit_List_Filters() {
+	rsp := suite.listAudit(map[string]string{"actor_id": "actor1", "action": "replaceCode"})
+	assert.Equal(suite.T(), 1, rsp.Count)
+	assert.Equal(suite.T(), "eeeeeeeeeeeeeeeeeeeeeeee", rsp.Items[0].MerchantId)
+
+	rsp = suite.listAudit(map[string]string{"merchant_id": "ffffffffffffffffffffffff", "limit": "1"})
+	assert.Equal(suite.T(), 2, rsp.Count)
+	assert.Len(suite.T(), rsp.Items, 1)
+
+	from := time.Now().Add(-150 * time.Minute).Unix()
+	rsp = suite.listAudit(map[string]string{"date_from": strconv.FormatInt(from, 10)})
+	assert.Equal(suite.T(), 2, rsp.Count)
+}
+
+func (suite *AuditTestSuite) TestAudit_List_ValidationError() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.SystemUserGroupPath+auditPath).
+		SetQueryParam("merchant_id", "unknown").
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,541 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/ProtocolONE/go-core/v2/pkg/provider"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/labstack/echo/v4"
+	"github.com/micro/go-micro/metadata"
+	"github.com/opentracing/opentracing-go"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"gopkg.in/go-playground/validator.v9"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bulkRefundsPath         = "/refunds/bulk"
+	bulkRefundsIdPath       = "/refunds/bulk/:job_id"
+	bulkRefundsIdResultPath = "/refunds/bulk/:job_id/result"
+)
+
+const (
+	bulkRefundMIMECsv = "text/csv"
+	// the number of the invalid rows listed in the error details
+	bulkRefundErrorsMax = 20
+	// the error of the rows left unprocessed at the server shutdown
+	bulkRefundInterrupted = "not processed, the processing was interrupted by the server shutdown"
+	// the error of the rows left unprocessed at the job deadline
+	bulkRefundTimedOut = "not processed, the processing timed out"
+	// the error of the rows left unprocessed by the instance stopped without the graceful shutdown
+	bulkRefundStale = "not processed, the processing was stopped by the server failure"
+	// the time after the job deadline to complete the job by its instance, then the job is stale
+	bulkRefundStaleDelay = time.Minute
+)
+
+var bulkRefundCsvColumns = []string{"order_id", "amount", "reason"}
+
+type BulkRefundRequest struct {
+	// The list of the refunds.
+	Rows []*common.BulkRefundRow `json:"rows"`
+}
+
+type BulkRefundRoute struct {
+	dispatch common.HandlerSet
+	cfg      common.Config
+	jobs     common.BulkRefundStore
+	refunds  common.RefundStore
+	tasks    *common.BackgroundTasks
+	provider.LMT
+}
+
+func NewBulkRefundRoute(
+	set common.HandlerSet,
+	jobs common.BulkRefundStore,
+	refunds common.RefundStore,
+	tasks *common.BackgroundTasks,
+	cfg *common.Config,
+) *BulkRefundRoute {
+	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "BulkRefundRoute"})
+	h := &BulkRefundRoute{
+		dispatch: set,
+		LMT:      &set.AwareSet,
+		cfg:      *cfg,
+		jobs:     jobs,
+		refunds:  refunds,
+		tasks:    tasks,
+	}
+
+	// the jobs left by the failed instances are completed at the start
+	tasks.Go(func(stop context.Context) {
+		h.interruptStaleJobs()
+	})
+
+	return h
+}
+
+func (h *BulkRefundRoute) Route(groups *common.Groups) {
+	groups.AuthUser.POST(bulkRefundsPath, h.createBulkRefund)
+	groups.AuthUser.GET(bulkRefundsIdPath, h.getBulkRefund)
+	groups.AuthUser.GET(bulkRefundsIdResultPath, h.getBulkRefundResult)
+}
+
+// @summary Submit the bulk refund
+// @desc Submit the list of the refunds as CSV file (the file field of the multipart form or text/csv body)
+// @desc with order_id, amount and reason columns or as JSON. All rows are validated before the processing,
+// @desc the refunds are created in the background.
+// @id bulkRefundsPathCreateBulkRefund
+// @tag Order
+// @accept application/json, multipart/form-data, text/csv
+// @produce application/json
+// @body BulkRefundRequest
+// @success 202 {object} common.BulkRefundJob Returns the job of the bulk refund
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data, the details list the invalid rows
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @failure 503 {object} billingpb.ResponseErrorMessage The server is shutting down, submit the bulk refund again
+// @router /admin/api/v1/refunds/bulk [post]
+func (h *BulkRefundRoute) createBulkRefund(ctx echo.Context) error {
+	rows, err := h.bindRows(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageBulkRefundRowsInvalid)
+	}
+
+	if len(rows) > h.cfg.BulkRefundMaxRows {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageBulkRefundTooManyRows)
+	}
+
+	var invalid []string
+	trans := common.ValidationTranslator(common.RequestLocale(ctx))
+	orders := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		if err := h.dispatch.Validate.Struct(row); err != nil {
+			invalid = append(invalid, fmt.Sprintf("row %d: %s", i+1, bulkRefundRowErrors(err, trans)))
+			continue
+		}
+
+		// one order can't be refunded twice by the same file
+		if first, ok := orders[row.OrderId]; ok {
+			invalid = append(invalid, fmt.Sprintf("row %d: order_id is the same as in row %d", i+1, first+1))
+			continue
+		}
+
+		orders[row.OrderId] = i
+	}
+
+	if len(invalid) > 0 {
+		if len(invalid) > bulkRefundErrorsMax {
+			invalid = append(invalid[:bulkRefundErrorsMax], fmt.Sprintf("and %d more", len(invalid)-bulkRefundErrorsMax))
+		}
+
+		msg := common.ErrorMessageBulkRefundRowsInvalid
+		return echo.NewHTTPError(http.StatusBadRequest, common.NewManagementApiResponseError(msg.Code, msg.Message, strings.Join(invalid, "; ")))
+	}
+
+	user := common.ExtractUserContext(ctx)
+	job := common.NewBulkRefundJob(user.MerchantId, user.Id, rows)
+
+	if err := h.jobs.Create(job); err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	log := common.RequestLogger(ctx, h.L()).WithFields(logger.Fields{"job_id": job.Id})
+	jobCtx, cancel := h.jobContext(ctx)
+	started := h.tasks.Go(func(stop context.Context) {
+		defer cancel()
+		h.process(stop, jobCtx, log, job)
+	})
+
+	if !started {
+		cancel()
+
+		if err := h.jobs.Interrupt(job.Id, bulkRefundInterrupted); err != nil {
+			common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		}
+
+		return echo.NewHTTPError(http.StatusServiceUnavailable, common.ErrorMessageBulkRefundShutdown)
+	}
+
+	return ctx.JSON(http.StatusAccepted, job)
+}
+
+// @summary Get the bulk refund status
+// @desc Get the progress of the bulk refund job
+// @id bulkRefundsIdPathGetBulkRefund
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} common.BulkRefundJob Returns the job of the bulk refund
+// @failure 404 {object} billingpb.ResponseErrorMessage The job not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param job_id path {string} true The unique identifier for the bulk refund job.
+// @router /admin/api/v1/refunds/bulk/{job_id} [get]
+func (h *BulkRefundRoute) getBulkRefund(ctx echo.Context) error {
+	job, err := h.getJob(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, job)
+}
+
+// @summary Download the bulk refund result
+// @desc Download the CSV file with the refund ID or the error for every row of the completed bulk refund
+// @id bulkRefundsIdResultPathGetBulkRefundResult
+// @tag Order
+// @accept application/json
+// @produce text/csv
+// @success 200 {string} Returns the result file
+// @failure 404 {object} billingpb.ResponseErrorMessage The job not found
+// @failure 409 {object} billingpb.ResponseErrorMessage The job is still in progress
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param job_id path {string} true The unique identifier for the bulk refund job.
+// @router /admin/api/v1/refunds/bulk/{job_id}/result [get]
+func (h *BulkRefundRoute) getBulkRefundResult(ctx echo.Context) error {
+	job, err := h.getJob(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if job.Status != common.BulkRefundStatusCompleted {
+		return echo.NewHTTPError(http.StatusConflict, common.ErrorMessageBulkRefundJobInProgress)
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	_ = w.Write([]string{"row", "order_id", "amount", "reason", "refund_id", "error"})
+
+	for i, row := range job.Rows {
+		_ = w.Write([]string{
+			strconv.Itoa(i + 1),
+			row.OrderId,
+			strconv.FormatFloat(row.Amount, 'f', -1, 64),
+			row.Reason,
+			row.RefundId,
+			row.Error,
+		})
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="bulk_refund_%s.csv"`, job.Id))
+	return ctx.Blob(http.StatusOK, bulkRefundMIMECsv, buf.Bytes())
+}
+
+func (h *BulkRefundRoute) getJob(ctx echo.Context) (*common.BulkRefundJob, error) {
+	merchantId, id := common.ExtractUserContext(ctx).MerchantId, ctx.Param(common.RequestParameterJobId)
+	job, err := h.jobs.Get(merchantId, id)
+
+	// the job of the failed instance isn't processed by anyone, so it's completed on the read
+	if err == nil && job != nil && job.Status == common.BulkRefundStatusProcessing && job.CreatedAt.Before(h.staleBefore()) {
+		if err = h.jobs.Interrupt(job.Id, bulkRefundStale); err == nil {
+			job, err = h.jobs.Get(merchantId, id)
+		}
+	}
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if job == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageBulkRefundJobNotFound)
+	}
+
+	return job, nil
+}
+
+// interruptStaleJobs completes the jobs which are still processed after their deadline
+func (h *BulkRefundRoute) interruptStaleJobs() {
+	count, err := h.jobs.InterruptStale(h.staleBefore(), bulkRefundStale)
+
+	if err != nil {
+		h.L().Error("bulk refund stale jobs interruption failed", logger.WithFields(logger.Fields{"err": err.Error()}))
+		return
+	}
+
+	if count > 0 {
+		h.L().Info("bulk refund stale jobs interrupted", logger.WithFields(logger.Fields{"count": count}))
+	}
+}
+
+// staleBefore returns the creation time of the jobs which should be completed by their instance already.
+// The job can't be stale earlier, because it may be processed by the other instance until its deadline.
+func (h *BulkRefundRoute) staleBefore() time.Time {
+	return time.Now().UTC().Add(-h.jobTimeout() - bulkRefundStaleDelay)
+}
+
+func (h *BulkRefundRoute) jobTimeout() time.Duration {
+	if h.cfg.BulkRefundJobTimeout <= 0 {
+		return time.Hour
+	}
+
+	return h.cfg.BulkRefundJobTimeout
+}
+
+// bindRows reads the rows from the CSV file or JSON body
+func (h *BulkRefundRoute) bindRows(ctx echo.Context) ([]*common.BulkRefundRow, error) {
+	contentType := ctx.Request().Header.Get(echo.HeaderContentType)
+
+	switch {
+	case strings.HasPrefix(contentType, echo.MIMEMultipartForm):
+		file, err := ctx.FormFile("file")
+
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageFileNotFound)
+		}
+
+		src, err := file.Open()
+
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageCantReadFile)
+		}
+
+		defer src.Close()
+		return h.readCsvRows(src)
+	case strings.HasPrefix(contentType, bulkRefundMIMECsv):
+		return h.readCsvRows(ctx.Request().Body)
+	}
+
+	req := &BulkRefundRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	for _, row := range req.Rows {
+		if row == nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+		}
+
+		row.RefundId = ""
+		row.Error = ""
+	}
+
+	return req.Rows, nil
+}
+
+// readCsvRows reads the file with the header row, the columns may be in any order
+func (h *BulkRefundRoute) readCsvRows(src io.Reader) ([]*common.BulkRefundRow, error) {
+	r := csv.NewReader(src)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageCantReadFile)
+	}
+
+	columns := make(map[string]int)
+
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range bulkRefundCsvColumns {
+		if _, ok := columns[name]; !ok {
+			msg := common.ErrorMessageBulkRefundRowsInvalid
+			return nil, echo.NewHTTPError(http.StatusBadRequest, common.NewManagementApiResponseError(msg.Code, msg.Message, "column "+name+" not found"))
+		}
+	}
+
+	var rows []*common.BulkRefundRow
+
+	for line := 1; ; line++ {
+		record, err := r.Read()
+
+		if err == io.EOF {
+			return rows, nil
+		}
+
+		if err != nil {
+			msg := common.ErrorMessageBulkRefundRowsInvalid
+			return nil, echo.NewHTTPError(http.StatusBadRequest, common.NewManagementApiResponseError(msg.Code, msg.Message, err.Error()))
+		}
+
+		amount, err := strconv.ParseFloat(strings.TrimSpace(record[columns["amount"]]), 64)
+
+		// Inf and NaN are parsed too, but they pass the amount validation
+		if err == nil && (math.IsInf(amount, 0) || math.IsNaN(amount)) {
+			err = strconv.ErrSyntax
+		}
+
+		if err != nil {
+			msg := common.ErrorMessageBulkRefundRowsInvalid
+			return nil, echo.NewHTTPError(http.StatusBadRequest, common.NewManagementApiResponseError(msg.Code, msg.Message, fmt.Sprintf("row %d: amount is not a number", line)))
+		}
+
+		rows = append(rows, &common.BulkRefundRow{
+			OrderId: strings.TrimSpace(record[columns["order_id"]]),
+			Amount:  amount,
+			Reason:  strings.TrimSpace(record[columns["reason"]]),
+		})
+	}
+}
+
+// jobContext returns the context of the job with the request id and the trace of the submission request,
+// the context is done at the job deadline
+func (h *BulkRefundRoute) jobContext(ctx echo.Context) (context.Context, context.CancelFunc) {
+	reqCtx := ctx.Request().Context()
+	jobCtx := context.Background()
+
+	if md, ok := metadata.FromContext(reqCtx); ok {
+		jobCtx = metadata.NewContext(jobCtx, md)
+	}
+
+	if span := opentracing.SpanFromContext(reqCtx); span != nil {
+		jobCtx = opentracing.ContextWithSpan(jobCtx, span)
+	}
+
+	return context.WithTimeout(jobCtx, h.jobTimeout())
+}
+
+// process creates the refunds of the job by the bounded pool of the workers. When stop or ctx is done
+// the rows in progress are completed and the rest of the rows are failed, so the result lists every row.
+func (h *BulkRefundRoute) process(stop, ctx context.Context, log logger.Logger, job *common.BulkRefundJob) {
+	workers := h.cfg.BulkRefundWorkers
+
+	if workers <= 0 {
+		workers = 1
+	}
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				refundId, rowErr := h.refund(ctx, log, job, job.Rows[index])
+
+				if err := h.jobs.SetRowResult(job.Id, index, refundId, rowErr); err != nil {
+					log.Error("bulk refund row result save failed", logger.WithFields(logger.Fields{"row": index + 1, "err": err.Error()}))
+				}
+			}
+		}()
+	}
+
+	rowErr := ""
+
+	for index := range job.Rows {
+		select {
+		case indexes <- index:
+			continue
+		case <-stop.Done():
+			rowErr = bulkRefundInterrupted
+		case <-ctx.Done():
+			rowErr = bulkRefundTimedOut
+		}
+
+		break
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	if rowErr == "" {
+		return
+	}
+
+	log.Info("bulk refund interrupted", logger.WithFields(logger.Fields{"reason": rowErr}))
+
+	if err := h.jobs.Interrupt(job.Id, rowErr); err != nil {
+		log.Error("bulk refund interruption save failed", logger.WithFields(logger.Fields{"err": err.Error()}))
+	}
+}
+
+// refund creates the refund of the row, the order must belong to the merchant of the job
+// and the refunds of the order are created one by one within its refundable amount
+func (h *BulkRefundRoute) refund(ctx context.Context, log logger.Logger, job *common.BulkRefundJob, row *common.BulkRefundRow) (string, string) {
+	order, err := h.dispatch.Services.Billing.GetOrderPublic(ctx, &billingpb.GetOrderRequest{
+		OrderId:    row.OrderId,
+		MerchantId: job.MerchantId,
+	})
+
+	if err != nil {
+		log.Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"order_id": row.OrderId, "err": err.Error()}))
+		return "", common.ErrorInternal.Message
+	}
+
+	if order.Status != billingpb.ResponseStatusOk {
+		return "", order.Message.GetMessage()
+	}
+
+	unlock, err := lockOrderRefund(ctx, h.dispatch.Services.Billing, h.refunds, h.cfg.LimitMax, order.Item, row.Amount)
+
+	if err != nil {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return "", httpErr.Message.(*billingpb.ResponseErrorMessage).Message
+		}
+
+		log.Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"order_id": row.OrderId, "err": err.Error()}))
+		return "", common.ErrorInternal.Message
+	}
+
+	defer func() {
+		if err := unlock(); err != nil {
+			log.Error("refund lock can't be released", logger.WithFields(logger.Fields{"order_id": row.OrderId, "err": err.Error()}))
+		}
+	}()
+
+	res, err := h.dispatch.Services.Billing.CreateRefund(ctx, &billingpb.CreateRefundRequest{
+		OrderId:   order.Item.Uuid,
+		Amount:    row.Amount,
+		Reason:    row.Reason,
+		CreatorId: job.CreatorId,
+	})
+
+	if err != nil {
+		log.Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"order_id": row.OrderId, "err": err.Error()}))
+		return "", common.ErrorInternal.Message
+	}
+
+	if res.Status != billingpb.ResponseStatusOk {
+		return "", res.Message.GetMessage()
+	}
+
+	return res.Item.GetId(), ""
+}
+
+// bulkRefundRowErrors lists all failed fields of the row
+func bulkRefundRowErrors(err error, trans ut.Translator) string {
+	vErrs, ok := err.(validator.ValidationErrors)
+
+	if !ok {
+		return common.GetValidationError(err).Details
+	}
+
+	fields := common.NewFieldErrors(vErrs, trans)
+	messages := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		messages = append(messages, field.Message)
+	}
+
+	return strings.Join(messages, ", ")
+}
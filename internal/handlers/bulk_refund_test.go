@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/mock"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	billMock "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	bulkRefundOrderOk     = "ace2fc5c-b8c2-4424-96e8-5b631a73b88a"
+	bulkRefundOrderFailed = "bce2fc5c-b8c2-4424-96e8-5b631a73b88a"
+)
+
+type BulkRefundTestSuite struct {
+	suite.Suite
+	router  *BulkRefundRoute
+	caller  *test.EchoReqResCaller
+	jobs    common.BulkRefundStore
+	refunds common.RefundStore
+	tasks   *common.BackgroundTasks
+}
+
+func Test_BulkRefund(t *testing.T) {
+	suite.Run(t, new(BulkRefundTestSuite))
+}
+
+func (suite *BulkRefundTestSuite) SetupTest() {
+	user := &common.AuthUser{
+		Id:         "ffffffffffffffffffffffff",
+		MerchantId: "ffffffffffffffffffffffff",
+	}
+
+	var e error
+	settings := test.DefaultSettings()
+	srv := common.Services{
+		Billing: mock.NewBillingServerOkMock(),
+	}
+	suite.jobs = common.NewMemoryBulkRefundStore(time.Hour)
+	suite.refunds = common.NewMemoryRefundStore()
+	suite.tasks = common.NewBackgroundTasks()
+	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
+		mw.Pre(test.PreAuthUserMiddleware(user))
+		suite.router = NewBulkRefundRoute(set.HandlerSet, suite.jobs, suite.refunds, suite.tasks, set.GlobalConfig)
+		return common.Handlers{
+			suite.router,
+		}
+	})
+
+	if e != nil {
+		panic(e)
+	}
+
+	bill := &billMock.BillingService{}
+	bill.On("GetOrderPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.GetOrderRequest) bool {
+		return req.OrderId == bulkRefundOrderOk && req.MerchantId == user.MerchantId
+	})).
+		Return(&billingpb.GetOrderPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.OrderViewPublic{Uuid: bulkRefundOrderOk, TotalPaymentAmount: 100},
+		}, nil)
+	bill.On("GetOrderPublic", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetOrderPublicResponse{Status: billingpb.ResponseStatusNotFound, Message: &billingpb.ResponseErrorMessage{Message: "order not found"}}, nil)
+	bill.On("ListRefunds", mock2.Anything, mock2.Anything).
+		Return(&billingpb.ListRefundsResponse{
+			Count: 1,
+			Items: []*billingpb.Refund{{Amount: 30, Status: billingpb.RefundStatusCompleted}},
+		}, nil)
+	bill.On("CreateRefund", mock2.Anything, mock2.Anything).
+		Return(&billingpb.CreateRefundResponse{Status: billingpb.ResponseStatusOk, Item: &billingpb.Refund{Id: "refund_id"}}, nil)
+	suite.router.dispatch.Services.Billing = bill
+}
+
+func (suite *BulkRefundTestSuite) TearDownTest() {}
+
+func (suite *BulkRefundTestSuite) waitJob(id string) *common.BulkRefundJob {
+	for i := 0; i < 100; i++ {
+		job, err := suite.jobs.Get("ffffffffffffffffffffffff", id)
+		assert.NoError(suite.T(), err)
+
+		if job.Status == common.BulkRefundStatusCompleted {
+			return job
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	suite.FailNow("bulk refund job isn't completed")
+	return nil
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_Json_Ok() {
+	body := `{"rows": [{"order_id": "` + bulkRefundOrderOk + `", "amount": 10, "reason": "launch issue"},` +
+		`{"order_id": "` + bulkRefundOrderFailed + `", "amount": 5.5, "reason": "launch issue"}]}`
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Path(common.AuthUserGroupPath + bulkRefundsPath).
+		Init(test.ReqInitJSON()).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusAccepted, res.Code)
+
+	job := &common.BulkRefundJob{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), job))
+	assert.Equal(suite.T(), 2, job.Total)
+
+	job = suite.waitJob(job.Id)
+	assert.Equal(suite.T(), 1, job.Succeeded)
+	assert.Equal(suite.T(), 1, job.Failed)
+
+	res, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterJobId, job.Id).
+		Path(common.AuthUserGroupPath + bulkRefundsIdResultPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+	assert.Len(suite.T(), lines, 3)
+	assert.Equal(suite.T(), "1,"+bulkRefundOrderOk+",10,launch issue,refund_id,", lines[1])
+	assert.Equal(suite.T(), "2,"+bulkRefundOrderFailed+",5.5,launch issue,,order not found", lines[2])
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_Csv_Ok() {
+	body := "reason,order_id,amount\nlaunch issue," + bulkRefundOrderOk + ",10\n"
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Path(common.AuthUserGroupPath + bulkRefundsPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, bulkRefundMIMECsv)
+		}).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusAccepted, res.Code)
+
+	job := &common.BulkRefundJob{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), job))
+
+	job = suite.waitJob(job.Id)
+	assert.Equal(suite.T(), 1, job.Succeeded)
+	assert.Equal(suite.T(), "refund_id", job.Rows[0].RefundId)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_InvalidRows() {
+	body := "order_id,amount,reason\n" + bulkRefundOrderOk + ",10,launch issue\nnot_uuid,-1,launch issue\n"
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Path(common.AuthUserGroupPath + bulkRefundsPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, bulkRefundMIMECsv)
+		}).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+
+	msg, ok := httpErr.Message.(*billingpb.ResponseErrorMessage)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), common.ErrorMessageBulkRefundRowsInvalid.Code, msg.Code)
+	assert.True(suite.T(), strings.HasPrefix(msg.Details, "row 2: "))
+	assert.Contains(suite.T(), msg.Details, "order_id")
+	assert.Contains(suite.T(), msg.Details, "amount")
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_DuplicateOrder_Error() {
+	body := "order_id,amount,reason\n" + bulkRefundOrderOk + ",10,launch issue\n" + bulkRefundOrderOk + ",5,launch issue\n"
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Path(common.AuthUserGroupPath + bulkRefundsPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, bulkRefundMIMECsv)
+		}).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+
+	msg, ok := httpErr.Message.(*billingpb.ResponseErrorMessage)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), common.ErrorMessageBulkRefundRowsInvalid.Code, msg.Code)
+	assert.Equal(suite.T(), "row 2: order_id is the same as in row 1", msg.Details)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_Result_InProgress() {
+	job := common.NewBulkRefundJob("ffffffffffffffffffffffff", "ffffffffffffffffffffffff", []*common.BulkRefundRow{
+		{OrderId: bulkRefundOrderOk, Amount: 10, Reason: "launch issue"},
+	})
+	assert.NoError(suite.T(), suite.jobs.Create(job))
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterJobId, job.Id).
+		Path(common.AuthUserGroupPath + bulkRefundsIdResultPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusConflict, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageBulkRefundJobInProgress, httpErr.Message)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_Get_NotFound() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterJobId, "ffffffffffffffffffffffff").
+		Path(common.AuthUserGroupPath + bulkRefundsIdPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusNotFound, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageBulkRefundJobNotFound, httpErr.Message)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_Interrupted() {
+	job := common.NewBulkRefundJob("ffffffffffffffffffffffff", "ffffffffffffffffffffffff", []*common.BulkRefundRow{
+		{OrderId: bulkRefundOrderOk, Amount: 10, Reason: "launch issue"},
+		{OrderId: bulkRefundOrderOk, Amount: 5, Reason: "launch issue"},
+	})
+	assert.NoError(suite.T(), suite.jobs.Create(job))
+
+	stop, cancel := context.WithCancel(context.Background())
+	cancel()
+	suite.router.process(stop, context.Background(), suite.router.L(), job)
+
+	job = suite.waitJob(job.Id)
+	assert.Equal(suite.T(), 2, job.Processed)
+
+	for _, row := range job.Rows {
+		if row.RefundId == "" {
+			assert.Equal(suite.T(), bulkRefundInterrupted, row.Error)
+		}
+	}
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_Shutdown_Error() {
+	assert.NoError(suite.T(), suite.tasks.Shutdown(context.Background()))
+
+	body := `{"rows": [{"order_id": "` + bulkRefundOrderOk + `", "amount": 10, "reason": "launch issue"}]}`
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Path(common.AuthUserGroupPath + bulkRefundsPath).
+		Init(test.ReqInitJSON()).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageBulkRefundShutdown, httpErr.Message)
+}
+
+// runJob processes the job of the rows at once
+func (suite *BulkRefundTestSuite) runJob(rows ...*common.BulkRefundRow) *common.BulkRefundJob {
+	job := common.NewBulkRefundJob("ffffffffffffffffffffffff", "ffffffffffffffffffffffff", rows)
+	assert.NoError(suite.T(), suite.jobs.Create(job))
+
+	suite.router.process(context.Background(), context.Background(), suite.router.L(), job)
+	return suite.waitJob(job.Id)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_AmountExceeded() {
+	job := suite.runJob(&common.BulkRefundRow{OrderId: bulkRefundOrderOk, Amount: 70.01, Reason: "launch issue"})
+
+	assert.Equal(suite.T(), 1, job.Failed)
+	assert.Equal(suite.T(), common.ErrorMessageRefundAmountExceeded.Message, job.Rows[0].Error)
+	suite.router.dispatch.Services.Billing.(*billMock.BillingService).AssertNotCalled(suite.T(), "CreateRefund", mock2.Anything, mock2.Anything)
+
+	// the order is unlocked after the row
+	locked, err := suite.refunds.Lock(bulkRefundOrderOk, time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), locked)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_OrderLocked() {
+	locked, err := suite.refunds.Lock(bulkRefundOrderOk, time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), locked)
+
+	job := suite.runJob(&common.BulkRefundRow{OrderId: bulkRefundOrderOk, Amount: 10, Reason: "launch issue"})
+
+	assert.Equal(suite.T(), 1, job.Failed)
+	assert.Equal(suite.T(), common.ErrorMessageRefundInProgress.Message, job.Rows[0].Error)
+	suite.router.dispatch.Services.Billing.(*billMock.BillingService).AssertNotCalled(suite.T(), "CreateRefund", mock2.Anything, mock2.Anything)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_InfiniteAmount_Error() {
+	for _, amount := range []string{"Inf", "+Inf", "-Inf", "NaN"} {
+		body := "order_id,amount,reason\n" + bulkRefundOrderOk + "," + amount + ",launch issue\n"
+
+		_, err := suite.caller.Builder().
+			Method(http.MethodPost).
+			Path(common.AuthUserGroupPath + bulkRefundsPath).
+			Init(func(request *http.Request, middleware test.Middleware) {
+				request.Header.Set(echo.HeaderContentType, bulkRefundMIMECsv)
+			}).
+			BodyString(body).
+			Exec(suite.T())
+
+		assert.Error(suite.T(), err)
+		httpErr, ok := err.(*echo.HTTPError)
+		assert.True(suite.T(), ok)
+		assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+
+		msg, ok := httpErr.Message.(*billingpb.ResponseErrorMessage)
+		assert.True(suite.T(), ok)
+		assert.Equal(suite.T(), "row 1: amount is not a number", msg.Details, amount)
+	}
+}
+
+// staleJob creates the job which is left in processing by the failed instance
+func (suite *BulkRefundTestSuite) staleJob() *common.BulkRefundJob {
+	job := common.NewBulkRefundJob("ffffffffffffffffffffffff", "ffffffffffffffffffffffff", []*common.BulkRefundRow{
+		{OrderId: bulkRefundOrderOk, Amount: 10, Reason: "launch issue", RefundId: "refund_id"},
+		{OrderId: bulkRefundOrderFailed, Amount: 5, Reason: "launch issue"},
+	})
+	job.CreatedAt = time.Now().UTC().Add(-suite.router.jobTimeout() - bulkRefundStaleDelay - time.Minute)
+	job.Processed, job.Succeeded = 1, 1
+	assert.NoError(suite.T(), suite.jobs.Create(job))
+
+	return job
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_StaleJob_InterruptedOnRead() {
+	job := suite.staleJob()
+	fresh := common.NewBulkRefundJob("ffffffffffffffffffffffff", "ffffffffffffffffffffffff", []*common.BulkRefundRow{
+		{OrderId: bulkRefundOrderOk, Amount: 10, Reason: "launch issue"},
+	})
+	assert.NoError(suite.T(), suite.jobs.Create(fresh))
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterJobId, job.Id).
+		Path(common.AuthUserGroupPath + bulkRefundsIdPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	job, err = suite.jobs.Get("ffffffffffffffffffffffff", job.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), common.BulkRefundStatusCompleted, job.Status)
+	assert.Equal(suite.T(), "refund_id", job.Rows[0].RefundId)
+	assert.Equal(suite.T(), bulkRefundStale, job.Rows[1].Error)
+
+	// the job of the other instance may be still processed
+	fresh, err = suite.jobs.Get("ffffffffffffffffffffffff", fresh.Id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), common.BulkRefundStatusProcessing, fresh.Status)
+}
+
+func (suite *BulkRefundTestSuite) TestBulkRefund_StaleJob_InterruptedAtStart() {
+	job := suite.staleJob()
+	suite.router.interruptStaleJobs()
+
+	job = suite.waitJob(job.Id)
+	assert.Equal(suite.T(), 2, job.Processed)
+	assert.Equal(suite.T(), 1, job.Failed)
+	assert.Equal(suite.T(), bulkRefundStale, job.Rows[1].Error)
+}
@@ -50,7 +50,7 @@ func (h *CardPayWebHook) paymentCallback(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(st); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	req := &billingpb.PaymentNotifyRequest{
@@ -62,7 +62,7 @@ func (h *CardPayWebHook) paymentCallback(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.PaymentCallbackProcess(ctx.Request().Context(), req)
 
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorUnknown)
 	}
 
@@ -99,7 +99,7 @@ func (h *CardPayWebHook) refundCallback(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(st)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	req := &billingpb.CallbackRequest{
@@ -111,7 +111,7 @@ func (h *CardPayWebHook) refundCallback(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ProcessRefundCallback(ctx.Request().Context(), req)
 
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -74,7 +74,7 @@ func (h *CountryApiV1) getById(ctx echo.Context) error {
 	}
 	err := h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetCountry(ctx.Request().Context(), req)
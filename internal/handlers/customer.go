@@ -51,13 +51,13 @@ func (h *CustomerRoute) getCustomerDetails(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetCustomerInfo(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetCustomerInfo", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetCustomerInfo", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -79,13 +79,13 @@ func (h *CustomerRoute) getCustomers(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetCustomerList(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetCustomerList", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetCustomerList", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -105,13 +105,13 @@ func (h *CustomerRoute) deleteCustomerCardAdmin(ctx echo.Context) error {
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeleteCustomerCard(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeleteCustomerCard", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "DeleteCustomerCard", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
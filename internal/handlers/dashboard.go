@@ -58,13 +58,13 @@ func (h *DashboardRoute) getCustomers(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetDashboardCustomersReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetDashboardCustomersReport", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetDashboardCustomersReport", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -97,13 +97,13 @@ func (h *DashboardRoute) getMainReports(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetDashboardMainReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetDashboardMainReport", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetDashboardMainReport", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -136,13 +136,13 @@ func (h *DashboardRoute) getRevenueDynamicsReport(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetDashboardRevenueDynamicsReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetDashboardMainReport", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetDashboardMainReport", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -175,13 +175,13 @@ func (h *DashboardRoute) getBaseReports(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetDashboardBaseReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetDashboardMainReport", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetDashboardMainReport", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -50,12 +50,12 @@ func (h *KeyRoute) getKeyInfo(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetKeyByID(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
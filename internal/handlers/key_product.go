@@ -81,12 +81,12 @@ func (h *KeyProductRoute) unpublishKeyProduct(ctx echo.Context) error {
 	req.KeyProductId = ctx.Param("key_product_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.UnPublishKeyProduct(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -119,12 +119,12 @@ func (h *KeyProductRoute) publishKeyProduct(ctx echo.Context) error {
 	req.KeyProductId = ctx.Param("key_product_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.PublishKeyProduct(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -176,12 +176,12 @@ func (h *KeyProductRoute) getPlatformsList(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPlatforms(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -213,12 +213,12 @@ func (h *KeyProductRoute) deleteKeyProductById(ctx echo.Context) error {
 	req.Id = ctx.Param("key_product_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeleteKeyProduct(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -251,12 +251,12 @@ func (h *KeyProductRoute) changeKeyProduct(ctx echo.Context) error {
 	req.Id = ctx.Param("key_product_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.CreateOrUpdateKeyProduct(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -301,12 +301,12 @@ func (h *KeyProductRoute) getKeyProductById(ctx echo.Context) error {
 	req.Id = ctx.Param("key_product_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetKeyProduct(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -335,14 +335,14 @@ func (h *KeyProductRoute) createKeyProduct(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
-	h.L().Info("createKeyProduct", logger.PairArgs("req", req))
+	common.RequestLogger(ctx, h.L()).Info("createKeyProduct", logger.PairArgs("req", req))
 
 	res, err := h.dispatch.Services.Billing.CreateOrUpdateKeyProduct(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -384,12 +384,12 @@ func (h *KeyProductRoute) getKeyProductList(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetKeyProducts(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -425,13 +425,13 @@ func (h *KeyProductRoute) getKeyProduct(ctx echo.Context) error {
 	req.KeyProductId = ctx.Param("key_product_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	if req.Currency == "" && req.Country == "" {
 		res, err := h.dispatch.Services.Geo.GetIpData(ctx.Request().Context(), &proto.GeoIpDataRequest{IP: ctx.RealIP()})
 		if err != nil {
-			h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+			common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		} else {
 			req.Country = res.Country.IsoCode
 		}
@@ -443,7 +443,7 @@ func (h *KeyProductRoute) getKeyProduct(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Billing.GetKeyProductInfo(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -476,13 +476,13 @@ func (h *KeyProductRoute) uploadKeys(ctx echo.Context) error {
 
 	file, err := ctx.FormFile("file")
 	if err != nil {
-		h.L().Error(common.ErrorMessageFileNotFound.String(), logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.ErrorMessageFileNotFound.String(), logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageFileNotFound)
 	}
 
 	src, err := file.Open()
 	if err != nil {
-		h.L().Error(common.ErrorMessageCantReadFile.String(), logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.ErrorMessageCantReadFile.String(), logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageCantReadFile)
 	}
 	defer src.Close()
@@ -490,7 +490,7 @@ func (h *KeyProductRoute) uploadKeys(ctx echo.Context) error {
 	req.File, err = ioutil.ReadAll(src)
 
 	if err != nil {
-		h.L().Error(common.ErrorMessageCantReadFile.String(), logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.ErrorMessageCantReadFile.String(), logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageCantReadFile)
 	}
 
@@ -499,7 +499,7 @@ func (h *KeyProductRoute) uploadKeys(ctx echo.Context) error {
 
 	keyProductRes, err := h.dispatch.Services.Billing.GetKeyProduct(ctx.Request().Context(), &billingpb.RequestKeyProductMerchant{Id: req.KeyProductId, MerchantId: req.MerchantId})
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -508,12 +508,12 @@ func (h *KeyProductRoute) uploadKeys(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.UploadKeysFile(ctx.Request().Context(), req, client.WithRequestTimeout(time.Minute*10))
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -561,13 +561,13 @@ func (h *KeyProductRoute) getCountOfKeys(ctx echo.Context) error {
 	req.PlatformId = ctx.Param("platform_id")
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetAvailableKeysCount(ctx.Request().Context(), req)
 
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
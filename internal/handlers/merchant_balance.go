@@ -68,7 +68,7 @@ func (h *BalanceRoute) getBalance(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantBalance(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantBalance", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantBalance", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
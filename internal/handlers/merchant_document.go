@@ -131,7 +131,7 @@ func (h *MerchantDocumentRoute) uploadDocument(ctx echo.Context) error {
 	file, err := h.validateUploadedFile(ctx, document)
 
 	if err != nil {
-		h.L().Error(
+		common.RequestLogger(ctx, h.L()).Error(
 			"failed validate merchant uploaded document",
 			logger.PairArgs("err", err.Error()),
 		)
@@ -139,7 +139,7 @@ func (h *MerchantDocumentRoute) uploadDocument(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(document); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	in := &awsWrapper.UploadInput{
@@ -150,7 +150,7 @@ func (h *MerchantDocumentRoute) uploadDocument(ctx echo.Context) error {
 	_, err = h.awsManager.Upload(ctxUpload, in)
 
 	if err != nil {
-		h.L().Error(
+		common.RequestLogger(ctx, h.L()).Error(
 			"unable to upload merchant document into S3",
 			logger.PairArgs("err", err.Error()),
 		)
@@ -160,7 +160,7 @@ func (h *MerchantDocumentRoute) uploadDocument(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.AddMerchantDocument(ctx.Request().Context(), document)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(document, err, billingpb.ServiceName, "AddMerchantDocument")
+		return h.dispatch.SrvCallHandler(ctx, document, err, billingpb.ServiceName, "AddMerchantDocument")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -175,7 +175,7 @@ func (h *MerchantDocumentRoute) validateUploadedFile(ctx echo.Context, document
 	file, handler, err := ctx.Request().FormFile("file")
 
 	if err != nil {
-		h.L().Error(
+		common.RequestLogger(ctx, h.L()).Error(
 			"unable to find file in merchant document upload request",
 			logger.PairArgs("err", err.Error()),
 		)
@@ -239,7 +239,7 @@ func (h *MerchantDocumentRoute) listDocuments(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantDocuments(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantDocuments", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantDocuments", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -284,7 +284,7 @@ func (h *MerchantDocumentRoute) getDocument(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantDocument(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantDocument", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantDocument", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -329,7 +329,7 @@ func (h *MerchantDocumentRoute) downloadDocument(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantDocument(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantDocument", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantDocument", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -341,7 +341,7 @@ func (h *MerchantDocumentRoute) downloadDocument(ctx echo.Context) error {
 	_, err = h.awsManager.Download(ctx.Request().Context(), filePath, &awsWrapper.DownloadInput{FileName: res.Item.FilePath})
 
 	if err != nil {
-		h.L().Error(
+		common.RequestLogger(ctx, h.L()).Error(
 			"unable to download the merchant document",
 			logger.PairArgs("err", err),
 			logger.PairArgs("document", res.Item),
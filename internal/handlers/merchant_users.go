@@ -18,17 +18,21 @@ const (
 )
 
 type MerchantUsersRoute struct {
-	dispatch common.HandlerSet
-	cfg      common.Config
+	dispatch  common.HandlerSet
+	cfg       common.Config
+	authCache common.AuthCache
+	sessions  common.SessionStore
 	provider.LMT
 }
 
-func NewMerchantUsersRoute(set common.HandlerSet, cfg *common.Config) *MerchantUsersRoute {
+func NewMerchantUsersRoute(set common.HandlerSet, authCache common.AuthCache, sessions common.SessionStore, cfg *common.Config) *MerchantUsersRoute {
 	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "MerchantUsersRoute"})
 	return &MerchantUsersRoute{
-		dispatch: set,
-		LMT:      &set.AwareSet,
-		cfg:      *cfg,
+		dispatch:  set,
+		LMT:       &set.AwareSet,
+		cfg:       *cfg,
+		authCache: authCache,
+		sessions:  sessions,
 	}
 }
 
@@ -63,13 +67,19 @@ func (h *MerchantUsersRoute) changeRole(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ChangeRoleForMerchantUser(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeRoleForMerchantUser")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeRoleForMerchantUser")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
+	// the roles of the merchant users are cached by the auth middleware
+	h.authCache.Invalidate(common.AuthCacheMerchantTag(common.ExtractUserContext(ctx).MerchantId))
+
+	// the user signs in again to get the permissions of the new role
+	revokeUserSessions(ctx, h.L(), h.sessions, h.roleUserId(ctx, req.MerchantId, req.RoleId))
+
 	return ctx.NoContent(http.StatusOK)
 }
 
@@ -93,7 +103,7 @@ func (h *MerchantUsersRoute) getMerchantUsers(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantUsers(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetMerchantUsers")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetMerchantUsers")
 	}
 
 	if res.Status != http.StatusOK {
@@ -124,7 +134,7 @@ func (h *MerchantUsersRoute) sendInvite(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.InviteUserMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "InviteUserMerchant")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "InviteUserMerchant")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -155,7 +165,7 @@ func (h *MerchantUsersRoute) resendInvite(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ResendInviteMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ResendInviteMerchant")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ResendInviteMerchant")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -180,7 +190,7 @@ func (h *MerchantUsersRoute) listRoles(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetRoleList(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetRoleList")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetRoleList")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -204,16 +214,21 @@ func (h *MerchantUsersRoute) deleteUser(ctx echo.Context) error {
 		return err
 	}
 
+	userId := h.roleUserId(ctx, req.MerchantId, req.RoleId)
 	res, err := h.dispatch.Services.Billing.DeleteMerchantUser(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "DeleteMerchantUser")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "DeleteMerchantUser")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
+	// the roles of the merchant users are cached by the auth middleware
+	h.authCache.Invalidate(common.AuthCacheMerchantTag(common.ExtractUserContext(ctx).MerchantId))
+	revokeUserSessions(ctx, h.L(), h.sessions, userId)
+
 	return ctx.JSON(http.StatusOK, res)
 }
 
@@ -238,7 +253,7 @@ func (h *MerchantUsersRoute) getUser(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantUserRole(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetMerchantUserRole")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetMerchantUserRole")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -247,3 +262,20 @@ func (h *MerchantUsersRoute) getUser(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, res)
 }
+
+// roleUserId returns the user of the role, it's empty if the role is unknown or the invitation isn't accepted yet
+func (h *MerchantUsersRoute) roleUserId(ctx echo.Context, merchantId, roleId string) string {
+	req := &billingpb.MerchantRoleRequest{MerchantId: merchantId, RoleId: roleId}
+	res, err := h.dispatch.Services.Billing.GetMerchantUserRole(ctx.Request().Context(), req)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return ""
+	}
+
+	if res.Status != billingpb.ResponseStatusOk || res.UserRole == nil {
+		return ""
+	}
+
+	return res.UserRole.UserId
+}
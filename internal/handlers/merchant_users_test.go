@@ -14,12 +14,14 @@ import (
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type MerchantUsersTestSuite struct {
 	suite.Suite
-	router *MerchantUsersRoute
-	caller *test.EchoReqResCaller
+	router   *MerchantUsersRoute
+	caller   *test.EchoReqResCaller
+	sessions common.SessionStore
 }
 
 func Test_MerchantUsers(t *testing.T) {
@@ -39,7 +41,8 @@ func (suite *MerchantUsersTestSuite) SetupTest() {
 	}
 	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
 		mw.Pre(test.PreAuthUserMiddleware(user))
-		suite.router = NewMerchantUsersRoute(set.HandlerSet, set.GlobalConfig)
+		suite.sessions = common.NewMemorySessionStore(time.Hour)
+		suite.router = NewMerchantUsersRoute(set.HandlerSet, common.NewMemoryAuthCache(100), suite.sessions, set.GlobalConfig)
 		return common.Handlers{
 			suite.router,
 		}
@@ -162,10 +165,18 @@ func (suite *MerchantUsersTestSuite) TestMerchantChangeRole_EmptyBodyError() {
 func (suite *MerchantUsersTestSuite) TestMerchantChangeRole_Ok() {
 	shouldBe := require.New(suite.T())
 
+	session := common.NewSession("some_access_token", "eeeeeeeeeeeeeeeeeeeeeeee", "", "")
+	_, err := suite.sessions.Touch(session)
+	shouldBe.NoError(err)
+
 	billingService := suite.router.dispatch.Services.Billing.(*mocks.BillingService)
 	billingService.On("ChangeRoleForMerchantUser", mock2.Anything, mock2.Anything).Return(&billingpb.EmptyResponseWithStatus{
 		Status: 200,
 	}, nil)
+	billingService.On("GetMerchantUserRole", mock2.Anything, mock2.Anything).Return(&billingpb.UserRoleResponse{
+		Status:   200,
+		UserRole: &billingpb.UserRole{UserId: "eeeeeeeeeeeeeeeeeeeeeeee"},
+	}, nil)
 
 	res, err := suite.caller.Builder().
 		Method(http.MethodPut).
@@ -178,4 +189,8 @@ func (suite *MerchantUsersTestSuite) TestMerchantChangeRole_Ok() {
 	shouldBe.NoError(err)
 	shouldBe.Equal(http.StatusOK, res.Code)
 	shouldBe.Empty(res.Body.String())
+
+	revoked, err := suite.sessions.Touch(session)
+	shouldBe.NoError(err)
+	shouldBe.True(revoked)
 }
@@ -100,7 +100,7 @@ func (h *OnboardingRoute) Route(groups *common.Groups) {
 	groups.SystemUser.PUT(merchantsIdPath, h.setMerchantOnboardingData)
 	groups.AuthUser.GET(merchantsStatusCompanyPath, h.getMerchantStatus)
 
-	groups.SystemUser.PUT(merchantsIdChangeStatusCompanyPath, h.changeMerchantStatus)
+	groups.SystemUser.PUT(merchantsIdChangeStatusCompanyPath, h.changeMerchantStatus, common.AuditSnapshot(h.merchantStatusSnapshot))
 	groups.AuthUser.PATCH(merchantsPath, h.changeAgreement)
 
 	groups.AuthUser.GET(merchantsAgreementPath, h.getMerchantAgreementData)
@@ -116,7 +116,7 @@ func (h *OnboardingRoute) Route(groups *common.Groups) {
 
 	groups.AuthUser.GET(merchantsTariffsPath, h.getTariffRates)
 	groups.AuthUser.POST(merchantsTariffsPath, h.setTariffRates)
-	groups.SystemUser.POST(merchantsIdTariffsPath, h.setTariffRates)
+	groups.SystemUser.POST(merchantsIdTariffsPath, h.setTariffRates, common.AuditSnapshot(h.merchantTariffSnapshot))
 
 	groups.AuthUser.PUT(merchantsIdManualPayoutEnablePath, h.enableMerchantManualPayout)
 	groups.AuthUser.PUT(merchantsIdManualPayoutDisablePath, h.disableMerchantManualPayout)
@@ -125,6 +125,43 @@ func (h *OnboardingRoute) Route(groups *common.Groups) {
 	groups.SystemUser.POST(merchantsIdAcceptPath, h.acceptMerchant)
 }
 
+// merchantStatusSnapshot reads the merchant status before the change for the audit record
+func (h *OnboardingRoute) merchantStatusSnapshot(ctx echo.Context) (interface{}, error) {
+	merchant, err := h.auditMerchant(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"status": merchant.Status}, nil
+}
+
+// merchantTariffSnapshot reads the merchant tariff before the change for the audit record
+func (h *OnboardingRoute) merchantTariffSnapshot(ctx echo.Context) (interface{}, error) {
+	merchant, err := h.auditMerchant(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"tariff": merchant.Tariff}, nil
+}
+
+func (h *OnboardingRoute) auditMerchant(ctx echo.Context) (*billingpb.Merchant, error) {
+	req := &billingpb.GetMerchantByRequest{MerchantId: ctx.Param(common.RequestParameterMerchantId)}
+	res, err := h.dispatch.Services.Billing.GetMerchantBy(ctx.Request().Context(), req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != billingpb.ResponseStatusOk {
+		return nil, res.Message
+	}
+
+	return res.Item, nil
+}
+
 // @summary Get the merchant user
 // @desc Get the merchant user using the user ID
 // @id merchantsIdPathGetMerchant
@@ -144,7 +181,7 @@ func (h *OnboardingRoute) getMerchant(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantBy(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantBy", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantBy", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -165,7 +202,7 @@ func (h *OnboardingRoute) getMerchantByUser(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantBy(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantBy", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantBy", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -213,13 +250,13 @@ func (h *OnboardingRoute) listMerchantsForAgreement(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ListMerchantsForAgreement(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ListMerchantsForAgreement", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ListMerchantsForAgreement", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -265,13 +302,13 @@ func (h *OnboardingRoute) listMerchants(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ListMerchants(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ListMerchants", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ListMerchants", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -300,14 +337,14 @@ func (h *OnboardingRoute) changeMerchantStatus(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	req.UserId = authUser.Id
 	res, err := h.dispatch.Services.Billing.ChangeMerchantStatus(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ChangeMerchantStatus", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ChangeMerchantStatus", req)
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorUnknown)
 	}
 
@@ -340,14 +377,14 @@ func (h *OnboardingRoute) createNotification(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	req.UserId = authUser.Id
 	res, err := h.dispatch.Services.Billing.CreateNotification(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "CreateNotification", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "CreateNotification", req)
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorUnknown)
 	}
 
@@ -380,7 +417,7 @@ func (h *OnboardingRoute) getNotification(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetNotification(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetNotification")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetNotification")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -434,13 +471,13 @@ func (h *OnboardingRoute) listNotifications(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ListNotifications(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ListNotifications", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ListNotifications", req)
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorUnknown)
 	}
 
@@ -469,7 +506,7 @@ func (h *OnboardingRoute) markAsReadNotification(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.MarkNotificationAsRead(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "MarkNotificationAsRead")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "MarkNotificationAsRead")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -498,7 +535,7 @@ func (h *OnboardingRoute) changeAgreement(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ChangeMerchantData(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeMerchantData")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeMerchantData")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -536,13 +573,13 @@ func (h *OnboardingRoute) getAgreementDocument(ctx echo.Context) error {
 	req := &billingpb.GetMerchantByRequest{}
 
 	if err := h.dispatch.BindAndValidate(req, ctx); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+		return err
 	}
 
 	res, err := h.dispatch.Services.Billing.GetMerchantBy(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetMerchantBy")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetMerchantBy")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -557,7 +594,7 @@ func (h *OnboardingRoute) getAgreementDocument(ctx echo.Context) error {
 	_, err = h.awsManager.Download(ctx.Request().Context(), filePath, &awsWrapper.DownloadInput{FileName: res.Item.S3AgreementName})
 
 	if err != nil {
-		h.L().Error("AWS api call to download file failed", logger.PairArgs("err", err.Error(), "file_name", res.Item.S3AgreementName))
+		common.RequestLogger(ctx, h.L()).Error("AWS api call to download file failed", logger.PairArgs("err", err.Error(), "file_name", res.Item.S3AgreementName))
 
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorAgreementFileNotExist)
 	}
@@ -652,13 +689,13 @@ func (h *OnboardingRoute) setMerchantCompany(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ChangeMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ChangeMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ChangeMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -715,13 +752,13 @@ func (h *OnboardingRoute) setMerchantContacts(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ChangeMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ChangeMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ChangeMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -778,13 +815,13 @@ func (h *OnboardingRoute) setMerchantBanking(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ChangeMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ChangeMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ChangeMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -825,13 +862,13 @@ func (h *OnboardingRoute) setMerchantOnboardingData(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(in)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ChangeMerchant(ctx.Request().Context(), in)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ChangeMerchant", in)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ChangeMerchant", in)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -863,7 +900,7 @@ func (h *OnboardingRoute) getMerchantStatus(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetMerchantOnboardingCompleteData(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetMerchantOnboardingCompleteData")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetMerchantOnboardingCompleteData")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -900,13 +937,13 @@ func (h *OnboardingRoute) getTariffRates(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetMerchantTariffRates(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMerchantTariffRates", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMerchantTariffRates", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -954,7 +991,7 @@ func (h *OnboardingRoute) setTariffRates(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetMerchantTariffRates(
@@ -964,7 +1001,7 @@ func (h *OnboardingRoute) setTariffRates(ctx echo.Context) error {
 	)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetMerchantTariffRates", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetMerchantTariffRates", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -1016,7 +1053,7 @@ func (h *OnboardingRoute) getAgreementData(ctx echo.Context, signerType int32) e
 	res, err := h.dispatch.Services.Billing.GetMerchantBy(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetMerchantBy")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetMerchantBy")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -1031,7 +1068,7 @@ func (h *OnboardingRoute) getAgreementData(ctx echo.Context, signerType int32) e
 	_, err = h.awsManager.Download(ctx.Request().Context(), filePath, &awsWrapper.DownloadInput{FileName: res.Item.S3AgreementName})
 
 	if err != nil {
-		h.L().Error("AWS api call to download file failed", logger.PairArgs("err", err.Error(), "file_name", res.Item.S3AgreementName))
+		common.RequestLogger(ctx, h.L()).Error("AWS api call to download file failed", logger.PairArgs("err", err.Error(), "file_name", res.Item.S3AgreementName))
 
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
@@ -1039,7 +1076,7 @@ func (h *OnboardingRoute) getAgreementData(ctx echo.Context, signerType int32) e
 	fData, err := h.getAgreementStructure(ctx, req.MerchantId, agreementExtension, agreementContentType, filePath, signerType)
 
 	if err != nil {
-		h.L().Error("Get agreement structure failed", logger.PairArgs("err", err.Error(), "merchant_id", req.MerchantId))
+		common.RequestLogger(ctx, h.L()).Error("Get agreement structure failed", logger.PairArgs("err", err.Error(), "merchant_id", req.MerchantId))
 
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
@@ -1087,7 +1124,7 @@ func (h *OnboardingRoute) changeMerchantManualPayout(ctx echo.Context, enableMan
 	res, err := h.dispatch.Services.Billing.ChangeMerchantManualPayouts(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeMerchantManualPayouts")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeMerchantManualPayouts")
 	}
 
 	if res.Status != http.StatusOK {
@@ -1122,13 +1159,13 @@ func (h *OnboardingRoute) setOperatingCompany(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetMerchantOperatingCompany(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetMerchantOperatingCompany", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetMerchantOperatingCompany", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -1164,13 +1201,13 @@ func (h *OnboardingRoute) acceptMerchant(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetMerchantAcceptedStatus(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetMerchantAcceptedStatus", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetMerchantAcceptedStatus", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -383,7 +383,12 @@ func (suite *OnboardingTestSuite) TestOnboarding_GetNotification_EmptyId_Error()
 	httpErr, ok := err.(*echo.HTTPError)
 	assert.True(suite.T(), ok)
 	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
-	assert.Equal(suite.T(), common.ErrorValidationFailed, httpErr.Message)
+
+	msg, ok := httpErr.Message.(*billingpb.ResponseErrorMessage)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), common.ErrorValidationFailed.Code, msg.Code)
+	assert.Equal(suite.T(), common.ErrorValidationFailed.Message, msg.Message)
+	assert.Empty(suite.T(), common.ErrorValidationFailed.Details)
 }
 
 func (suite *OnboardingTestSuite) TestOnboarding_GetNotification_BillingServerUnavailable_Error() {
@@ -514,7 +519,12 @@ func (suite *OnboardingTestSuite) TestOnboarding_MarkAsReadNotification_EmptyId_
 	httpErr, ok := err.(*echo.HTTPError)
 	assert.True(suite.T(), ok)
 	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
-	assert.Equal(suite.T(), common.ErrorValidationFailed, httpErr.Message)
+
+	msg, ok := httpErr.Message.(*billingpb.ResponseErrorMessage)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), common.ErrorValidationFailed.Code, msg.Code)
+	assert.Equal(suite.T(), common.ErrorValidationFailed.Message, msg.Message)
+	assert.Empty(suite.T(), common.ErrorValidationFailed.Details)
 }
 
 func (suite *OnboardingTestSuite) TestOnboarding_MarkAsReadNotification_BillingServer_Error() {
@@ -1926,6 +1936,8 @@ func (suite *OnboardingTestSuite) TestOnboarding_SetTariffRates_Ok() {
 	body := `{"home_region": "russia_and_cis", "merchant_operations_type": "low-risk"}`
 
 	billingService := &billMock.BillingService{}
+	billingService.On("GetMerchantBy", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetMerchantResponse{Status: billingpb.ResponseStatusOk, Item: &billingpb.Merchant{}}, nil)
 	billingService.On("SetMerchantTariffRates", mock2.Anything, mock2.Anything, mock2.Anything).
 		Return(&billingpb.CheckProjectRequestSignatureResponse{Status: billingpb.ResponseStatusOk}, nil)
 	suite.router.dispatch.Services.Billing = billingService
@@ -1988,6 +2000,8 @@ func (suite *OnboardingTestSuite) TestOnboarding_SetTariffRates_BillingServerErr
 	body := `{"home_region": "russia_and_cis", "merchant_operations_type": "low-risk"}`
 
 	billingService := &billMock.BillingService{}
+	billingService.On("GetMerchantBy", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetMerchantResponse{Status: billingpb.ResponseStatusOk, Item: &billingpb.Merchant{}}, nil)
 	billingService.On("SetMerchantTariffRates", mock2.Anything, mock2.Anything, mock2.Anything).
 		Return(nil, errors.New("some error"))
 	suite.router.dispatch.Services.Billing = billingService
@@ -2012,6 +2026,8 @@ func (suite *OnboardingTestSuite) TestOnboarding_SetTariffRates_BillingServerRes
 	body := `{"home_region": "russia_and_cis", "merchant_operations_type": "low-risk"}`
 
 	billingService := &billMock.BillingService{}
+	billingService.On("GetMerchantBy", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetMerchantResponse{Status: billingpb.ResponseStatusOk, Item: &billingpb.Merchant{}}, nil)
 	billingService.On("SetMerchantTariffRates", mock2.Anything, mock2.Anything, mock2.Anything).
 		Return(&billingpb.CheckProjectRequestSignatureResponse{Status: billingpb.ResponseStatusBadData, Message: mock.SomeError}, nil)
 	suite.router.dispatch.Services.Billing = billingService
@@ -53,7 +53,7 @@ func (h *OperatingCompanyRoute) getOperatingCompanyList(ctx echo.Context) error
 
 	res, err := h.dispatch.Services.Billing.GetOperatingCompaniesList(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetOperatingCompaniesList", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetOperatingCompaniesList", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 	if res.Status != http.StatusOK {
@@ -80,7 +80,7 @@ func (h *OperatingCompanyRoute) getOperatingCompany(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Billing.GetOperatingCompany(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetOperatingCompaniesList", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetOperatingCompaniesList", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 	if res.Status != http.StatusOK {
@@ -133,13 +133,13 @@ func (h *OperatingCompanyRoute) addOrUpdateOperatingCompany(ctx echo.Context, op
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.AddOperatingCompany(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "AddOperatingCompany", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "AddOperatingCompany", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
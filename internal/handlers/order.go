@@ -14,6 +14,8 @@ import (
 	"net/http"
 	"reflect"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -25,13 +27,43 @@ const (
 	orderRefundsIdsPath                = "/order/:order_id/refunds/:refund_id"
 	orderReplaceCodePath               = "/order/:order_id/replace_code"
 	orderGetLogsPath                   = "/order/:order_id/logs"
+	orderStatusPath                    = "/order/:order_id/status"
+	orderTimelinePath                  = "/order/:order_id/timeline"
+	orderInvoicePath                   = "/order/invoice/:invoice_id"
 	merchantIdTransactionsDownloadPath = "/merchants/:merchant_id/transactions/download"
 )
 
 const (
-	errorTemplateName = "error.html"
+	// the order listing is anchored to the creation time of the first page, so the orders
+	// created during the scan don't shift the next pages
+	orderListAnchorParam = "project_date_to"
+	orderListSortUnique  = "_id"
 )
 
+const (
+	OrderTimelineEventStatus         = "status"
+	OrderTimelineEventPaymentRequest = "payment_request"
+	OrderTimelineEventCallback       = "callback"
+	OrderTimelineEventRefund         = "refund"
+	OrderTimelineEventChargeback     = "chargeback"
+	OrderTimelineEventNotification   = "notification"
+
+	orderTimelineQueryWindow = "window"
+	// CloudWatch returns at most 10000 events per call
+	orderTimelinePageMax = 10000
+	// the limit of the log events if OrderTimelineMaxEvents isn't positive
+	orderTimelineMaxEventsDefault = 1000
+)
+
+var orderRefundStatuses = map[int32]string{
+	billingpb.RefundStatusCreated:               "created",
+	billingpb.RefundStatusRejected:              "rejected",
+	billingpb.RefundStatusInProgress:            "in_progress",
+	billingpb.RefundStatusCompleted:             "completed",
+	billingpb.RefundStatusPaymentSystemDeclined: "payment_system_declined",
+	billingpb.RefundStatusPaymentSystemCanceled: "payment_system_canceled",
+}
+
 type CreateOrderJsonProjectResponse struct {
 	Id              string                         `json:"id"`
 	PaymentFormUrl  string                         `json:"payment_form_url"`
@@ -63,10 +95,62 @@ type ListOrdersRequest struct {
 	HideTest bool `json:"hide_test"`
 }
 
+// ListOrdersPublicPage is the page of the orders with the cursors of the next and previous pages
+type ListOrdersPublicPage struct {
+	*billingpb.ListOrdersPublicResponseItem
+	*common.CursorPage
+}
+
+// ListOrdersPrivatePage is the page of the orders with the cursors of the next and previous pages
+type ListOrdersPrivatePage struct {
+	*billingpb.ListOrdersPrivateResponseItem
+	*common.CursorPage
+}
+
+// ListOrdersPage is the page of the orders with the cursors of the next and previous pages
+type ListOrdersPage struct {
+	*billingpb.ListOrdersResponseItem
+	*common.CursorPage
+}
+
+// ListRefundsPage is the page of the refunds with the cursors of the next and previous pages
+type ListRefundsPage struct {
+	*billingpb.ListRefundsResponse
+	*common.CursorPage
+	// The merchant's references of the refunds created by the merchant's server by the refund ID.
+	MerchantReferences map[string]string `json:"merchant_references,omitempty"`
+}
+
+// CreateRefundS2sRequest is the refund request of the merchant's server
+type CreateRefundS2sRequest struct {
+	// The refund amount in the order currency, the amount can't exceed the amount not refunded yet.
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	// The refund reason code. Available values: requested_by_customer, duplicate, fraudulent, product_not_delivered, product_unacceptable, other.
+	Reason string `json:"reason" validate:"required,oneof=requested_by_customer duplicate fraudulent product_not_delivered product_unacceptable other"`
+	// The unique identifier for the refund in the merchant's billing system.
+	MerchantReference string `json:"merchant_reference" validate:"omitempty,max=255"`
+}
+
+// RefundS2sResponse is the refund created by the merchant's server
+type RefundS2sResponse struct {
+	*billingpb.Refund
+	// The unique identifier for the refund in the merchant's billing system.
+	MerchantReference string `json:"merchant_reference,omitempty"`
+}
+
+// OrderStatusResponse is the current payment status of the order
+type OrderStatusResponse struct {
+	// The unique identifier for the order in PaySuper's billing system.
+	Id string `json:"id"`
+	// The order's status. Available values: created, processed, canceled, rejected, refunded, chargeback, pending.
+	Status string `json:"status"`
+}
+
 type cloudWatchLogSettings struct {
-	group   string
-	pattern func(order *billingpb.OrderViewPublic) string
-	setter  func(result *GetOrderLogsResponse, value *LogOrder)
+	group     string
+	eventType string
+	pattern   func(order *billingpb.OrderViewPublic) string
+	setter    func(result *GetOrderLogsResponse, value *LogOrder)
 }
 
 type cloudWatch struct {
@@ -98,6 +182,40 @@ type GetOrderLogsResponse struct {
 	Callback []*LogOrder `json:"callback"`
 	// The order's logs list of the notification about the payment status sent to the project.
 	Notify []*LogOrder `json:"notify"`
+	// Has a true value if some logs are missing because the logs storage is unavailable.
+	Incomplete bool `json:"incomplete"`
+	// The number of the log events which can't be read.
+	Unparsed int `json:"unparsed,omitempty"`
+}
+
+type OrderTimelineEvent struct {
+	// The date of the event.
+	Date time.Time `json:"date"`
+	// The event type. Available values: status, payment_request, callback, refund, chargeback, notification.
+	Type string `json:"type"`
+	// The order status for the status events, the refund status for the refund and chargeback events
+	// and the HTTP status of the response for the others.
+	Status interface{} `json:"status,omitempty"`
+	// The refund amount.
+	Amount float64 `json:"amount,omitempty"`
+	// The refund currency.
+	Currency string `json:"currency,omitempty"`
+	// The log record of the request, it's available for the system users only.
+	Log *LogOrder `json:"log,omitempty"`
+}
+
+type OrderTimelineResponse struct {
+	// The order's events list, the oldest first. The billing server keeps the creation and the last status
+	// change of the order only, so the intermediate statuses of the order aren't listed.
+	Events []*OrderTimelineEvent `json:"events"`
+	// Has a true value if the order's status changed after the creation, so the intermediate statuses may be missing.
+	PartialStatusHistory bool `json:"partial_status_history"`
+	// Has a true value if the log events of the order exceed the limit and the oldest of them are listed only.
+	Truncated bool `json:"truncated"`
+	// Has a true value if some log events are missing because the logs storage is unavailable.
+	Incomplete bool `json:"incomplete"`
+	// The number of the log events which can't be read.
+	Unparsed int `json:"unparsed,omitempty"`
 }
 
 type OrderListRefundsBinder struct {
@@ -129,18 +247,21 @@ type OrderRoute struct {
 	cfg      common.Config
 	provider.LMT
 	*cloudWatch
+	refunds common.RefundStore
 }
 
 func NewOrderRoute(
 	set common.HandlerSet,
 	cloudWatchLog common.CloudWatchInterface,
+	refunds common.RefundStore,
 	cfg *common.Config,
 ) *OrderRoute {
 	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "OrderRoute"})
 	cloudWatch := &cloudWatch{
 		logSettings: []*cloudWatchLogSettings{
 			{
-				group: cfg.AwsCloudWatchLogGroupBillingServer,
+				group:     cfg.AwsCloudWatchLogGroupBillingServer,
+				eventType: OrderTimelineEventPaymentRequest,
 				pattern: func(order *billingpb.OrderViewPublic) string {
 					return order.Id + " cardpay"
 				},
@@ -149,7 +270,8 @@ func NewOrderRoute(
 				},
 			},
 			{
-				group: cfg.AwsCloudWatchLogGroupManagementApi,
+				group:     cfg.AwsCloudWatchLogGroupManagementApi,
+				eventType: OrderTimelineEventCallback,
 				pattern: func(order *billingpb.OrderViewPublic) string {
 					return order.Id + " webhook"
 				},
@@ -158,7 +280,8 @@ func NewOrderRoute(
 				},
 			},
 			{
-				group: cfg.AwsCloudWatchLogGroupWebhookNotifier,
+				group:     cfg.AwsCloudWatchLogGroupWebhookNotifier,
+				eventType: OrderTimelineEventNotification,
 				pattern: func(order *billingpb.OrderViewPublic) string {
 					return order.Uuid + " delivery_try"
 				},
@@ -174,6 +297,7 @@ func NewOrderRoute(
 		dispatch:   set,
 		LMT:        &set.AwareSet,
 		cloudWatch: cloudWatch,
+		refunds:    refunds,
 		cfg:        *cfg,
 	}
 }
@@ -185,16 +309,23 @@ func (h *OrderRoute) Route(groups *common.Groups) {
 	groups.AuthUser.GET(orderRefundsPath, h.listRefunds)
 	groups.AuthUser.GET(orderRefundsIdsPath, h.getRefund)
 	groups.AuthUser.POST(orderRefundsPath, h.createRefund)
+	groups.AuthUser.GET(orderTimelinePath, h.getOrderTimelinePublic)
 
 	groups.SystemUser.GET(orderPath, h.listOrdersPrivate)
 	groups.SystemUser.GET(orderIdPath, h.getOrderPrivate)
 	groups.SystemUser.POST(merchantIdTransactionsDownloadPath, h.downloadOrdersPublic)
 	groups.SystemUser.GET(orderGetLogsPath, h.getOrderLogs)
+	groups.SystemUser.GET(orderTimelinePath, h.getOrderTimelinePrivate)
 	groups.SystemUser.GET(orderRefundsIdsPath, h.getRefund)
 	groups.SystemUser.POST(orderRefundsPath, h.createRefund)
 	groups.SystemUser.PUT(orderReplaceCodePath, h.replaceCode)
 
-	groups.MerchantS2S.GET(orderPath, h.listOrdersS2s)
+	groups.MerchantS2S.GET(orderPath, h.listOrdersS2s, common.RequireApiKeyScope(common.ApiKeyScopeReadOrders))
+	groups.MerchantS2S.GET(orderIdPath, h.getOrderS2s, common.RequireApiKeyScope(common.ApiKeyScopeReadOrders))
+	groups.MerchantS2S.GET(orderInvoicePath, h.getOrderByInvoiceS2s, common.RequireApiKeyScope(common.ApiKeyScopeReadOrders))
+	groups.MerchantS2S.GET(orderStatusPath, h.getOrderStatusS2s, common.RequireApiKeyScope(common.ApiKeyScopeReadOrders))
+	groups.MerchantS2S.GET(orderRefundsPath, h.listRefundsS2s, common.RequireApiKeyScope(common.ApiKeyScopeReadOrders))
+	groups.MerchantS2S.POST(orderRefundsPath, h.createRefundS2s, common.RequireApiKeyScope(common.ApiKeyScopeRefunds), common.RequireIdempotencyKey)
 }
 
 // @summary Get the full data about the order
@@ -209,19 +340,13 @@ func (h *OrderRoute) Route(groups *common.Groups) {
 // @param order_id path {string} true The unique identifier for the order.
 // @router /admin/api/v1/order/{order_id} [get]
 func (h *OrderRoute) getOrderPublic(ctx echo.Context) error {
-	res, err := h.getOrder(ctx, h.dispatch.Services.Billing.GetOrderPublic)
+	order, err := h.getOrderPublicItem(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	typed := res.(*billingpb.GetOrderPublicResponse)
-
-	if typed.Status != billingpb.ResponseStatusOk {
-		return echo.NewHTTPError(int(typed.Status), typed.Message)
-	}
-
-	return ctx.JSON(http.StatusOK, typed.Item)
+	return ctx.JSON(http.StatusOK, order)
 }
 
 // @summary Get the full private data about the order
@@ -257,7 +382,7 @@ func (h *OrderRoute) getOrderPrivate(ctx echo.Context) error {
 // @tag Order
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.ListOrdersPublicResponseItem Returns the orders list
+// @success 200 {object} ListOrdersPublicPage Returns the orders list
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param id query {string} false The unique identifier for the order.
@@ -276,9 +401,10 @@ func (h *OrderRoute) getOrderPrivate(ctx echo.Context) error {
 // @param sort query {[]string} false The list of the order's fields for sorting.
 // @param type query {string} false The sales type. Available values: simple, product, key.
 // @param hide_test query {boolean} false Has a true value for getting only production orders.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /admin/api/v1/order [get]
 func (h *OrderRoute) listOrdersPublic(ctx echo.Context) error {
-	rsp, err := h.listOrders(ctx, h.dispatch.Services.Billing.FindAllOrdersPublic)
+	rsp, seek, err := h.listOrders(ctx, h.dispatch.Services.Billing.FindAllOrdersPublic)
 
 	if err != nil {
 		return err
@@ -290,7 +416,16 @@ func (h *OrderRoute) listOrdersPublic(ctx echo.Context) error {
 		return echo.NewHTTPError(int(typed.Status), typed.Message)
 	}
 
-	return ctx.JSON(http.StatusOK, typed.Item)
+	items := typed.Item.GetItems()
+	start, end, page := seek.Page(ctx, &h.cfg, typed.Item.GetCount(), len(items), func(i int) string {
+		return items[i].Id
+	})
+
+	if typed.Item != nil {
+		typed.Item.Items = items[start:end]
+	}
+
+	return ctx.JSON(http.StatusOK, &ListOrdersPublicPage{typed.Item, page})
 }
 
 // @summary Get the private orders list
@@ -299,7 +434,7 @@ func (h *OrderRoute) listOrdersPublic(ctx echo.Context) error {
 // @tag Order
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.ListOrdersPublicResponseItem Returns the orders list
+// @success 200 {object} ListOrdersPrivatePage Returns the orders list
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param id query {string} false The unique identifier for the order.
@@ -318,9 +453,10 @@ func (h *OrderRoute) listOrdersPublic(ctx echo.Context) error {
 // @param sort query {[]string} false The list of the order's fields for sorting.
 // @param type query {string} false The sales type. Available values: simple, product, key.
 // @param hide_test query {boolean} false Has a true value for getting only production orders.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /system/api/v1/order [get]
 func (h *OrderRoute) listOrdersPrivate(ctx echo.Context) error {
-	rsp, err := h.listOrders(ctx, h.dispatch.Services.Billing.FindAllOrdersPrivate)
+	rsp, seek, err := h.listOrders(ctx, h.dispatch.Services.Billing.FindAllOrdersPrivate)
 
 	if err != nil {
 		return err
@@ -332,7 +468,16 @@ func (h *OrderRoute) listOrdersPrivate(ctx echo.Context) error {
 		return echo.NewHTTPError(int(typed.Status), typed.Message)
 	}
 
-	return ctx.JSON(http.StatusOK, typed.Item)
+	items := typed.Item.GetItems()
+	start, end, page := seek.Page(ctx, &h.cfg, typed.Item.GetCount(), len(items), func(i int) string {
+		return items[i].Id
+	})
+
+	if typed.Item != nil {
+		typed.Item.Items = items[start:end]
+	}
+
+	return ctx.JSON(http.StatusOK, &ListOrdersPrivatePage{typed.Item, page})
 }
 
 // @summary Get the orders list
@@ -341,7 +486,7 @@ func (h *OrderRoute) listOrdersPrivate(ctx echo.Context) error {
 // @tag Order
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.ListOrdersPublicResponseItem Returns the orders list
+// @success 200 {object} ListOrdersPage Returns the orders list
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param id query {string} false The unique identifier for the order in PaySuper's billing system.
@@ -354,9 +499,10 @@ func (h *OrderRoute) listOrdersPrivate(ctx echo.Context) error {
 // @param limit query {integer} true The number of orders returned in one page. Default value is 100.
 // @param offset query {integer} false The ranking number of the first item on the page.
 // @param sort query {[]string} false The list of the order's fields for sorting.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /merchant/s2s/api/v1/order [get]
 func (h *OrderRoute) listOrdersS2s(ctx echo.Context) error {
-	rsp, err := h.listOrders(ctx, h.dispatch.Services.Billing.FindAllOrders)
+	rsp, seek, err := h.listOrders(ctx, h.dispatch.Services.Billing.FindAllOrders)
 
 	if err != nil {
 		return err
@@ -368,7 +514,106 @@ func (h *OrderRoute) listOrdersS2s(ctx echo.Context) error {
 		return echo.NewHTTPError(int(typed.Status), typed.Message)
 	}
 
-	return ctx.JSON(http.StatusOK, typed.Item)
+	items := typed.Item.GetItems()
+	start, end, page := seek.Page(ctx, &h.cfg, typed.Item.GetCount(), len(items), func(i int) string {
+		return items[i].Id
+	})
+
+	if typed.Item != nil {
+		typed.Item.Items = items[start:end]
+	}
+
+	return ctx.JSON(http.StatusOK, &ListOrdersPage{typed.Item, page})
+}
+
+// @summary Get the full data about the order
+// @desc Get the full data about the order of the merchant using the order ID
+// @id merchantS2SOrderIdPathGetOrder
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} billingpb.OrderViewPublic Returns the order data
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 403 {object} billingpb.ResponseErrorMessage The API key has no read-orders scope
+// @failure 404 {object} billingpb.ResponseErrorMessage The order not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param order_id path {string} true The unique identifier for the order in PaySuper's billing system.
+// @router /merchant/s2s/api/v1/order/{order_id} [get]
+func (h *OrderRoute) getOrderS2s(ctx echo.Context) error {
+	order, err := h.getOrderPublicItem(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, order)
+}
+
+// @summary Get the order by the invoice ID
+// @desc Get the full data about the order of the project using the order ID in the merchant's billing system
+// @id merchantS2SOrderInvoicePathGetOrderByInvoice
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} billingpb.OrderViewPublic Returns the order data
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 403 {object} billingpb.ResponseErrorMessage The API key has no read-orders scope
+// @failure 404 {object} billingpb.ResponseErrorMessage The order not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param invoice_id path {string} true The unique identifier for the order in the merchant's billing system.
+// @router /merchant/s2s/api/v1/order/invoice/{invoice_id} [get]
+func (h *OrderRoute) getOrderByInvoiceS2s(ctx echo.Context) error {
+	user := common.ExtractUserContext(ctx)
+	// the invoice ID is unique within the project only
+	req := &billingpb.ListOrdersRequest{
+		MerchantId: user.MerchantId,
+		Project:    []string{user.ProjectId},
+		InvoiceId:  ctx.Param(common.RequestParameterInvoiceId),
+		Limit:      1,
+	}
+
+	if req.InvoiceId == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	res, err := h.dispatch.Services.Billing.FindAllOrdersPublic(ctx.Request().Context(), req)
+
+	if err != nil {
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "FindAllOrdersPublic")
+	}
+
+	if res.Status != billingpb.ResponseStatusOk {
+		return echo.NewHTTPError(int(res.Status), res.Message)
+	}
+
+	if len(res.Item.GetItems()) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageOrdersNotFound)
+	}
+
+	return ctx.JSON(http.StatusOK, res.Item.Items[0])
+}
+
+// @summary Get the payment status of the order
+// @desc Get the current payment status of the order of the merchant using the order ID
+// @id merchantS2SOrderStatusPathGetOrderStatus
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} OrderStatusResponse Returns the order status
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 403 {object} billingpb.ResponseErrorMessage The API key has no read-orders scope
+// @failure 404 {object} billingpb.ResponseErrorMessage The order not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param order_id path {string} true The unique identifier for the order in PaySuper's billing system.
+// @router /merchant/s2s/api/v1/order/{order_id}/status [get]
+func (h *OrderRoute) getOrderStatusS2s(ctx echo.Context) error {
+	order, err := h.getOrderPublicItem(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, &OrderStatusResponse{Id: order.Uuid, Status: order.Status})
 }
 
 // @summary Export the orders list
@@ -469,7 +714,7 @@ func (h *OrderRoute) getRefund(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetRefund(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetRefund")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetRefund")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -485,12 +730,13 @@ func (h *OrderRoute) getRefund(ctx echo.Context) error {
 // @tag Order
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.ListRefundsResponse Returns the order's refunds list
+// @success 200 {object} ListRefundsPage Returns the order's refunds list
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param order_id path {string} true The unique identifier for the order.
 // @param limit query {integer} true The number of refunds returned in one page. Default value is 100.
 // @param offset query {integer} false The ranking number of the first item on the page.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /admin/api/v1/order/{order_id}/refunds [get]
 func (h *OrderRoute) listRefunds(ctx echo.Context) error {
 	req := &billingpb.ListRefundsRequest{}
@@ -500,16 +746,81 @@ func (h *OrderRoute) listRefunds(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
+	}
+
+	return h.listRefundsPage(ctx, req)
+}
+
+// @summary Get the order's refunds list
+// @desc Get the refunds list of the merchant's order using the order ID
+// @id merchantS2SOrderRefundsPathListRefunds
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} ListRefundsPage Returns the order's refunds list
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 403 {object} billingpb.ResponseErrorMessage The API key has no read-orders scope
+// @failure 404 {object} billingpb.ResponseErrorMessage The order not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param order_id path {string} true The unique identifier for the order in PaySuper's billing system.
+// @param limit query {integer} true The number of refunds returned in one page. Default value is 100.
+// @param offset query {integer} false The ranking number of the first item on the page.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
+// @router /merchant/s2s/api/v1/order/{order_id}/refunds [get]
+func (h *OrderRoute) listRefundsS2s(ctx echo.Context) error {
+	req := &billingpb.ListRefundsRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return common.NewValidationHTTPError(err)
 	}
 
+	// the refunds are listed for the order of the project only
+	order, err := h.getOrderPublicItem(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	req.OrderId = order.Uuid
+
+	return h.listRefundsPage(ctx, req)
+}
+
+// listRefundsPage returns the page of the order refunds with the merchant's references of the refunds
+func (h *OrderRoute) listRefundsPage(ctx echo.Context, req *billingpb.ListRefundsRequest) error {
+	seek := common.NewPageSeek(ctx, &h.cfg, req.Limit, req.Offset)
+	req.Limit, req.Offset = seek.Limit, seek.Offset
+
 	res, err := h.dispatch.Services.Billing.ListRefunds(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ListRefunds")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ListRefunds")
+	}
+
+	items := res.GetItems()
+	start, end, page := seek.Page(ctx, &h.cfg, int64(res.GetCount()), len(items), func(i int) string {
+		return items[i].Id
+	})
+	res.Items = items[start:end]
+	refundIds := make([]string, 0, len(res.Items))
+
+	for _, item := range res.Items {
+		refundIds = append(refundIds, item.Id)
+	}
+
+	references, err := h.refunds.References(refundIds)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
-	return ctx.JSON(http.StatusOK, res)
+	return ctx.JSON(http.StatusOK, &ListRefundsPage{res, page, references})
 }
 
 // @summary Replaces the activation code in the order
@@ -532,14 +843,14 @@ func (h *OrderRoute) replaceCode(ctx echo.Context) error {
 
 	req.OrderId = ctx.Param("order_id")
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res := &billingpb.ChangeCodeInOrderResponse{}
 
 	res, err := h.dispatch.Services.Billing.ChangeCodeInOrder(ctx.Request().Context(), req)
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeCodeInOrder")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeCodeInOrder")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -587,14 +898,14 @@ func (h *OrderRoute) createRefund(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	req.CreatorId = authUser.Id
 	res, err := h.dispatch.Services.Billing.CreateRefund(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "CreateRefund")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "CreateRefund")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -604,6 +915,90 @@ func (h *OrderRoute) createRefund(ctx echo.Context) error {
 	return ctx.JSON(http.StatusCreated, res.Item)
 }
 
+// @summary Create a refund
+// @desc Create a full or partial refund of the merchant's order. The Idempotency-Key header is required.
+// @id merchantS2SOrderRefundsPathCreateRefund
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @body CreateRefundS2sRequest
+// @success 201 {object} RefundS2sResponse Returns the refund data with the merchant's reference
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data or the amount exceeds the refundable amount
+// @failure 403 {object} billingpb.ResponseErrorMessage The API key has no refunds scope
+// @failure 409 {object} billingpb.ResponseErrorMessage The idempotency key was used for another request or another refund of the order is being created
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param order_id path {string} true The unique identifier for the order in PaySuper's billing system.
+// @router /merchant/s2s/api/v1/order/{order_id}/refunds [post]
+func (h *OrderRoute) createRefundS2s(ctx echo.Context) error {
+	user := common.ExtractUserContext(ctx)
+	req := &CreateRefundS2sRequest{}
+
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	if err := h.dispatch.Validate.Struct(req); err != nil {
+		return common.NewValidationHTTPError(err)
+	}
+
+	order, err := h.getOrderPublicItem(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// the requests with the different idempotency keys must not pass the remaining amount check together
+	unlock, err := lockOrderRefund(ctx.Request().Context(), h.dispatch.Services.Billing, h.refunds, h.cfg.LimitMax, order, req.Amount)
+
+	if err != nil {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return httpErr
+		}
+
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.PairArgs("err", err.Error()))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
+	}
+
+	defer func() {
+		if err := unlock(); err != nil {
+			common.RequestLogger(ctx, h.L()).Error("refund lock can't be released", logger.PairArgs("order_id", order.Uuid, "err", err.Error()))
+		}
+	}()
+
+	refundReq := &billingpb.CreateRefundRequest{
+		OrderId:   order.Uuid,
+		Amount:    req.Amount,
+		CreatorId: user.ProjectId,
+		Reason:    req.Reason,
+	}
+
+	res, err := h.dispatch.Services.Billing.CreateRefund(ctx.Request().Context(), refundReq)
+
+	if err != nil {
+		return h.dispatch.SrvCallHandler(ctx, refundReq, err, billingpb.ServiceName, "CreateRefund")
+	}
+
+	if res.Status != billingpb.ResponseStatusOk {
+		return echo.NewHTTPError(int(res.Status), res.Message)
+	}
+
+	if req.MerchantReference != "" {
+		ref := &common.RefundReference{
+			RefundId:          res.Item.Id,
+			OrderId:           order.Uuid,
+			MerchantReference: req.MerchantReference,
+			CreatedAt:         time.Now().UTC(),
+		}
+
+		// the refund is created already, so the failed reference is logged only and the refund is returned
+		if err := h.refunds.AddReference(ref); err != nil {
+			common.RequestLogger(ctx, h.L()).Error("refund merchant reference can't be saved", logger.PairArgs("refund_id", ref.RefundId, "err", err.Error()))
+		}
+	}
+
+	return ctx.JSON(http.StatusCreated, &RefundS2sResponse{Refund: res.Item, MerchantReference: req.MerchantReference})
+}
+
 // @summary Get the order's logs list
 // @desc Get the order's logs list using the order ID
 // @id orderLogsPathListLogs
@@ -616,89 +1011,285 @@ func (h *OrderRoute) createRefund(ctx echo.Context) error {
 // @param order_id path {string} true The unique identifier for the order.
 // @router /system/api/v1/order/{order_id}/logs [get]
 func (h *OrderRoute) getOrderLogs(ctx echo.Context) error {
-	res, err := h.getOrder(ctx, h.dispatch.Services.Billing.GetOrderPublic)
+	order, err := h.getOrderPublicItem(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	typed := res.(*billingpb.GetOrderPublicResponse)
+	createdAt, err := ptypes.Timestamp(order.CreatedAt)
 
-	if typed.Status != billingpb.ResponseStatusOk {
-		return echo.NewHTTPError(int(typed.Status), typed.Message)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
+	}
+
+	result := new(GetOrderLogsResponse)
+
+	for _, val := range h.cloudWatch.logSettings {
+		events, _, err := h.filterLogEvents(ctx, val.group, val.pattern(order), createdAt, createdAt.Add(h.cfg.OrderTimelineWindow))
+
+		if err != nil {
+			result.Incomplete = true
+			continue
+		}
+
+		for _, event := range events {
+			logOrder, err := h.parseLogEvent(event)
+
+			if err != nil {
+				result.Unparsed++
+				continue
+			}
+
+			val.setter(result, logOrder)
+		}
+	}
+
+	if result.Unparsed > 0 {
+		common.RequestLogger(ctx, h.L()).Error("order log events can't be parsed", logger.PairArgs("order_id", order.Uuid, "count", result.Unparsed))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// @summary Get the order's timeline
+// @desc Get the order's status changes, refunds, chargebacks and notifications of the merchant in the time order
+// @id orderTimelinePathGetOrderTimelinePublic
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} OrderTimelineResponse Returns the order's timeline
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param order_id path {string} true The unique identifier for the order.
+// @param window query {string} false The period after the order creation to search the logs in, for instance 72h. Default value is 168h.
+// @router /admin/api/v1/order/{order_id}/timeline [get]
+func (h *OrderRoute) getOrderTimelinePublic(ctx echo.Context) error {
+	return h.getOrderTimeline(ctx, true)
+}
+
+// @summary Get the order's timeline
+// @desc Get the order's status changes, payment system requests and callbacks, refunds, chargebacks
+// @desc and notifications of the merchant with the logs of the requests in the time order
+// @id systemOrderTimelinePathGetOrderTimelinePrivate
+// @tag Order
+// @accept application/json
+// @produce application/json
+// @success 200 {object} OrderTimelineResponse Returns the order's timeline
+// @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param order_id path {string} true The unique identifier for the order.
+// @param window query {string} false The period after the order creation to search the logs in, for instance 72h. Default value is 168h.
+// @router /system/api/v1/order/{order_id}/timeline [get]
+func (h *OrderRoute) getOrderTimelinePrivate(ctx echo.Context) error {
+	return h.getOrderTimeline(ctx, false)
+}
+
+// getOrderTimeline merges the order's events, the redacted timeline has no logs and the requests to the payment system
+func (h *OrderRoute) getOrderTimeline(ctx echo.Context, redacted bool) error {
+	window := h.cfg.OrderTimelineWindow
+
+	if value := ctx.QueryParam(orderTimelineQueryWindow); value != "" {
+		d, err := time.ParseDuration(value)
+
+		if err != nil || d <= 0 || d > h.cfg.OrderTimelineWindowMax {
+			return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+		}
+
+		window = d
+	}
+
+	order, err := h.getOrderPublicItem(ctx)
+
+	if err != nil {
+		return err
 	}
 
-	order := typed.Item
 	createdAt, err := ptypes.Timestamp(order.CreatedAt)
 
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
-	result := new(GetOrderLogsResponse)
+	// the billing server keeps the creation and the last status change of the order only
+	result := &OrderTimelineResponse{
+		Events: []*OrderTimelineEvent{{Date: createdAt, Type: OrderTimelineEventStatus, Status: "created"}},
+	}
+
+	if changedAt, err := ptypes.Timestamp(order.TransactionDate); err == nil && order.Status != "created" {
+		result.Events = append(result.Events, &OrderTimelineEvent{Date: changedAt, Type: OrderTimelineEventStatus, Status: order.Status})
+		result.PartialStatusHistory = true
+	}
+
+	refunds, err := h.listOrderRefunds(ctx, order.Uuid)
+
+	if err != nil {
+		return err
+	}
+
+	for _, refund := range refunds {
+		date, err := ptypes.Timestamp(refund.CreatedAt)
+
+		if err != nil {
+			continue
+		}
+
+		event := &OrderTimelineEvent{
+			Date:     date,
+			Type:     OrderTimelineEventRefund,
+			Status:   orderRefundStatuses[refund.Status],
+			Amount:   refund.Amount,
+			Currency: refund.Currency,
+		}
+
+		if refund.IsChargeback {
+			event.Type = OrderTimelineEventChargeback
+		}
+
+		result.Events = append(result.Events, event)
+	}
 
 	for _, val := range h.cloudWatch.logSettings {
-		pattern := val.pattern(order)
-
-		rsp, err := h.cloudWatch.instance.FilterLogEventsWithContext(
-			ctx.Request().Context(),
-			&cloudwatchlogs.FilterLogEventsInput{
-				Limit:         aws.Int64(100),
-				LogGroupName:  aws.String(val.group),
-				StartTime:     aws.Int64(aws.TimeUnixMilli(createdAt)),
-				EndTime:       aws.Int64(aws.TimeUnixMilli(createdAt.AddDate(0, 0, 7))),
-				FilterPattern: aws.String(pattern),
-			},
-		)
+		if redacted && val.eventType == OrderTimelineEventPaymentRequest {
+			continue
+		}
+
+		events, truncated, err := h.filterLogEvents(ctx, val.group, val.pattern(order), createdAt, createdAt.Add(window))
 
 		if err != nil {
-			h.dispatch.AwareSet.L().Error(
-				"get logs form amazon cloudwatch failed",
-				logger.PairArgs(
-					"group", val.group,
-					"pattern", pattern,
-				),
-				logger.WithPrettyFields(logger.Fields{"err": err}),
-			)
+			result.Incomplete = true
 			continue
 		}
 
-		for _, event := range rsp.Events {
-			log := make(map[string]interface{})
-			err = json.Unmarshal([]byte(*event.Message), &log)
+		result.Truncated = result.Truncated || truncated
+
+		for _, event := range events {
+			logOrder, err := h.parseLogEvent(event)
 
 			if err != nil {
+				result.Unparsed++
 				continue
 			}
 
-			logOrder := &LogOrder{
-				Date: aws.MillisecondsTimeValue(event.Timestamp),
-				Uri:  log["msg"],
-				Request: &LogRequest{
-					Headers: log["request_headers"],
-					Body:    log["request_body"],
-				},
-				Response: &LogResponse{
-					HttpStatus: log["response_status"],
-					LogRequest: LogRequest{
-						Headers: log["response_headers"],
-						Body:    log["response_body"],
-					},
-				},
+			item := &OrderTimelineEvent{Date: logOrder.Date, Type: val.eventType, Status: logOrder.Response.HttpStatus}
+
+			if !redacted {
+				item.Log = logOrder
 			}
-			val.setter(result, logOrder)
+
+			result.Events = append(result.Events, item)
 		}
 	}
 
+	if result.Unparsed > 0 {
+		common.RequestLogger(ctx, h.L()).Error("order log events can't be parsed", logger.PairArgs("order_id", order.Uuid, "count", result.Unparsed))
+	}
+
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		return result.Events[i].Date.Before(result.Events[j].Date)
+	})
+
 	return ctx.JSON(http.StatusOK, result)
 }
 
-func (h *OrderRoute) listOrders(ctx echo.Context, fn interface{}) (interface{}, error) {
+// filterLogEvents reads the pages of the log events until OrderTimelineMaxEvents, truncated is true if the limit is reached
+func (h *OrderRoute) filterLogEvents(ctx echo.Context, group, pattern string, from, to time.Time) ([]*cloudwatchlogs.FilteredLogEvent, bool, error) {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName:  aws.String(group),
+		StartTime:     aws.Int64(aws.TimeUnixMilli(from)),
+		EndTime:       aws.Int64(aws.TimeUnixMilli(to)),
+		FilterPattern: aws.String(pattern),
+	}
+	var events []*cloudwatchlogs.FilteredLogEvent
+	maxEvents := h.cfg.OrderTimelineMaxEvents
+
+	if maxEvents <= 0 {
+		maxEvents = orderTimelineMaxEventsDefault
+	}
+
+	for {
+		limit := maxEvents - len(events)
+
+		if limit > orderTimelinePageMax {
+			limit = orderTimelinePageMax
+		}
+
+		input.Limit = aws.Int64(int64(limit))
+		rsp, err := h.cloudWatch.instance.FilterLogEventsWithContext(ctx.Request().Context(), input)
+
+		if err != nil {
+			common.RequestLogger(ctx, h.dispatch.AwareSet.L()).Error(
+				"get logs form amazon cloudwatch failed",
+				logger.PairArgs(
+					"group", group,
+					"pattern", pattern,
+				),
+				logger.WithPrettyFields(logger.Fields{"err": err}),
+			)
+			return nil, false, err
+		}
+
+		events = append(events, rsp.Events...)
+
+		if rsp.NextToken == nil || aws.StringValue(rsp.NextToken) == "" {
+			return events, false, nil
+		}
+
+		if len(events) >= maxEvents {
+			return events, true, nil
+		}
+
+		input.NextToken = rsp.NextToken
+	}
+}
+
+// parseLogEvent reads the request log written by the services in JSON
+func (h *OrderRoute) parseLogEvent(event *cloudwatchlogs.FilteredLogEvent) (*LogOrder, error) {
+	log := make(map[string]interface{})
+
+	if err := json.Unmarshal([]byte(aws.StringValue(event.Message)), &log); err != nil {
+		return nil, err
+	}
+
+	return &LogOrder{
+		Date: aws.MillisecondsTimeValue(event.Timestamp),
+		Uri:  log["msg"],
+		Request: &LogRequest{
+			Headers: log["request_headers"],
+			Body:    log["request_body"],
+		},
+		Response: &LogResponse{
+			HttpStatus: log["response_status"],
+			LogRequest: LogRequest{
+				Headers: log["response_headers"],
+				Body:    log["response_body"],
+			},
+		},
+	}, nil
+}
+
+// listOrders calls the listing method of the billing server, the request is returned to build the page cursors
+func (h *OrderRoute) listOrders(ctx echo.Context, fn interface{}) (interface{}, *common.PageSeek, error) {
+	query := ctx.QueryParams()
+
+	if query.Get(orderListAnchorParam) == "" {
+		query.Set(orderListAnchorParam, time.Now().UTC().Format(billingpb.FilterDatetimeFormat))
+	}
+
+	// the unique key makes the order of the rows with the same values of the sort fields stable
+	if sort, ok := query[common.QueryParameterNameSort]; ok && !h.hasSortField(sort, orderListSortUnique) {
+		query[common.QueryParameterNameSort] = append(sort, orderListSortUnique)
+	}
+
 	req := &billingpb.ListOrdersRequest{}
 	err := ctx.Bind(req)
 
 	if err != nil {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
 	}
 
 	if req.Limit <= 0 {
@@ -712,18 +1303,21 @@ func (h *OrderRoute) listOrders(ctx echo.Context, fn interface{}) (interface{},
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return nil, nil, common.NewValidationHTTPError(err)
 	}
 
+	seek := common.NewPageSeek(ctx, &h.cfg, req.Limit, req.Offset)
+	req.Limit, req.Offset = seek.Limit, seek.Offset
+
 	refFn := reflect.ValueOf(fn)
 	fnName := runtime.FuncForPC(refFn.Pointer()).Name()
 	returnValues := refFn.Call([]reflect.Value{reflect.ValueOf(ctx.Request().Context()), reflect.ValueOf(req)})
 
 	if err := returnValues[1].Interface(); err != nil {
-		return nil, h.dispatch.SrvCallHandler(req, err.(error), billingpb.ServiceName, fnName)
+		return nil, nil, h.dispatch.SrvCallHandler(ctx, req, err.(error), billingpb.ServiceName, fnName)
 	}
 
-	return returnValues[0].Interface(), nil
+	return returnValues[0].Interface(), seek, nil
 }
 
 func (h *OrderRoute) getOrder(ctx echo.Context, fn interface{}) (interface{}, error) {
@@ -738,8 +1332,55 @@ func (h *OrderRoute) getOrder(ctx echo.Context, fn interface{}) (interface{}, er
 	returnValues := refFn.Call([]reflect.Value{reflect.ValueOf(ctx.Request().Context()), reflect.ValueOf(req)})
 
 	if err := returnValues[1].Interface(); err != nil {
-		return nil, h.dispatch.SrvCallHandler(req, err.(error), billingpb.ServiceName, fnName)
+		return nil, h.dispatch.SrvCallHandler(ctx, req, err.(error), billingpb.ServiceName, fnName)
 	}
 
 	return returnValues[0].Interface(), nil
 }
+
+// getOrderPublicItem returns the order of the merchant of the request,
+// the requests of the merchant's server get the orders of the signed project only
+func (h *OrderRoute) getOrderPublicItem(ctx echo.Context) (*billingpb.OrderViewPublic, error) {
+	res, err := h.getOrder(ctx, h.dispatch.Services.Billing.GetOrderPublic)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typed := res.(*billingpb.GetOrderPublicResponse)
+
+	if typed.Status != billingpb.ResponseStatusOk {
+		return nil, echo.NewHTTPError(int(typed.Status), typed.Message)
+	}
+
+	user := common.ExtractUserContext(ctx)
+
+	if user.ProjectId != "" && typed.Item.GetProject().GetId() != user.ProjectId {
+		return nil, echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageOrdersNotFound)
+	}
+
+	return typed.Item, nil
+}
+
+// listOrderRefunds returns all refunds of the order, the order must be checked by the caller
+func (h *OrderRoute) listOrderRefunds(ctx echo.Context, orderId string) ([]*billingpb.Refund, error) {
+	refunds, err := listAllOrderRefunds(ctx.Request().Context(), h.dispatch.Services.Billing, h.cfg.LimitMax, orderId)
+
+	if err != nil {
+		req := &billingpb.ListRefundsRequest{OrderId: orderId}
+		return nil, h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ListRefunds")
+	}
+
+	return refunds, nil
+}
+
+// hasSortField checks the field is in the sort list in any direction
+func (h *OrderRoute) hasSortField(sort []string, field string) bool {
+	for _, value := range sort {
+		if strings.TrimPrefix(value, "-") == field {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-proto/go/billingpb"
+	"math"
+	"net/http"
+	"time"
+)
+
+// the lock of the order outlives the billing call if the instance dies while the refund is created
+const orderRefundLockTtl = time.Minute
+
+// lockOrderRefund locks the order for the refund creation and checks the refund amount doesn't exceed
+// the refundable amount of the order. The refunds of the order are created one by one, otherwise
+// the concurrent refunds of the merchant's server and the bulk refunds pass the check together.
+// The order is refused with *echo.HTTPError, unlock must be called after the refund is created.
+func lockOrderRefund(
+	ctx context.Context,
+	billing billingpb.BillingService,
+	refunds common.RefundStore,
+	limit int32,
+	order *billingpb.OrderViewPublic,
+	amount float64,
+) (func() error, error) {
+	locked, err := refunds.Lock(order.Uuid, orderRefundLockTtl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, echo.NewHTTPError(http.StatusConflict, common.ErrorMessageRefundInProgress)
+	}
+
+	unlock := func() error {
+		return refunds.Unlock(order.Uuid)
+	}
+
+	refunded, err := orderRefundedAmount(ctx, billing, limit, order.Uuid)
+
+	if err == nil && refundRound(amount) > refundRound(order.TotalPaymentAmount-refunded) {
+		err = echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessageRefundAmountExceeded)
+	}
+
+	if err != nil {
+		_ = unlock()
+		return nil, err
+	}
+
+	return unlock, nil
+}
+
+// orderRefundedAmount returns the amount of the order refunds except the rejected and failed ones
+func orderRefundedAmount(ctx context.Context, billing billingpb.BillingService, limit int32, orderId string) (float64, error) {
+	refunds, err := listAllOrderRefunds(ctx, billing, limit, orderId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	amount := float64(0)
+
+	for _, refund := range refunds {
+		switch refund.Status {
+		case billingpb.RefundStatusRejected, billingpb.RefundStatusPaymentSystemDeclined, billingpb.RefundStatusPaymentSystemCanceled:
+			continue
+		}
+
+		amount += refund.Amount
+	}
+
+	return amount, nil
+}
+
+// listAllOrderRefunds reads all pages of the order refunds, the order must be checked by the caller
+func listAllOrderRefunds(ctx context.Context, billing billingpb.BillingService, limit int32, orderId string) ([]*billingpb.Refund, error) {
+	req := &billingpb.ListRefundsRequest{
+		OrderId: orderId,
+		Limit:   int64(limit),
+	}
+	var refunds []*billingpb.Refund
+
+	for {
+		res, err := billing.ListRefunds(ctx, req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		refunds = append(refunds, res.Items...)
+		req.Offset += int64(len(res.Items))
+
+		if len(res.Items) == 0 || req.Offset >= int64(res.Count) {
+			return refunds, nil
+		}
+	}
+}
+
+// refundRound rounds the amount to cents, so the float errors of the sum don't deny the full refund
+func refundRound(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
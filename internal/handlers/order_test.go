@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/bxcodec/faker"
 	"github.com/globalsign/mgo/bson"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
@@ -22,14 +23,17 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 )
 
 type OrderTestSuite struct {
 	suite.Suite
 	router *OrderRoute
 	caller *test.EchoReqResCaller
+	user   *common.AuthUser
 }
 
 func Test_Order(t *testing.T) {
@@ -37,7 +41,7 @@ func Test_Order(t *testing.T) {
 }
 
 func (suite *OrderTestSuite) SetupTest() {
-	user := &common.AuthUser{
+	suite.user = &common.AuthUser{
 		Id:         "ffffffffffffffffffffffff",
 		MerchantId: "ffffffffffffffffffffffff",
 	}
@@ -62,8 +66,8 @@ func (suite *OrderTestSuite) SetupTest() {
 		)
 
 	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
-		mw.Pre(test.PreAuthUserMiddleware(user))
-		suite.router = NewOrderRoute(set.HandlerSet, cloudwatchMock, set.GlobalConfig)
+		mw.Pre(test.PreAuthUserMiddleware(suite.user))
+		suite.router = NewOrderRoute(set.HandlerSet, cloudwatchMock, common.NewMemoryRefundStore(), set.GlobalConfig)
 		return common.Handlers{
 			suite.router,
 		}
@@ -274,6 +278,35 @@ func (suite *OrderTestSuite) TestOrder_CreateRefund_ValidationError() {
 	assert.Regexp(suite.T(), common.NewValidationError("Amount"), httpErr.Message)
 }
 
+func (suite *OrderTestSuite) TestOrder_CreateRefund_ValidationError_AllFields() {
+	data := `{"amount": -10}`
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":order_id", uuid.New().String()).
+		Path(common.AuthUserGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		BodyString(data).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusBadRequest, res.Code)
+
+	envelope := &common.ErrorEnvelope{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), envelope))
+	assert.Equal(suite.T(), common.ErrorValidationFailed.Code, envelope.Code)
+
+	fields := make(map[string]*common.FieldError)
+	for _, fieldErr := range envelope.Errors {
+		fields[fieldErr.Field] = fieldErr
+	}
+
+	assert.Contains(suite.T(), fields, "amount")
+	assert.Contains(suite.T(), fields, "reason")
+	assert.NotEmpty(suite.T(), fields["reason"].Message)
+	assert.Empty(suite.T(), common.ErrorValidationFailed.Details)
+}
+
 func (suite *OrderTestSuite) TestOrder_CreateRefund_BillingServerError() {
 	data := `{"amount": 10, "reason": "test"}`
 
@@ -316,6 +349,71 @@ func (suite *OrderTestSuite) TestOrder_CreateRefund_BillingServer_CreateError()
 	assert.Equal(suite.T(), mock.SomeError, httpErr.Message)
 }
 
+func (suite *OrderTestSuite) TestOrder_CreateRefund_IdempotencyKey_Replay_Ok() {
+	data := `{"amount": 10, "reason": "test"}`
+	orderId := uuid.New().String()
+
+	billingService := &billMock.BillingService{}
+	billingService.On("CreateRefund", mock2.Anything, mock2.Anything).
+		Return(&billingpb.CreateRefundResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.Refund{Id: bson.NewObjectId().Hex(), Amount: 10},
+		}, nil)
+	suite.router.dispatch.Services.Billing = billingService
+
+	req := func() (*httptest.ResponseRecorder, error) {
+		return suite.caller.Builder().
+			Method(http.MethodPost).
+			Params(":order_id", orderId).
+			Path(common.AuthUserGroupPath + orderRefundsPath).
+			Init(test.ReqInitJSON()).
+			Init(func(request *http.Request, middleware test.Middleware) {
+				request.Header.Set(common.HeaderIdempotencyKey, "refund-key")
+			}).
+			BodyString(data).
+			Exec(suite.T())
+	}
+
+	first, err := req()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusCreated, first.Code)
+
+	second, err := req()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusCreated, second.Code)
+	assert.Equal(suite.T(), first.Body.String(), second.Body.String())
+	assert.Equal(suite.T(), "true", second.Header().Get(common.HeaderIdempotentReplayed))
+
+	billingService.AssertNumberOfCalls(suite.T(), "CreateRefund", 1)
+}
+
+func (suite *OrderTestSuite) TestOrder_CreateRefund_IdempotencyKey_AnotherBody_Error() {
+	req := func(data string) (*httptest.ResponseRecorder, error) {
+		return suite.caller.Builder().
+			Method(http.MethodPost).
+			Params(":order_id", "ffffffffffffffffffffffff").
+			Path(common.AuthUserGroupPath + orderRefundsPath).
+			Init(test.ReqInitJSON()).
+			Init(func(request *http.Request, middleware test.Middleware) {
+				request.Header.Set(common.HeaderIdempotencyKey, "refund-key")
+			}).
+			BodyString(data).
+			Exec(suite.T())
+	}
+
+	res, err := req(`{"amount": 10, "reason": "test"}`)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusCreated, res.Code)
+
+	_, err = req(`{"amount": 20, "reason": "test"}`)
+	assert.Error(suite.T(), err)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusConflict, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageIdempotencyKeyReused, httpErr.Message)
+}
+
 func (suite *OrderTestSuite) TestOrder_GetOrders_Ok() {
 	count := 5
 	items := make([]*billingpb.OrderViewPublic, 0, count)
@@ -348,6 +446,327 @@ func (suite *OrderTestSuite) TestOrder_GetOrders_Ok() {
 	assert.NotEmpty(suite.T(), res.Body.String())
 }
 
+func (suite *OrderTestSuite) TestOrder_GetOrders_Cursor_Ok() {
+	var requests []*billingpb.ListOrdersRequest
+
+	bill := &billMock.BillingService{}
+	bill.On("FindAllOrdersPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+		requests = append(requests, req)
+		return true
+	})).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item: &billingpb.ListOrdersPublicResponseItem{
+				Count: 5,
+				Items: []*billingpb.OrderViewPublic{},
+			},
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam("limit", "2").
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Contains(suite.T(), res.Header().Get(common.HeaderLink), `rel="next"`)
+
+	page := &ListOrdersPublicPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+	assert.NotEmpty(suite.T(), page.NextCursor)
+	assert.Empty(suite.T(), page.PrevCursor)
+
+	res, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam(common.QueryParameterNameCursor, page.NextCursor).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Contains(suite.T(), res.Header().Get(common.HeaderLink), `rel="prev"`)
+
+	assert.Len(suite.T(), requests, 2)
+	assert.EqualValues(suite.T(), 2, requests[1].Limit)
+	assert.EqualValues(suite.T(), 2, requests[1].Offset)
+	assert.NotEmpty(suite.T(), requests[0].ProjectDateTo)
+	assert.Equal(suite.T(), requests[0].ProjectDateTo, requests[1].ProjectDateTo)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrders_Cursor_RowsShifted() {
+	var requests []*billingpb.ListOrdersRequest
+	orders := func(ids ...string) []*billingpb.OrderViewPublic {
+		items := make([]*billingpb.OrderViewPublic, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, &billingpb.OrderViewPublic{Id: id})
+		}
+		return items
+	}
+	// the matcher runs for every expected call, the request is recorded once
+	matcher := mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+		if len(requests) == 0 || requests[len(requests)-1] != req {
+			requests = append(requests, req)
+		}
+		return true
+	})
+
+	bill := &billMock.BillingService{}
+	bill.On("FindAllOrdersPublic", mock2.Anything, matcher).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.ListOrdersPublicResponseItem{Count: 5, Items: orders("o5", "o4")},
+		}, nil).
+		Once()
+	// the order o6 is added to the head of the list after the first page
+	bill.On("FindAllOrdersPublic", mock2.Anything, matcher).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.ListOrdersPublicResponseItem{Count: 6, Items: orders("o5", "o4", "o3", "o2", "o1")},
+		}, nil).
+		Once()
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam("limit", "2").
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	page := &ListOrdersPublicPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+
+	res, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam(common.QueryParameterNameCursor, page.NextCursor).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	page = &ListOrdersPublicPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+	assert.Len(suite.T(), page.Items, 2)
+	assert.Equal(suite.T(), "o3", page.Items[0].Id)
+	assert.Equal(suite.T(), "o2", page.Items[1].Id)
+	assert.NotEmpty(suite.T(), page.NextCursor)
+	assert.NotEmpty(suite.T(), page.PrevCursor)
+
+	assert.Len(suite.T(), requests, 2)
+	assert.EqualValues(suite.T(), 1, requests[1].Offset)
+	assert.EqualValues(suite.T(), 5, requests[1].Limit)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrders_Cursor_Invalid() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam(common.QueryParameterNameCursor, "eyJwIjoiL2FkbWluL2FwaS92MS9vcmRlciJ9.c2lnbmF0dXJl").
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageCursorInvalid, httpErr.Message)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrders_Cursor_PrevPage_RowsShifted() {
+	var requests []*billingpb.ListOrdersRequest
+	orders := func(ids ...string) []*billingpb.OrderViewPublic {
+		items := make([]*billingpb.OrderViewPublic, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, &billingpb.OrderViewPublic{Id: id})
+		}
+		return items
+	}
+	// the matcher runs for every expected call, the request is recorded once
+	matcher := mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+		if len(requests) == 0 || requests[len(requests)-1] != req {
+			requests = append(requests, req)
+		}
+		return true
+	})
+
+	bill := &billMock.BillingService{}
+	bill.On("FindAllOrdersPublic", mock2.Anything, matcher).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.ListOrdersPublicResponseItem{Count: 5, Items: orders("o3", "o2")},
+		}, nil).
+		Once()
+	// the order o6 is added to the head of the list after the second page
+	bill.On("FindAllOrdersPublic", mock2.Anything, matcher).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.ListOrdersPublicResponseItem{Count: 6, Items: orders("o6", "o5", "o4", "o3", "o2")},
+		}, nil).
+		Once()
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam("limit", "2").
+		SetQueryParam("offset", "2").
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	page := &ListOrdersPublicPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+	assert.NotEmpty(suite.T(), page.PrevCursor)
+
+	res, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath+orderPath).
+		SetQueryParam(common.QueryParameterNameCursor, page.PrevCursor).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	page = &ListOrdersPublicPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+	assert.Len(suite.T(), page.Items, 2)
+	assert.Equal(suite.T(), "o5", page.Items[0].Id)
+	assert.Equal(suite.T(), "o4", page.Items[1].Id)
+	assert.NotEmpty(suite.T(), page.NextCursor)
+	assert.NotEmpty(suite.T(), page.PrevCursor)
+
+	assert.Len(suite.T(), requests, 2)
+	assert.EqualValues(suite.T(), 0, requests[1].Offset)
+	assert.EqualValues(suite.T(), 5, requests[1].Limit)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrders_Cursor_Rejected() {
+	path := common.AuthUserGroupPath + orderPath
+	cursors := map[string]*common.PageCursor{
+		"expired":      {Path: path, ExpireAt: time.Now().Add(-time.Minute).Unix()},
+		"another path": {Path: common.AuthUserGroupPath + orderRefundsPath, ExpireAt: time.Now().Add(time.Minute).Unix()},
+	}
+
+	for name, cursor := range cursors {
+		value, err := common.EncodePageCursor(&suite.router.cfg, cursor)
+		assert.NoError(suite.T(), err, name)
+
+		_, err = suite.caller.Builder().
+			Method(http.MethodGet).
+			Path(path).
+			SetQueryParam(common.QueryParameterNameCursor, value).
+			Init(test.ReqInitJSON()).
+			Exec(suite.T())
+
+		assert.Error(suite.T(), err, name)
+		httpErr, ok := err.(*echo.HTTPError)
+		assert.True(suite.T(), ok, name)
+		assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code, name)
+		assert.Equal(suite.T(), common.ErrorMessageCursorInvalid, httpErr.Message, name)
+	}
+}
+
+func (suite *OrderTestSuite) TestOrder_ListRefunds_Cursor_RowsShifted() {
+	var requests []*billingpb.ListRefundsRequest
+	orderId := uuid.New().String()
+	refunds := func(ids ...string) []*billingpb.Refund {
+		items := make([]*billingpb.Refund, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, &billingpb.Refund{Id: id})
+		}
+		return items
+	}
+	// the matcher runs for every expected call, the request is recorded once
+	matcher := mock2.MatchedBy(func(req *billingpb.ListRefundsRequest) bool {
+		if len(requests) == 0 || requests[len(requests)-1] != req {
+			requests = append(requests, req)
+		}
+		return true
+	})
+
+	bill := &billMock.BillingService{}
+	bill.On("ListRefunds", mock2.Anything, matcher).
+		Return(&billingpb.ListRefundsResponse{Count: 5, Items: refunds("r5", "r4")}, nil).
+		Once()
+	// the refund r6 is added to the head of the list after the first page
+	bill.On("ListRefunds", mock2.Anything, matcher).
+		Return(&billingpb.ListRefundsResponse{Count: 6, Items: refunds("r5", "r4", "r3", "r2", "r1")}, nil).
+		Once()
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", orderId).
+		Path(common.AuthUserGroupPath+orderRefundsPath).
+		SetQueryParam("limit", "2").
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Contains(suite.T(), res.Header().Get(common.HeaderLink), `rel="next"`)
+
+	page := &ListRefundsPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+	assert.NotEmpty(suite.T(), page.NextCursor)
+	assert.Empty(suite.T(), page.PrevCursor)
+
+	res, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", orderId).
+		Path(common.AuthUserGroupPath+orderRefundsPath).
+		SetQueryParam(common.QueryParameterNameCursor, page.NextCursor).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Contains(suite.T(), res.Header().Get(common.HeaderLink), `rel="prev"`)
+
+	page = &ListRefundsPage{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), page))
+	assert.Len(suite.T(), page.Items, 2)
+	assert.Equal(suite.T(), "r3", page.Items[0].Id)
+	assert.Equal(suite.T(), "r2", page.Items[1].Id)
+	assert.NotEmpty(suite.T(), page.NextCursor)
+	assert.NotEmpty(suite.T(), page.PrevCursor)
+
+	assert.Len(suite.T(), requests, 2)
+	assert.Equal(suite.T(), orderId, requests[1].OrderId)
+	assert.EqualValues(suite.T(), 1, requests[1].Offset)
+	assert.EqualValues(suite.T(), 5, requests[1].Limit)
+}
+
+func (suite *OrderTestSuite) TestOrder_ListRefunds_Cursor_AnotherOrder_Error() {
+	value, err := common.EncodePageCursor(&suite.router.cfg, &common.PageCursor{
+		Path:     common.AuthUserGroupPath + "/order/" + uuid.New().String() + "/refunds",
+		ExpireAt: time.Now().Add(time.Minute).Unix(),
+	})
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", uuid.New().String()).
+		Path(common.AuthUserGroupPath+orderRefundsPath).
+		SetQueryParam(common.QueryParameterNameCursor, value).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageCursorInvalid, httpErr.Message)
+}
+
 func (suite *OrderTestSuite) TestOrder_ListOrdersPublic_DateValidationError() {
 	bs := &billMock.BillingService{}
 	bs.On("FindAllOrdersPublic", mock2.Anything, mock2.Anything, mock2.Anything).
@@ -727,6 +1146,49 @@ func (suite *OrderTestSuite) TestOrder_GetOrderLogs_Ok() {
 	assert.NotEmpty(suite.T(), logs.Callback)
 	assert.NotNil(suite.T(), logs.Notify)
 	assert.NotEmpty(suite.T(), logs.Notify)
+	assert.False(suite.T(), logs.Incomplete)
+	assert.Zero(suite.T(), logs.Unparsed)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderLogs_Unparsed() {
+	cloudwatchMock := &mock.CloudWatchInterface{}
+	cloudwatchMock.On("FilterLogEventsWithContext", mock2.Anything, mock2.Anything).
+		Return(&cloudwatchlogs.FilterLogEventsOutput{
+			Events: []*cloudwatchlogs.FilteredLogEvent{
+				{Timestamp: aws.Int64(1586868621704), Message: aws.String(`{"msg":"/api/payments","response_status":200}`)},
+				{Timestamp: aws.Int64(1586868621704), Message: aws.String("plain text")},
+			},
+		}, nil)
+	suite.router.cloudWatch.instance = cloudwatchMock
+
+	item := new(billingpb.OrderViewPublic)
+	_ = faker.FakeData(item)
+
+	bill := &billMock.BillingService{}
+	bill.On("GetOrderPublic", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetOrderPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   item,
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.SystemUserGroupPath + orderGetLogsPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	logs := new(GetOrderLogsResponse)
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), logs))
+	assert.Len(suite.T(), logs.Create, 1)
+	assert.Len(suite.T(), logs.Callback, 1)
+	assert.Len(suite.T(), logs.Notify, 1)
+	assert.Equal(suite.T(), 3, logs.Unparsed)
+	assert.False(suite.T(), logs.Incomplete)
 }
 
 func (suite *OrderTestSuite) TestOrder_GetOrderLogs_GetOrderPublic_Error() {
@@ -838,6 +1300,7 @@ func (suite *OrderTestSuite) TestOrder_GetOrderLogs_CloudWatchLog_Error() {
 	assert.Nil(suite.T(), logs.Create)
 	assert.Nil(suite.T(), logs.Callback)
 	assert.Nil(suite.T(), logs.Notify)
+	assert.True(suite.T(), logs.Incomplete)
 }
 
 func (suite *OrderTestSuite) TestOrder_GetOrderPrivate_Ok() {
@@ -1108,3 +1571,427 @@ func (suite *OrderTestSuite) TestOrder_ListOrdersS2s_Billing_FindAllOrders_Resul
 	assert.Equal(suite.T(), "000", message.Code)
 	assert.Equal(suite.T(), "TestOrder_ListOrdersS2s_Billing_FindAllOrders_Result_Error", message.Message)
 }
+
+func (suite *OrderTestSuite) TestOrder_GetOrderS2s_Ok() {
+	item := new(billingpb.OrderViewPublic)
+	_ = faker.FakeData(item)
+
+	bill := &billMock.BillingService{}
+	bill.On("GetOrderPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.GetOrderRequest) bool {
+		return req.MerchantId == "ffffffffffffffffffffffff" && req.OrderId == "ace2fc5c-b8c2-4424-96e8-5b631a73b88a"
+	})).
+		Return(&billingpb.GetOrderPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   item,
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderIdPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.NotEmpty(suite.T(), res.Body.String())
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderStatusS2s_Ok() {
+	item := new(billingpb.OrderViewPublic)
+	_ = faker.FakeData(item)
+
+	bill := &billMock.BillingService{}
+	bill.On("GetOrderPublic", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetOrderPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   item,
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderStatusPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	status := &OrderStatusResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), status))
+	assert.Equal(suite.T(), item.Uuid, status.Id)
+	assert.Equal(suite.T(), item.Status, status.Status)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderByInvoiceS2s_Ok() {
+	item := new(billingpb.OrderViewPublic)
+	_ = faker.FakeData(item)
+
+	bill := &billMock.BillingService{}
+	bill.On("FindAllOrdersPublic", mock2.Anything, mock2.MatchedBy(func(req *billingpb.ListOrdersRequest) bool {
+		return req.MerchantId == "ffffffffffffffffffffffff" && req.InvoiceId == "invoice_1" && req.Limit == 1
+	})).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item: &billingpb.ListOrdersPublicResponseItem{
+				Count: 1,
+				Items: []*billingpb.OrderViewPublic{item},
+			},
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterInvoiceId, "invoice_1").
+		Path(common.MerchantS2SGroupPath + orderInvoicePath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	order := &billingpb.OrderViewPublic{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), order))
+	assert.Equal(suite.T(), item.Uuid, order.Uuid)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderByInvoiceS2s_NotFound() {
+	bill := &billMock.BillingService{}
+	bill.On("FindAllOrdersPublic", mock2.Anything, mock2.Anything).
+		Return(&billingpb.ListOrdersPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item: &billingpb.ListOrdersPublicResponseItem{
+				Items: []*billingpb.OrderViewPublic{},
+			},
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterInvoiceId, "invoice_1").
+		Path(common.MerchantS2SGroupPath + orderInvoicePath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusNotFound, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageOrdersNotFound, httpErr.Message)
+}
+
+func (suite *OrderTestSuite) TestOrder_ListRefundsS2s_Ok() {
+	suite.user.ProjectId = "5dbac6a9120a810001a8fe41"
+	bill := suite.mockRefundableOrder()
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.NotEmpty(suite.T(), res.Body.String())
+	bill.AssertCalled(suite.T(), "ListRefunds", mock2.Anything, mock2.MatchedBy(func(req *billingpb.ListRefundsRequest) bool {
+		return req.OrderId == "ace2fc5c-b8c2-4424-96e8-5b631a73b88a"
+	}))
+}
+
+func (suite *OrderTestSuite) TestOrder_ListRefundsS2s_AnotherProject_NotFound() {
+	suite.user.ProjectId = "5dbac6a9120a810001a8fe42"
+	bill := suite.mockRefundableOrder()
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusNotFound, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageOrdersNotFound, httpErr.Message)
+	bill.AssertNotCalled(suite.T(), "ListRefunds", mock2.Anything, mock2.Anything)
+}
+
+func (suite *OrderTestSuite) mockRefundableOrder() *billMock.BillingService {
+	bill := &billMock.BillingService{}
+	bill.On("GetOrderPublic", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetOrderPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item: &billingpb.OrderViewPublic{
+				Uuid:               "ace2fc5c-b8c2-4424-96e8-5b631a73b88a",
+				TotalPaymentAmount: 100,
+				Project:            &billingpb.ProjectOrder{Id: "5dbac6a9120a810001a8fe41"},
+			},
+		}, nil)
+	bill.On("ListRefunds", mock2.Anything, mock2.Anything).
+		Return(&billingpb.ListRefundsResponse{
+			Count: 2,
+			Items: []*billingpb.Refund{
+				{Amount: 30.1, Status: billingpb.RefundStatusCompleted},
+				{Amount: 50, Status: billingpb.RefundStatusRejected},
+			},
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+
+	return bill
+}
+
+func (suite *OrderTestSuite) TestOrder_CreateRefundS2s_Ok() {
+	bill := suite.mockRefundableOrder()
+	bill.On("CreateRefund", mock2.Anything, mock2.MatchedBy(func(req *billingpb.CreateRefundRequest) bool {
+		return req.OrderId == "ace2fc5c-b8c2-4424-96e8-5b631a73b88a" && req.Amount == 69.9 &&
+			req.Reason == "duplicate"
+	})).
+		Return(&billingpb.CreateRefundResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item:   &billingpb.Refund{Id: "5dbac6a9120a810001a8fe41", Amount: 69.9},
+		}, nil)
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(common.HeaderIdempotencyKey, "refund-1")
+		}).
+		BodyString(`{"amount": 69.9, "reason": "duplicate", "merchant_reference": "R-1"}`).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusCreated, res.Code)
+
+	refund := &RefundS2sResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), refund))
+	assert.Equal(suite.T(), "R-1", refund.MerchantReference)
+
+	references, err := suite.router.refunds.References([]string{"5dbac6a9120a810001a8fe41"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]string{"5dbac6a9120a810001a8fe41": "R-1"}, references)
+
+	// the order is unlocked after the refund is created
+	locked, err := suite.router.refunds.Lock("ace2fc5c-b8c2-4424-96e8-5b631a73b88a", time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), locked)
+}
+
+func (suite *OrderTestSuite) TestOrder_CreateRefundS2s_InProgress() {
+	bill := suite.mockRefundableOrder()
+
+	locked, err := suite.router.refunds.Lock("ace2fc5c-b8c2-4424-96e8-5b631a73b88a", time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), locked)
+
+	_, err = suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(common.HeaderIdempotencyKey, "refund-3")
+		}).
+		BodyString(`{"amount": 10, "reason": "other"}`).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusConflict, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageRefundInProgress, httpErr.Message)
+	bill.AssertNotCalled(suite.T(), "CreateRefund", mock2.Anything, mock2.Anything)
+}
+
+func (suite *OrderTestSuite) TestOrder_CreateRefundS2s_AmountExceeded() {
+	suite.mockRefundableOrder()
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(common.HeaderIdempotencyKey, "refund-2")
+		}).
+		BodyString(`{"amount": 70, "reason": "other"}`).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageRefundAmountExceeded, httpErr.Message)
+}
+
+func (suite *OrderTestSuite) TestOrder_CreateRefundS2s_IdempotencyKeyRequired() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodPost).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.MerchantS2SGroupPath + orderRefundsPath).
+		Init(test.ReqInitJSON()).
+		BodyString(`{"amount": 10, "reason": "other"}`).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageIdempotencyKeyRequired, httpErr.Message)
+}
+
+func (suite *OrderTestSuite) mockTimelineOrder() {
+	now := time.Now()
+	createdAt, _ := ptypes.TimestampProto(now.Add(-time.Hour))
+	transactionDate, _ := ptypes.TimestampProto(now.Add(-50 * time.Minute))
+	refundedAt, _ := ptypes.TimestampProto(now.Add(-30 * time.Minute))
+
+	bill := &billMock.BillingService{}
+	bill.On("GetOrderPublic", mock2.Anything, mock2.Anything).
+		Return(&billingpb.GetOrderPublicResponse{
+			Status: billingpb.ResponseStatusOk,
+			Item: &billingpb.OrderViewPublic{
+				Uuid:            "ace2fc5c-b8c2-4424-96e8-5b631a73b88a",
+				Status:          "refunded",
+				CreatedAt:       createdAt,
+				TransactionDate: transactionDate,
+			},
+		}, nil)
+	bill.On("ListRefunds", mock2.Anything, mock2.Anything).
+		Return(&billingpb.ListRefundsResponse{
+			Count: 1,
+			Items: []*billingpb.Refund{
+				{Amount: 10, Currency: "USD", Status: billingpb.RefundStatusCompleted, CreatedAt: refundedAt},
+			},
+		}, nil)
+	suite.router.dispatch.Services.Billing = bill
+}
+
+func (suite *OrderTestSuite) getOrderTimeline(path string) *OrderTimelineResponse {
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(path).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	timeline := &OrderTimelineResponse{}
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), timeline))
+
+	for i := 1; i < len(timeline.Events); i++ {
+		assert.False(suite.T(), timeline.Events[i].Date.Before(timeline.Events[i-1].Date))
+	}
+
+	return timeline
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderTimelinePrivate_Ok() {
+	suite.mockTimelineOrder()
+	timeline := suite.getOrderTimeline(common.SystemUserGroupPath + orderTimelinePath)
+
+	assert.False(suite.T(), timeline.Incomplete)
+	assert.False(suite.T(), timeline.Truncated)
+	assert.True(suite.T(), timeline.PartialStatusHistory)
+
+	types := make(map[string]int)
+
+	for _, event := range timeline.Events {
+		types[event.Type]++
+
+		if event.Type == OrderTimelineEventPaymentRequest || event.Type == OrderTimelineEventCallback ||
+			event.Type == OrderTimelineEventNotification {
+			assert.NotNil(suite.T(), event.Log)
+		}
+	}
+
+	assert.Equal(suite.T(), map[string]int{
+		OrderTimelineEventStatus:         2,
+		OrderTimelineEventRefund:         1,
+		OrderTimelineEventPaymentRequest: 1,
+		OrderTimelineEventCallback:       1,
+		OrderTimelineEventNotification:   1,
+	}, types)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderTimelinePublic_Redacted() {
+	suite.mockTimelineOrder()
+	timeline := suite.getOrderTimeline(common.AuthUserGroupPath + orderTimelinePath)
+
+	assert.Len(suite.T(), timeline.Events, 5)
+
+	for _, event := range timeline.Events {
+		assert.NotEqual(suite.T(), OrderTimelineEventPaymentRequest, event.Type)
+		assert.Nil(suite.T(), event.Log)
+	}
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderTimeline_CloudWatchPages() {
+	suite.mockTimelineOrder()
+
+	event := &cloudwatchlogs.FilteredLogEvent{
+		Timestamp: aws.Int64(aws.TimeUnixMilli(time.Now().Add(-40 * time.Minute))),
+		Message:   aws.String(`{"msg":"/api/payments","response_status":200}`),
+	}
+	cloudwatchMock := &mock.CloudWatchInterface{}
+	cloudwatchMock.On("FilterLogEventsWithContext", mock2.Anything, mock2.MatchedBy(func(input *cloudwatchlogs.FilterLogEventsInput) bool {
+		return input.NextToken == nil
+	})).
+		Return(&cloudwatchlogs.FilterLogEventsOutput{
+			Events:    []*cloudwatchlogs.FilteredLogEvent{event, {Timestamp: event.Timestamp, Message: aws.String("plain text")}},
+			NextToken: aws.String("next"),
+		}, nil)
+	cloudwatchMock.On("FilterLogEventsWithContext", mock2.Anything, mock2.Anything).
+		Return(&cloudwatchlogs.FilterLogEventsOutput{Events: []*cloudwatchlogs.FilteredLogEvent{event}}, nil)
+	suite.router.cloudWatch.instance = cloudwatchMock
+
+	timeline := suite.getOrderTimeline(common.SystemUserGroupPath + orderTimelinePath)
+
+	// 3 order events and 2 parsed log events of 3 log groups
+	assert.Len(suite.T(), timeline.Events, 9)
+	assert.Equal(suite.T(), 3, timeline.Unparsed)
+	cloudwatchMock.AssertNumberOfCalls(suite.T(), "FilterLogEventsWithContext", 6)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderTimeline_MaxEventsNotPositive() {
+	suite.mockTimelineOrder()
+	suite.router.cfg.OrderTimelineMaxEvents = 0
+
+	cloudwatchMock := &mock.CloudWatchInterface{}
+	cloudwatchMock.On("FilterLogEventsWithContext", mock2.Anything, mock2.MatchedBy(func(input *cloudwatchlogs.FilterLogEventsInput) bool {
+		return aws.Int64Value(input.Limit) == orderTimelineMaxEventsDefault
+	})).
+		Return(&cloudwatchlogs.FilterLogEventsOutput{}, nil)
+	suite.router.cloudWatch.instance = cloudwatchMock
+
+	timeline := suite.getOrderTimeline(common.SystemUserGroupPath + orderTimelinePath)
+
+	assert.False(suite.T(), timeline.Incomplete)
+	assert.Len(suite.T(), timeline.Events, 3)
+	cloudwatchMock.AssertNumberOfCalls(suite.T(), "FilterLogEventsWithContext", 3)
+}
+
+func (suite *OrderTestSuite) TestOrder_GetOrderTimeline_InvalidWindow() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":order_id", "ace2fc5c-b8c2-4424-96e8-5b631a73b88a").
+		Path(common.SystemUserGroupPath+orderTimelinePath).
+		SetQueryParam(orderTimelineQueryWindow, "10000h").
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorRequestParamsIncorrect, httpErr.Message)
+}
@@ -79,13 +79,12 @@ func (h *PayLinkRoute) getPaylinksList(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylinks(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinks", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinks")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -114,13 +113,12 @@ func (h *PayLinkRoute) getPaylink(ctx echo.Context) error {
 
 	err := h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylink(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylink", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylink")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -157,8 +155,7 @@ func (h *PayLinkRoute) getPaylinkUrl(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetPaylinkURL(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkURL", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinkURL")
 	}
 
 	if res.Status != http.StatusOK {
@@ -189,13 +186,12 @@ func (h *PayLinkRoute) deletePaylink(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeletePaylink(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeletePaylink", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "DeletePaylink")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -248,13 +244,12 @@ func (h *PayLinkRoute) createOrUpdatePaylink(ctx echo.Context, paylinkId string)
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.CreateOrUpdatePaylink(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "CreateOrUpdatePaylink", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "CreateOrUpdatePaylink")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -285,13 +280,12 @@ func (h *PayLinkRoute) getPaylinkStatSummary(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylinkStatTotal(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkStatTotal", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinkStatTotal")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -322,13 +316,12 @@ func (h *PayLinkRoute) getPaylinkStatByCountry(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylinkStatByCountry(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkStatByCountry", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinkStatByCountry")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -359,13 +352,12 @@ func (h *PayLinkRoute) getPaylinkStatByReferrer(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylinkStatByReferrer(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkStatByReferrer", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinkStatByReferrer")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -396,13 +388,12 @@ func (h *PayLinkRoute) getPaylinkStatByDate(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylinkStatByDate(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkStatByDate", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinkStatByDate")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -433,13 +424,12 @@ func (h *PayLinkRoute) getPaylinkStatByUtm(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaylinkStatByUtm(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkStatByUtm", req)
-		return ctx.Render(http.StatusBadRequest, errorTemplateName, map[string]interface{}{})
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPaylinkStatByUtm")
 	}
 	if res.Status != http.StatusOK {
 		return echo.NewHTTPError(int(res.Status), res.Message)
@@ -454,12 +444,13 @@ func (h *PayLinkRoute) getPaylinkStatByUtm(ctx echo.Context) error {
 // @tag Payment link
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.TransactionsPaginate Returns the list of payment link's transactions
+// @success 200 {object} TransactionsPage Returns the list of payment link's transactions
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param id path {string} true The unique identifier for the payment link.
 // @param limit query {integer} false The number of transactions returned in one page. Default value is 100.
 // @param offset query {integer} false The ranking number of the first item on the page.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /admin/api/v1/paylinks/{id}/transactions [get]
 func (h *PayLinkRoute) getPaylinkTransactions(ctx echo.Context) error {
 	req := &billingpb.GetPaylinkTransactionsRequest{}
@@ -470,12 +461,15 @@ func (h *PayLinkRoute) getPaylinkTransactions(ctx echo.Context) error {
 
 	err := h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
+	seek := common.NewPageSeek(ctx, &h.cfg, req.Limit, req.Offset)
+	req.Limit, req.Offset = seek.Limit, seek.Offset
+
 	res, err := h.dispatch.Services.Billing.GetPaylinkTransactions(ctx.Request().Context(), req)
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaylinkTransactions", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetPaylinkTransactions", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -483,5 +477,14 @@ func (h *PayLinkRoute) getPaylinkTransactions(ctx echo.Context) error {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
-	return ctx.JSON(http.StatusOK, res.Data)
+	items := res.Data.GetItems()
+	start, end, page := seek.Page(ctx, &h.cfg, res.Data.GetCount(), len(items), func(i int) string {
+		return items[i].Id
+	})
+
+	if res.Data != nil {
+		res.Data.Items = items[start:end]
+	}
+
+	return ctx.JSON(http.StatusOK, &TransactionsPage{res.Data, page})
 }
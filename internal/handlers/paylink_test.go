@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/globalsign/mgo/bson"
+	"github.com/labstack/echo/v4"
 	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
 	"github.com/paysuper/paysuper-management-api/internal/mock"
 	"github.com/paysuper/paysuper-management-api/internal/test"
+	billMock "github.com/paysuper/paysuper-proto/go/billingpb/mocks"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"net/url"
@@ -237,3 +242,116 @@ func (suite *PaylinkTestSuite) TestPaylink_getPaylinkTransactions_Ok() {
 		assert.NotEmpty(suite.T(), res.Body.String())
 	}
 }
+
+func (suite *PaylinkTestSuite) TestPaylink_getPaylink_BillingServerError_JsonEnvelope() {
+	billingService := &billMock.BillingService{}
+	billingService.On("GetPaylink", mock2.Anything, mock2.Anything).Return(nil, errors.New("some error"))
+	suite.router.dispatch.Services.Billing = billingService
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterId, bson.NewObjectId().Hex()).
+		Path(common.AuthUserGroupPath + paylinksIdPath).
+		Init(test.ReqInitJSON()).
+		Exec(suite.T())
+
+	if assert.Error(suite.T(), err) {
+		assert.Equal(suite.T(), http.StatusInternalServerError, res.Code)
+		assert.Contains(suite.T(), res.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+
+		envelope := &common.ErrorEnvelope{}
+		assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), envelope))
+		assert.Equal(suite.T(), common.ErrorInternal.Code, envelope.Code)
+		assert.Equal(suite.T(), common.ErrorInternal.Message, envelope.Message)
+	}
+}
+
+func (suite *PaylinkTestSuite) TestPaylink_getPaylink_BillingServerError_BrowserHtml() {
+	billingService := &billMock.BillingService{}
+	billingService.On("GetPaylink", mock2.Anything, mock2.Anything).Return(nil, errors.New("some error"))
+	suite.router.dispatch.Services.Billing = billingService
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterId, bson.NewObjectId().Hex()).
+		Path(common.AuthUserGroupPath + paylinksIdPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderAccept, "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+		}).
+		Exec(suite.T())
+
+	if assert.Error(suite.T(), err) {
+		assert.Equal(suite.T(), http.StatusInternalServerError, res.Code)
+		assert.Contains(suite.T(), res.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+	}
+}
+
+func (suite *PaylinkTestSuite) TestPaylink_getPaylink_BillingServerError_LocalizedMessage() {
+	billingService := &billMock.BillingService{}
+	billingService.On("GetPaylink", mock2.Anything, mock2.Anything).Return(nil, errors.New("some error"))
+	suite.router.dispatch.Services.Billing = billingService
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterId, bson.NewObjectId().Hex()).
+		Path(common.AuthUserGroupPath + paylinksIdPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			request.Header.Set(common.HeaderAcceptLanguage, "de-DE;q=0.8, ru-RU, en;q=0.5")
+		}).
+		Exec(suite.T())
+
+	if assert.Error(suite.T(), err) {
+		assert.Equal(suite.T(), http.StatusInternalServerError, res.Code)
+		assert.Equal(suite.T(), common.LocaleRu, res.Header().Get(common.HeaderContentLanguage))
+
+		envelope := &common.ErrorEnvelope{}
+		assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), envelope))
+		assert.Equal(suite.T(), common.ErrorInternal.Code, envelope.Code)
+		assert.Equal(suite.T(), common.LocalizeMessage(common.LocaleRu, common.ErrorInternal.Code, common.ErrorInternal.Message), envelope.Message)
+		assert.NotEqual(suite.T(), common.ErrorInternal.Message, envelope.Message)
+	}
+}
+
+func (suite *PaylinkTestSuite) TestPaylink_getPaylink_RequestId_Echoed() {
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterId, bson.NewObjectId().Hex()).
+		Path(common.AuthUserGroupPath + paylinksIdPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			request.Header.Set(echo.HeaderXRequestID, "merchant-request-1")
+		}).
+		Exec(suite.T())
+
+	if assert.NoError(suite.T(), err) {
+		assert.Equal(suite.T(), http.StatusOK, res.Code)
+		assert.Equal(suite.T(), "merchant-request-1", res.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func (suite *PaylinkTestSuite) TestPaylink_getPaylink_RequestId_GeneratedInErrorEnvelope() {
+	billingService := &billMock.BillingService{}
+	billingService.On("GetPaylink", mock2.Anything, mock2.Anything).Return(nil, errors.New("some error"))
+	suite.router.dispatch.Services.Billing = billingService
+
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Params(":"+common.RequestParameterId, bson.NewObjectId().Hex()).
+		Path(common.AuthUserGroupPath + paylinksIdPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			request.Header.Set(echo.HeaderXRequestID, "invalid request id")
+		}).
+		Exec(suite.T())
+
+	if assert.Error(suite.T(), err) {
+		id := res.Header().Get(echo.HeaderXRequestID)
+		assert.NotEmpty(suite.T(), id)
+		assert.NotEqual(suite.T(), "invalid request id", id)
+
+		envelope := &common.ErrorEnvelope{}
+		assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), envelope))
+		assert.Equal(suite.T(), id, envelope.RequestId)
+	}
+}
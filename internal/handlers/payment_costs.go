@@ -51,21 +51,89 @@ func (h *PaymentCostRoute) Route(groups *common.Groups) {
 	groups.SystemUser.GET(paymentCostsMoneyBackSystemPath, h.getMoneyBackCostSystem)
 	groups.SystemUser.GET(paymentCostsMoneyBackMerchantPath, h.getMoneyBackCostMerchant)
 
-	groups.SystemUser.DELETE(paymentCostsChannelSystemIdPath, h.deletePaymentChannelCostSystem)
+	// the merchant delete paths carry the cost identifier in merchant_id, their audit records stay without the old payload
+	channelSystem := common.AuditSnapshot(h.channelCostSystemSnapshot)
+	channelMerchant := common.AuditSnapshot(h.channelCostMerchantSnapshot)
+	moneyBackSystem := common.AuditSnapshot(h.moneyBackCostSystemSnapshot)
+	moneyBackMerchant := common.AuditSnapshot(h.moneyBackCostMerchantSnapshot)
+
+	groups.SystemUser.DELETE(paymentCostsChannelSystemIdPath, h.deletePaymentChannelCostSystem, channelSystem)
 	groups.SystemUser.DELETE(paymentCostsChannelMerchantPath, h.deletePaymentChannelCostMerchant)
-	groups.SystemUser.DELETE(paymentCostsMoneyBackSystemIdPath, h.deleteMoneyBackCostSystem)
+	groups.SystemUser.DELETE(paymentCostsMoneyBackSystemIdPath, h.deleteMoneyBackCostSystem, moneyBackSystem)
 	groups.SystemUser.DELETE(paymentCostsMoneyBackMerchantPath, h.deleteMoneyBackCostMerchant)
 
-	groups.SystemUser.POST(paymentCostsChannelSystemPath, h.setPaymentChannelCostSystem)
-	groups.SystemUser.POST(paymentCostsChannelMerchantPath, h.setPaymentChannelCostMerchant)
-	groups.SystemUser.POST(paymentCostsMoneyBackSystemPath, h.setMoneyBackCostSystem)
-	groups.SystemUser.POST(paymentCostsMoneyBackMerchantPath, h.setMoneyBackCostMerchant)
+	groups.SystemUser.POST(paymentCostsChannelSystemPath, h.setPaymentChannelCostSystem, channelSystem)
+	groups.SystemUser.POST(paymentCostsChannelMerchantPath, h.setPaymentChannelCostMerchant, channelMerchant)
+	groups.SystemUser.POST(paymentCostsMoneyBackSystemPath, h.setMoneyBackCostSystem, moneyBackSystem)
+	groups.SystemUser.POST(paymentCostsMoneyBackMerchantPath, h.setMoneyBackCostMerchant, moneyBackMerchant)
+
+	groups.SystemUser.PUT(paymentCostsChannelSystemIdPath, h.setPaymentChannelCostSystem, channelSystem)
+	groups.SystemUser.PUT(paymentCostsChannelMerchantIdsPath, h.setPaymentChannelCostMerchant, channelMerchant)
+	groups.SystemUser.PUT(paymentCostsChannelMerchantAllPath, h.setAllPaymentChannelCostMerchant, channelMerchant)
+	groups.SystemUser.PUT(paymentCostsMoneyBackSystemIdPath, h.setMoneyBackCostSystem, moneyBackSystem)
+	groups.SystemUser.PUT(paymentCostsMoneyBackMerchantIdsPath, h.setMoneyBackCostMerchant, moneyBackMerchant)
+}
+
+// channelCostSystemSnapshot reads the system costs of payments before the change for the audit record
+func (h *PaymentCostRoute) channelCostSystemSnapshot(ctx echo.Context) (interface{}, error) {
+	res, err := h.dispatch.Services.Billing.GetAllPaymentChannelCostSystem(ctx.Request().Context(), &billingpb.EmptyRequest{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != http.StatusOK {
+		return nil, res.Message
+	}
+
+	return res.Item, nil
+}
+
+// channelCostMerchantSnapshot reads the merchant costs of payments before the change for the audit record
+func (h *PaymentCostRoute) channelCostMerchantSnapshot(ctx echo.Context) (interface{}, error) {
+	req := &billingpb.PaymentChannelCostMerchantListRequest{MerchantId: ctx.Param(common.RequestParameterMerchantId)}
+	res, err := h.dispatch.Services.Billing.GetAllPaymentChannelCostMerchant(ctx.Request().Context(), req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != http.StatusOK {
+		return nil, res.Message
+	}
+
+	return res.Item, nil
+}
+
+// moneyBackCostSystemSnapshot reads the system costs of money back before the change for the audit record
+func (h *PaymentCostRoute) moneyBackCostSystemSnapshot(ctx echo.Context) (interface{}, error) {
+	res, err := h.dispatch.Services.Billing.GetAllMoneyBackCostSystem(ctx.Request().Context(), &billingpb.EmptyRequest{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != http.StatusOK {
+		return nil, res.Message
+	}
+
+	return res.Item, nil
+}
+
+// moneyBackCostMerchantSnapshot reads the merchant costs of money back before the change for the audit record
+func (h *PaymentCostRoute) moneyBackCostMerchantSnapshot(ctx echo.Context) (interface{}, error) {
+	req := &billingpb.MoneyBackCostMerchantListRequest{MerchantId: ctx.Param(common.RequestParameterMerchantId)}
+	res, err := h.dispatch.Services.Billing.GetAllMoneyBackCostMerchant(ctx.Request().Context(), req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != http.StatusOK {
+		return nil, res.Message
+	}
 
-	groups.SystemUser.PUT(paymentCostsChannelSystemIdPath, h.setPaymentChannelCostSystem)
-	groups.SystemUser.PUT(paymentCostsChannelMerchantIdsPath, h.setPaymentChannelCostMerchant)
-	groups.SystemUser.PUT(paymentCostsChannelMerchantAllPath, h.setAllPaymentChannelCostMerchant)
-	groups.SystemUser.PUT(paymentCostsMoneyBackSystemIdPath, h.setMoneyBackCostSystem)
-	groups.SystemUser.PUT(paymentCostsMoneyBackMerchantIdsPath, h.setMoneyBackCostMerchant)
+	return res.Item, nil
 }
 
 // @summary Get system costs for payment operations
@@ -93,13 +161,13 @@ func (h *PaymentCostRoute) getPaymentChannelCostSystem(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaymentChannelCostSystem(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaymentChannelCostSystem", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetPaymentChannelCostSystem", req)
 
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
@@ -140,13 +208,13 @@ func (h *PaymentCostRoute) getPaymentChannelCostMerchant(ctx echo.Context) error
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetPaymentChannelCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetPaymentChannelCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetPaymentChannelCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -186,13 +254,13 @@ func (h *PaymentCostRoute) getMoneyBackCostSystem(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetMoneyBackCostSystem(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMoneyBackCostSystem", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMoneyBackCostSystem", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -234,13 +302,13 @@ func (h *PaymentCostRoute) getMoneyBackCostMerchant(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetMoneyBackCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetMoneyBackCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetMoneyBackCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -267,13 +335,13 @@ func (h *PaymentCostRoute) deletePaymentChannelCostSystem(ctx echo.Context) erro
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeletePaymentChannelCostSystem(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeletePaymentChannelCostSystem", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "DeletePaymentChannelCostSystem", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -300,13 +368,13 @@ func (h *PaymentCostRoute) deletePaymentChannelCostMerchant(ctx echo.Context) er
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeletePaymentChannelCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeletePaymentChannelCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "DeletePaymentChannelCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -333,13 +401,13 @@ func (h *PaymentCostRoute) deleteMoneyBackCostSystem(ctx echo.Context) error {
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeleteMoneyBackCostSystem(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeleteMoneyBackCostSystem", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "DeleteMoneyBackCostSystem", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -366,13 +434,13 @@ func (h *PaymentCostRoute) deleteMoneyBackCostMerchant(ctx echo.Context) error {
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.DeleteMoneyBackCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeleteMoneyBackCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "DeleteMoneyBackCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -422,13 +490,13 @@ func (h *PaymentCostRoute) setPaymentChannelCostSystem(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetPaymentChannelCostSystem(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetPaymentChannelCostSystem", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetPaymentChannelCostSystem", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -482,13 +550,13 @@ func (h *PaymentCostRoute) setPaymentChannelCostMerchant(ctx echo.Context) error
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetPaymentChannelCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetPaymentChannelCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetPaymentChannelCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -528,13 +596,13 @@ func (h *PaymentCostRoute) setAllPaymentChannelCostMerchant(ctx echo.Context) er
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetAllPaymentChannelCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetAllPaymentChannelCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetAllPaymentChannelCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -584,13 +652,13 @@ func (h *PaymentCostRoute) setMoneyBackCostSystem(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetMoneyBackCostSystem(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetMoneyBackCostSystem", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetMoneyBackCostSystem", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -644,13 +712,13 @@ func (h *PaymentCostRoute) setMoneyBackCostMerchant(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.SetMoneyBackCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SetMoneyBackCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SetMoneyBackCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -675,7 +743,7 @@ func (h *PaymentCostRoute) getAllPaymentChannelCostSystem(ctx echo.Context) erro
 	res, err := h.dispatch.Services.Billing.GetAllPaymentChannelCostSystem(ctx.Request().Context(), &billingpb.EmptyRequest{})
 
 	if err != nil {
-		h.L().Error(billingpb.ErrorGrpcServiceCallFailed, logger.PairArgs("err", err.Error(), common.ErrorFieldService, billingpb.ServiceName, common.ErrorFieldMethod, "GetAllPaymentChannelCostSystem"))
+		common.RequestLogger(ctx, h.L()).Error(billingpb.ErrorGrpcServiceCallFailed, logger.PairArgs("err", err.Error(), common.ErrorFieldService, billingpb.ServiceName, common.ErrorFieldMethod, "GetAllPaymentChannelCostSystem"))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -702,13 +770,13 @@ func (h *PaymentCostRoute) getAllPaymentChannelCostMerchant(ctx echo.Context) er
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetAllPaymentChannelCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetAllPaymentChannelCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetAllPaymentChannelCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -733,7 +801,7 @@ func (h *PaymentCostRoute) getAllMoneyBackCostSystem(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetAllMoneyBackCostSystem(ctx.Request().Context(), &billingpb.EmptyRequest{})
 
 	if err != nil {
-		h.L().Error(billingpb.ErrorGrpcServiceCallFailed, logger.PairArgs("err", err.Error(), common.ErrorFieldService, billingpb.ServiceName, common.ErrorFieldMethod, "GetAllMoneyBackCostSystem"))
+		common.RequestLogger(ctx, h.L()).Error(billingpb.ErrorGrpcServiceCallFailed, logger.PairArgs("err", err.Error(), common.ErrorFieldService, billingpb.ServiceName, common.ErrorFieldMethod, "GetAllMoneyBackCostSystem"))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -760,13 +828,13 @@ func (h *PaymentCostRoute) getAllMoneyBackCostMerchant(ctx echo.Context) error {
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetAllMoneyBackCostMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetAllMoneyBackCostMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetAllMoneyBackCostMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
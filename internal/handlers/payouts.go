@@ -87,7 +87,7 @@ func (h *PayoutDocumentsRoute) getPayoutDocumentsList(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Billing.GetPayoutDocuments(ctx.Request().Context(), req)
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetPayoutDocuments")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPayoutDocuments")
 	}
 
 	if res.Status != http.StatusOK {
@@ -130,7 +130,7 @@ func (h *PayoutDocumentsRoute) getPayoutDocument(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetPayoutDocument(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetPayoutDocument")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPayoutDocument")
 	}
 
 	if res.Status != http.StatusOK {
@@ -205,12 +205,12 @@ func (h *PayoutDocumentsRoute) createPayoutDocument(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.CreatePayoutDocument(ctx.Request().Context(), req)
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "CreatePayoutDocument")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "CreatePayoutDocument")
 	}
 
 	if res.Status != http.StatusOK {
@@ -242,7 +242,7 @@ func (h *PayoutDocumentsRoute) updatePayoutDocument(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Billing.UpdatePayoutDocument(ctx.Request().Context(), req)
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "UpdatePayoutDocument")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "UpdatePayoutDocument")
 	}
 
 	if res.Status != http.StatusOK {
@@ -286,7 +286,7 @@ func (h *PayoutDocumentsRoute) getPayoutRoyaltyReports(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetPayoutDocumentRoyaltyReports(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetPayoutDocumentRoyaltyReports")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetPayoutDocumentRoyaltyReports")
 	}
 
 	if res.Status != http.StatusOK {
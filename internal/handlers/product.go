@@ -89,7 +89,7 @@ func (h *ProductRoute) getProductsList(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Billing.ListProducts(ctx.Request().Context(), req)
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ListProducts")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ListProducts")
 	}
 	return ctx.JSON(http.StatusOK, res)
 }
@@ -128,7 +128,7 @@ func (h *ProductRoute) getProduct(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetProduct(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetProduct")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetProduct")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -163,7 +163,7 @@ func (h *ProductRoute) deleteProduct(ctx echo.Context) error {
 	_, err := h.dispatch.Services.Billing.DeleteProduct(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "DeleteProduct")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "DeleteProduct")
 	}
 
 	return ctx.NoContent(http.StatusNoContent)
@@ -211,7 +211,7 @@ func (h *ProductRoute) createOrUpdateProduct(ctx echo.Context, binder echo.Binde
 	res, err := h.dispatch.Services.Billing.CreateOrUpdateProduct(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "CreateOrUpdateProduct")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "CreateOrUpdateProduct")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -239,7 +239,7 @@ func (h *ProductRoute) getProductPrices(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetProductPrices(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetProductPrices")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetProductPrices")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -268,7 +268,7 @@ func (h *ProductRoute) updateProductPrices(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.UpdateProductPrices(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "UpdateProductPrices")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "UpdateProductPrices")
 	}
 
 	return ctx.JSON(http.StatusOK, res)
@@ -8,13 +8,12 @@ import (
 	"gopkg.in/go-playground/validator.v9"
 )
 
-func ProviderHandlers(initial config.Initial, srv common.Services, validator *validator.Validate, set provider.AwareSet, cfg *common.Config) (common.Handlers, func(), error) {
+func ProviderHandlers(initial config.Initial, srv common.Services, validator *validator.Validate, set provider.AwareSet, cfg *common.Config, apiKeys common.ApiKeyStore, authCache common.AuthCache, sessions common.SessionStore, audit common.AuditStore, approvals common.ApprovalStore, bulkRefunds common.BulkRefundStore, refunds common.RefundStore, tasks *common.BackgroundTasks) (common.Handlers, func(), error) {
 	hSet := common.HandlerSet{
 		Services: srv,
 		Validate: validator,
 		AwareSet: set,
 	}
-	copyCfg := *cfg
 
 	// Agreement S3 AWS Client
 	awsOptions := []awsWrapper.Option{
@@ -58,39 +57,95 @@ func ProviderHandlers(initial config.Initial, srv common.Services, validator *va
 		return nil, func() {}, err
 	}
 
+	return newHandlers(initial, hSet, cfg, handlerDeps{
+		awsManagerAgreement:    awsManagerAgreement,
+		awsManagerReporter:     awsManagerReporter,
+		awsManagerMerchantDocs: awsManagerMerchantDocs,
+		awsCloudWatchLogs:      awsCloudWatchLogs,
+		apiKeys:                apiKeys,
+		authCache:              authCache,
+		sessions:               sessions,
+		audit:                  audit,
+		approvals:              approvals,
+		bulkRefunds:            bulkRefunds,
+		refunds:                refunds,
+		tasks:                  tasks,
+	}), func() {}, nil
+}
+
+// ProviderRouteHandlers returns the handlers without the clients of AWS and with the memory storages,
+// they're good for listing the routes only
+func ProviderRouteHandlers(initial config.Initial, set common.HandlerSet, cfg *common.Config) common.Handlers {
+	return newHandlers(initial, set, cfg, handlerDeps{
+		apiKeys:     common.NewMemoryApiKeyStore(),
+		authCache:   common.NewMemoryAuthCache(cfg.AuthCacheSize),
+		sessions:    common.NewMemorySessionStore(cfg.SessionTtl),
+		audit:       common.NewMemoryAuditStore(cfg.AuditStoreSize, common.NewNopAuditSink()),
+		approvals:   common.NewMemoryApprovalStore(),
+		bulkRefunds: common.NewMemoryBulkRefundStore(cfg.BulkRefundJobTtl),
+		refunds:     common.NewMemoryRefundStore(),
+		tasks:       common.NewBackgroundTasks(),
+	})
+}
+
+// handlerDeps are the clients and the storages shared by the handlers
+type handlerDeps struct {
+	awsManagerAgreement    awsWrapper.AwsManagerInterface
+	awsManagerReporter     awsWrapper.AwsManagerInterface
+	awsManagerMerchantDocs awsWrapper.AwsManagerInterface
+	awsCloudWatchLogs      common.CloudWatchInterface
+	apiKeys                common.ApiKeyStore
+	authCache              common.AuthCache
+	sessions               common.SessionStore
+	audit                  common.AuditStore
+	approvals              common.ApprovalStore
+	bulkRefunds            common.BulkRefundStore
+	refunds                common.RefundStore
+	tasks                  *common.BackgroundTasks
+}
+
+func newHandlers(initial config.Initial, hSet common.HandlerSet, cfg *common.Config, deps handlerDeps) common.Handlers {
+	copyCfg := *cfg
+	cfg = &copyCfg
+
 	return []common.Handler{
-		NewCardPayWebHook(hSet, &copyCfg),
-		NewCountryApiV1(hSet, &copyCfg),
-		NewDashboardRoute(hSet, &copyCfg),
-		NewKeyRoute(hSet, &copyCfg),
-		NewKeyProductRoute(hSet, &copyCfg),
-		NewOnboardingRoute(hSet, initial, awsManagerAgreement, &copyCfg),
-		NewOrderRoute(hSet, awsCloudWatchLogs, &copyCfg),
-		NewPayLinkRoute(hSet, &copyCfg),
-		NewPaymentCostRoute(hSet, &copyCfg),
-		NewPaymentMethodApiV1(hSet, &copyCfg),
-		NewPriceGroupRoute(hSet, &copyCfg),
-		NewProductRoute(hSet, &copyCfg),
-		NewProjectRoute(hSet, &copyCfg),
-		NewReportFileRoute(hSet, awsManagerReporter, &copyCfg),
-		NewRoyaltyReportsRoute(hSet, &copyCfg),
-		NewTaxesRoute(hSet, &copyCfg),
-		NewTokenRoute(hSet, &copyCfg),
-		NewUserProfileRoute(hSet, &copyCfg),
-		NewVatReportsRoute(hSet, &copyCfg),
-		NewZipCodeRoute(hSet, &copyCfg),
-		NewBalanceRoute(hSet, &copyCfg),
-		NewPayoutDocumentsRoute(hSet, &copyCfg),
-		NewPricingRoute(hSet, &copyCfg),
-		NewOperatingCompanyRoute(hSet, &copyCfg),
-		NewPaymentMinLimitSystemRoute(hSet, &copyCfg),
-		NewAdminUsersRoute(hSet, &copyCfg),
-		NewMerchantUsersRoute(hSet, &copyCfg),
-		NewUserRoute(hSet, &copyCfg),
-		NewWebHookRoute(hSet, &copyCfg),
-		NewActOfCompletionApiV1(hSet, &copyCfg),
-		NewCustomerRoute(hSet, &copyCfg),
-		NewSubscriptionsRoute(hSet, &copyCfg),
-		NewMerchantDocumentRoute(hSet, awsManagerMerchantDocs, &copyCfg),
-	}, func() {}, nil
+		NewApiKeyRoute(hSet, deps.apiKeys, cfg),
+		NewApprovalRoute(hSet, deps.approvals, cfg),
+		NewAuditRoute(hSet, deps.audit, cfg),
+		NewBulkRefundRoute(hSet, deps.bulkRefunds, deps.refunds, deps.tasks, cfg),
+		NewCardPayWebHook(hSet, cfg),
+		NewCountryApiV1(hSet, cfg),
+		NewDashboardRoute(hSet, cfg),
+		NewKeyRoute(hSet, cfg),
+		NewKeyProductRoute(hSet, cfg),
+		NewOnboardingRoute(hSet, initial, deps.awsManagerAgreement, cfg),
+		NewOrderRoute(hSet, deps.awsCloudWatchLogs, deps.refunds, cfg),
+		NewPayLinkRoute(hSet, cfg),
+		NewPaymentCostRoute(hSet, cfg),
+		NewPaymentMethodApiV1(hSet, cfg),
+		NewPriceGroupRoute(hSet, cfg),
+		NewProductRoute(hSet, cfg),
+		NewProjectRoute(hSet, cfg),
+		NewReportFileRoute(hSet, deps.awsManagerReporter, cfg),
+		NewRoyaltyReportsRoute(hSet, cfg),
+		NewTaxesRoute(hSet, cfg),
+		NewTokenRoute(hSet, cfg),
+		NewUserProfileRoute(hSet, deps.authCache, cfg),
+		NewVatReportsRoute(hSet, cfg),
+		NewZipCodeRoute(hSet, cfg),
+		NewBalanceRoute(hSet, cfg),
+		NewPayoutDocumentsRoute(hSet, cfg),
+		NewPricingRoute(hSet, cfg),
+		NewOperatingCompanyRoute(hSet, cfg),
+		NewPaymentMinLimitSystemRoute(hSet, cfg),
+		NewAdminUsersRoute(hSet, deps.sessions, cfg),
+		NewMerchantUsersRoute(hSet, deps.authCache, deps.sessions, cfg),
+		NewUserRoute(hSet, cfg),
+		NewUserSessionRoute(hSet, deps.sessions, cfg),
+		NewWebHookRoute(hSet, cfg),
+		NewActOfCompletionApiV1(hSet, cfg),
+		NewCustomerRoute(hSet, cfg),
+		NewSubscriptionsRoute(hSet, cfg),
+		NewMerchantDocumentRoute(hSet, deps.awsManagerMerchantDocs, cfg),
+	}
 }
@@ -69,7 +69,7 @@ func (h *ReportFileRoute) download(ctx echo.Context) error {
 	fileName := strings.TrimSpace(ctx.Param("file"))
 
 	if fileName == "" {
-		h.L().Error("unable to find the file")
+		common.RequestLogger(ctx, h.L()).Error("unable to find the file")
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
 	}
 
@@ -77,7 +77,7 @@ func (h *ReportFileRoute) download(ctx echo.Context) error {
 	_, err := h.awsManager.Download(ctx.Request().Context(), filePath, &awsWrapper.DownloadInput{FileName: fileName})
 
 	if err != nil {
-		h.L().Error("unable to download the file " + fileName + " with message: " + err.Error())
+		common.RequestLogger(ctx, h.L()).Error("unable to download the file " + fileName + " with message: " + err.Error())
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorMessageDownloadReportFile)
 	}
 
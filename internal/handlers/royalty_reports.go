@@ -27,6 +27,12 @@ type RoyaltyReportRequestFile struct {
 	MerchantId string `json:"merchant_id" validate:"required,hexadecimal,len=24"`
 }
 
+// TransactionsPage is the page of the transactions with the cursors of the next and previous pages
+type TransactionsPage struct {
+	*billingpb.TransactionsPaginate
+	*common.CursorPage
+}
+
 type RoyaltyReportsRoute struct {
 	dispatch common.HandlerSet
 	cfg      common.Config
@@ -81,13 +87,13 @@ func (h *RoyaltyReportsRoute) getRoyaltyReportsList(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.ListRoyaltyReports(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ListRoyaltyReports", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ListRoyaltyReports", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -133,7 +139,7 @@ func (h *RoyaltyReportsRoute) getRoyaltyReport(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetRoyaltyReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetRoyaltyReport")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetRoyaltyReport")
 	}
 
 	if res.Status != http.StatusOK {
@@ -192,13 +198,14 @@ func (h *RoyaltyReportsRoute) downloadRoyaltyReport(ctx echo.Context) error {
 // @tag Royalty reports
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.TransactionsPaginate Returns the transactions list
+// @success 200 {object} TransactionsPage Returns the transactions list
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 404 {object} billingpb.ResponseErrorMessage Not found
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param report_id path {string} true The unique identifier for the royalty report.
 // @param limit query {integer} false The number of transactions returned in one page. Default value is 100.
 // @param offset query {integer} false The ranking number of the first item on the page.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /admin/api/v1/royalty_reports/{report_id}/transactions [get]
 //
 // @summary Get the transactions list included in the royalty report
@@ -207,13 +214,14 @@ func (h *RoyaltyReportsRoute) downloadRoyaltyReport(ctx echo.Context) error {
 // @tag Royalty reports
 // @accept application/json
 // @produce application/json
-// @success 200 {object} billingpb.TransactionsPaginate Returns the transactions list
+// @success 200 {object} TransactionsPage Returns the transactions list
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
 // @failure 404 {object} billingpb.ResponseErrorMessage Not found
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @param report_id path {string} true The unique identifier for the royalty report.
 // @param limit query {integer} false The number of transactions returned in one page. Default value is 100.
 // @param offset query {integer} false The ranking number of the first item on the page.
+// @param cursor query {string} false The cursor of the page from next_cursor or prev_cursor of the previous response, the other query parameters are ignored.
 // @router /system/api/v1/royalty_reports/{report_id}/transactions [get]
 func (h *RoyaltyReportsRoute) listRoyaltyReportOrders(ctx echo.Context) error {
 	req := &billingpb.ListRoyaltyReportOrdersRequest{}
@@ -223,13 +231,16 @@ func (h *RoyaltyReportsRoute) listRoyaltyReportOrders(ctx echo.Context) error {
 	}
 
 	if err := h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
+	seek := common.NewPageSeek(ctx, &h.cfg, req.Limit, req.Offset)
+	req.Limit, req.Offset = seek.Limit, seek.Offset
+
 	res, err := h.dispatch.Services.Billing.ListRoyaltyReportOrders(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "ListRoyaltyReportOrders", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "ListRoyaltyReportOrders", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -237,7 +248,16 @@ func (h *RoyaltyReportsRoute) listRoyaltyReportOrders(ctx echo.Context) error {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
-	return ctx.JSON(http.StatusOK, res.Data)
+	items := res.Data.GetItems()
+	start, end, page := seek.Page(ctx, &h.cfg, res.Data.GetCount(), len(items), func(i int) string {
+		return items[i].Id
+	})
+
+	if res.Data != nil {
+		res.Data.Items = items[start:end]
+	}
+
+	return ctx.JSON(http.StatusOK, &TransactionsPage{res.Data, page})
 }
 
 // @summary Export the file of the transactions list included in the royalty report
@@ -300,7 +320,7 @@ func (h *RoyaltyReportsRoute) merchantReviewRoyaltyReport(ctx echo.Context) erro
 	req := &billingpb.MerchantReviewRoyaltyReportRequest{}
 
 	if err := h.dispatch.BindAndValidate(req, ctx); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.NewValidationError(err.Error()))
+		return err
 	}
 
 	req.IsAccepted = true
@@ -309,7 +329,7 @@ func (h *RoyaltyReportsRoute) merchantReviewRoyaltyReport(ctx echo.Context) erro
 	res, err := h.dispatch.Services.Billing.MerchantReviewRoyaltyReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "MerchantReviewRoyaltyReport")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "MerchantReviewRoyaltyReport")
 	}
 
 	if res.Status != http.StatusOK {
@@ -336,7 +356,7 @@ func (h *RoyaltyReportsRoute) merchantDeclineRoyaltyReport(ctx echo.Context) err
 	req := &billingpb.MerchantReviewRoyaltyReportRequest{}
 
 	if err := h.dispatch.BindAndValidate(req, ctx); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.NewValidationError(err.Error()))
+		return err
 	}
 
 	req.IsAccepted = false
@@ -345,7 +365,7 @@ func (h *RoyaltyReportsRoute) merchantDeclineRoyaltyReport(ctx echo.Context) err
 	res, err := h.dispatch.Services.Billing.MerchantReviewRoyaltyReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "MerchantReviewRoyaltyReport")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "MerchantReviewRoyaltyReport")
 	}
 
 	if res.Status != http.StatusOK {
@@ -372,7 +392,7 @@ func (h *RoyaltyReportsRoute) changeRoyaltyReport(ctx echo.Context) error {
 	req := &billingpb.ChangeRoyaltyReportRequest{}
 
 	if err := h.dispatch.BindAndValidate(req, ctx); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.NewValidationError(err.Error()))
+		return err
 	}
 
 	req.Ip = ctx.RealIP()
@@ -380,7 +400,7 @@ func (h *RoyaltyReportsRoute) changeRoyaltyReport(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.ChangeRoyaltyReport(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeRoyaltyReport")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeRoyaltyReport")
 	}
 
 	if res.Status != http.StatusOK {
@@ -73,7 +73,7 @@ func (h *SubscriptionsRoute) getSubscriptions(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.FindSubscriptions(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "FindSubscriptions")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "FindSubscriptions")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -112,7 +112,7 @@ func (h *SubscriptionsRoute) getSubscription(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetSubscription(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, "recurringpb", "GetSubscription", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, "recurringpb", "GetSubscription", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -152,7 +152,7 @@ func (h *SubscriptionsRoute) getSubscriptionOrders(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.GetSubscriptionOrders(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetSubscriptionOrders")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetSubscriptionOrders")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -191,7 +191,7 @@ func (h *SubscriptionsRoute) deleteSubscription(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Billing.DeleteRecurringSubscription(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "DeleteRecurringSubscription", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "DeleteRecurringSubscription", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
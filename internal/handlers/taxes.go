@@ -58,7 +58,7 @@ func (h *TaxesRoute) getTaxes(ctx echo.Context) error {
 	res, err := h.dispatch.Services.Tax.GetRates(ctx.Request().Context(), req)
 
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -130,7 +130,7 @@ func (h *TaxesRoute) setTax(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Tax.CreateOrUpdate(ctx.Request().Context(), req)
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -161,7 +161,7 @@ func (h *TaxesRoute) deleteTax(ctx echo.Context) error {
 
 	res, err := h.dispatch.Services.Tax.DeleteRateById(ctx.Request().Context(), &taxpb.DeleteRateRequest{Id: uint32(value)})
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -12,6 +12,8 @@ import (
 
 const (
 	tokenPath = "/tokens"
+
+	tokenRequestContextKey = "tokenRequest"
 )
 
 type TokenRoute struct {
@@ -37,7 +39,69 @@ func NewTokenRoute(set common.HandlerSet, cfg *common.Config) *TokenRoute {
 }
 
 func (h *TokenRoute) Route(groups *common.Groups) {
-	groups.Common.POST(tokenPath, h.createToken)
+	groups.Common.POST(
+		tokenPath,
+		h.createToken,
+		h.checkTokenRequest(groups.S2SSignature),
+		common.RequireApiKeyScope(common.ApiKeyScopeCreateTokens),
+		groups.Idempotency,
+	)
+}
+
+// checkTokenRequest validates the request and checks its signature, so the idempotency keys
+// of the request are scoped by the project which signed it. The request is signed with the project secret
+// in X-API-SIGNATURE header or with the project secret or API key in S2S signature headers.
+func (h *TokenRoute) checkTokenRequest(s2sSignature echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req, err := h.bindTokenRequest(ctx)
+
+			if err != nil {
+				return err
+			}
+
+			ctx.Set(tokenRequestContextKey, req)
+
+			if ctx.Request().Header.Get(common.HeaderXS2SSignature) != "" {
+				return s2sSignature(func(ctx echo.Context) error {
+					if common.ExtractUserContext(ctx).ProjectId != req.Settings.ProjectId {
+						return echo.NewHTTPError(http.StatusForbidden, common.ErrorMessageTokenProjectMismatch)
+					}
+
+					return next(ctx)
+				})(ctx)
+			}
+
+			err = common.CheckProjectAuthRequestSignature(h.dispatch, ctx, req.Settings.ProjectId)
+
+			if err != nil {
+				return err
+			}
+
+			user := common.ExtractUserContext(ctx)
+			user.ProjectId = req.Settings.ProjectId
+			common.SetUserContext(ctx, user)
+
+			return next(ctx)
+		}
+	}
+}
+
+func (h *TokenRoute) bindTokenRequest(ctx echo.Context) (*billingpb.TokenRequest, error) {
+	req := &billingpb.TokenRequest{}
+	err := ctx.Bind(req)
+
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+	}
+
+	err = h.dispatch.Validate.Struct(req)
+
+	if err != nil {
+		return nil, common.NewValidationHTTPError(err)
+	}
+
+	return req, nil
 }
 
 // @summary Create a payment token
@@ -49,33 +113,17 @@ func (h *TokenRoute) Route(groups *common.Groups) {
 // @body billingpb.TokenRequest
 // @success 200 {object} TokenCreationResponse Returns the payment token string and the PaySuper-hosted URL for a payment form
 // @failure 400 {object} billingpb.ResponseErrorMessage Invalid request data
+// @failure 401 {object} billingpb.ResponseErrorMessage The S2S signature is invalid
+// @failure 403 {object} billingpb.ResponseErrorMessage The API key has no create-tokens scope or belongs to another project
 // @failure 404 {object} billingpb.ResponseErrorMessage Not found
 // @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
 // @router /api/v1/tokens [post]
 func (h *TokenRoute) createToken(ctx echo.Context) error {
-	req := &billingpb.TokenRequest{}
-	err := ctx.Bind(req)
-
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
-	}
-
-	err = h.dispatch.Validate.Struct(req)
-
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
-	}
-
-	err = common.CheckProjectAuthRequestSignature(h.dispatch, ctx, req.Settings.ProjectId)
-
-	if err != nil {
-		return err
-	}
-
+	req := ctx.Get(tokenRequestContextKey).(*billingpb.TokenRequest)
 	res, err := h.dispatch.Services.Billing.CreateToken(ctx.Request().Context(), req)
 
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/globalsign/mgo/bson"
 	"github.com/labstack/echo/v4"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -411,3 +413,144 @@ func (suite *TokenTestSuite) TestToken_CreateToken_ChangeCustomer_ResultError()
 	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
 	assert.Equal(suite.T(), mock.SomeError, httpErr.Message)
 }
+
+// signedTokenRequest sends the token request of the project with the idempotency key
+func (suite *TokenTestSuite) signedTokenRequest(projectId, key string) (*httptest.ResponseRecorder, error) {
+	body := &billingpb.TokenRequest{
+		User: &billingpb.TokenUser{
+			Id:     "5dbac6a9120a810001a8fe41",
+			Email:  &billingpb.TokenUserEmailValue{Value: "test@unit.test"},
+			Ip:     &billingpb.TokenUserIpValue{Value: "127.0.0.1"},
+			Locale: &billingpb.TokenUserLocaleValue{Value: "ru-RU"},
+		},
+		Settings: &billingpb.TokenSettings{
+			ProjectId:   projectId,
+			Currency:    "RUB",
+			Amount:      100,
+			Description: "test payment",
+			Type:        "simple",
+		},
+	}
+
+	b, err := json.Marshal(body)
+	assert.NoError(suite.T(), err)
+
+	return suite.caller.Builder().
+		Method(http.MethodPost).
+		Path(common.NoAuthGroupPath + tokenPath).
+		Init(func(request *http.Request, middleware test.Middleware) {
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			request.Header.Set(common.HeaderXApiSignatureHeader, "signature")
+			request.Header.Set(common.HeaderIdempotencyKey, key)
+		}).
+		BodyBytes(b).
+		Exec(suite.T())
+}
+
+func (suite *TokenTestSuite) TestToken_CreateToken_IdempotencyKey_Replayed() {
+	res, err := suite.signedTokenRequest("5dbac6a9120a810001a8fe41", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Empty(suite.T(), res.Header().Get(common.HeaderIdempotentReplayed))
+
+	replayed, err := suite.signedTokenRequest("5dbac6a9120a810001a8fe41", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, replayed.Code)
+	assert.Equal(suite.T(), "true", replayed.Header().Get(common.HeaderIdempotentReplayed))
+	assert.Equal(suite.T(), res.Body.String(), replayed.Body.String())
+
+	// the keys are scoped by the project which signed the request
+	res, err = suite.signedTokenRequest("5dbac6a9120a810001a8fe42", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Empty(suite.T(), res.Header().Get(common.HeaderIdempotentReplayed))
+}
+
+func (suite *TokenTestSuite) TestToken_CreateToken_SignatureError_NotStored() {
+	suite.router.dispatch.Services.Billing = mock.NewBillingServerErrorMock()
+
+	_, err := suite.signedTokenRequest("5dbac6a9120a810001a8fe41", "key-1")
+	assert.Error(suite.T(), err)
+
+	suite.router.dispatch.Services.Billing = mock.NewBillingServerOkMock()
+
+	res, err := suite.signedTokenRequest("5dbac6a9120a810001a8fe41", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+	assert.Empty(suite.T(), res.Header().Get(common.HeaderIdempotentReplayed))
+}
+
+func (suite *TokenTestSuite) TestToken_CreateToken_ApiKeySignature() {
+	tests := []struct {
+		name      string
+		projectId string
+		scopes    []string
+		code      int
+		message   *billingpb.ResponseErrorMessage
+	}{
+		{"create-tokens scope", "5dbac6a9120a810001a8fe41", []string{common.ApiKeyScopeCreateTokens}, http.StatusOK, nil},
+		{"no create-tokens scope", "5dbac6a9120a810001a8fe41", []string{common.ApiKeyScopeReadOrders}, http.StatusForbidden, common.ErrorMessageApiKeyScopeDenied},
+		{"another project", "5dbac6a9120a810001a8fe42", []string{common.ApiKeyScopeCreateTokens}, http.StatusForbidden, common.ErrorMessageTokenProjectMismatch},
+	}
+
+	for _, tt := range tests {
+		// the signature is checked by the dispatcher, the request is authenticated with the API key of the project
+		signature := func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(ctx echo.Context) error {
+				common.SetUserContext(ctx, &common.AuthUser{
+					ProjectId: "5dbac6a9120a810001a8fe41",
+					ApiKeyId:  "api_key_id",
+					Scopes:    tt.scopes,
+				})
+				return next(ctx)
+			}
+		}
+
+		e := echo.New()
+		suite.router.Route(&common.Groups{
+			Common:       e.Group(common.NoAuthGroupPath),
+			Idempotency:  func(next echo.HandlerFunc) echo.HandlerFunc { return next },
+			S2SSignature: signature,
+		})
+
+		body, err := json.Marshal(&billingpb.TokenRequest{
+			User: &billingpb.TokenUser{
+				Id:     "5dbac6a9120a810001a8fe41",
+				Email:  &billingpb.TokenUserEmailValue{Value: "test@unit.test"},
+				Ip:     &billingpb.TokenUserIpValue{Value: "127.0.0.1"},
+				Locale: &billingpb.TokenUserLocaleValue{Value: "ru-RU"},
+			},
+			Settings: &billingpb.TokenSettings{
+				ProjectId:   tt.projectId,
+				Currency:    "RUB",
+				Amount:      100,
+				Description: "test payment",
+				Type:        "simple",
+			},
+		})
+		assert.NoError(suite.T(), err)
+
+		req := httptest.NewRequest(http.MethodPost, common.NoAuthGroupPath+tokenPath, bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(common.HeaderXS2SSignature, "signature")
+
+		var handlerErr error
+		e.HTTPErrorHandler = func(err error, ctx echo.Context) {
+			handlerErr = err
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if tt.message == nil {
+			assert.NoError(suite.T(), handlerErr, tt.name)
+			assert.Equal(suite.T(), tt.code, rec.Code, tt.name)
+			continue
+		}
+
+		httpErr, ok := handlerErr.(*echo.HTTPError)
+		assert.True(suite.T(), ok, tt.name)
+		assert.Equal(suite.T(), tt.code, httpErr.Code, tt.name)
+		assert.Equal(suite.T(), tt.message, httpErr.Message, tt.name)
+	}
+}
@@ -18,17 +18,19 @@ const (
 )
 
 type UserProfileRoute struct {
-	dispatch common.HandlerSet
-	cfg      common.Config
+	dispatch  common.HandlerSet
+	cfg       common.Config
+	authCache common.AuthCache
 	provider.LMT
 }
 
-func NewUserProfileRoute(set common.HandlerSet, cfg *common.Config) *UserProfileRoute {
+func NewUserProfileRoute(set common.HandlerSet, authCache common.AuthCache, cfg *common.Config) *UserProfileRoute {
 	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "UserProfileRoute"})
 	return &UserProfileRoute{
-		dispatch: set,
-		LMT:      &set.AwareSet,
-		cfg:      *cfg,
+		dispatch:  set,
+		LMT:       &set.AwareSet,
+		cfg:       *cfg,
+		authCache: authCache,
 	}
 }
 
@@ -77,13 +79,13 @@ func (h *UserProfileRoute) getUserProfile(ctx echo.Context) error {
 	err := h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetUserProfile(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "GetUserProfile", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "GetUserProfile", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -112,13 +114,13 @@ func (h *UserProfileRoute) getUserCommonProfile(ctx echo.Context) error {
 	req := &billingpb.CommonUserProfileRequest{UserId: authUser.Id}
 
 	if err := h.dispatch.BindAndValidate(req, ctx); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+		return err
 	}
 
 	res, err := h.dispatch.Services.Billing.GetCommonUserProfile(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "GetCommonUserProfile")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "GetCommonUserProfile")
 	}
 
 	if res.Status != billingpb.ResponseStatusOk {
@@ -159,13 +161,13 @@ func (h *UserProfileRoute) setUserProfile(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.CreateOrUpdateUserProfile(ctx.Request().Context(), req)
 
 	if err != nil {
-		h.L().Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
 	}
 
@@ -173,6 +175,8 @@ func (h *UserProfileRoute) setUserProfile(ctx echo.Context) error {
 		return echo.NewHTTPError(int(res.Status), res.Message)
 	}
 
+	h.authCache.Invalidate(common.AuthCacheUserTag(authUser.Id))
+
 	return ctx.JSON(http.StatusOK, res.Item)
 }
 
@@ -192,13 +196,13 @@ func (h *UserProfileRoute) confirmEmail(ctx echo.Context) error {
 	req := &billingpb.ConfirmUserEmailRequest{}
 
 	if err := h.dispatch.BindAndValidate(req, ctx); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
+		return err
 	}
 
 	res, err := h.dispatch.Services.Billing.ConfirmUserEmail(ctx.Request().Context(), req)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ConfirmUserEmail")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ConfirmUserEmail")
 	}
 
 	if res.Status != http.StatusOK {
@@ -215,13 +219,13 @@ func (h *UserProfileRoute) confirmEmail(ctx echo.Context) error {
 	}
 
 	if err = h.dispatch.Validate.Struct(req2); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res2, err := h.dispatch.Services.Billing.ChangeMerchant(ctx.Request().Context(), req2)
 
 	if err != nil {
-		return h.dispatch.SrvCallHandler(req, err, billingpb.ServiceName, "ChangeMerchant")
+		return h.dispatch.SrvCallHandler(ctx, req, err, billingpb.ServiceName, "ChangeMerchant")
 	}
 
 	if res2.Status != http.StatusOK {
@@ -261,7 +265,7 @@ func (h *UserProfileRoute) createFeedback(ctx echo.Context) error {
 	err = h.dispatch.Validate.Struct(req)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.CreatePageReview(ctx.Request().Context(), req)
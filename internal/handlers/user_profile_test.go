@@ -10,12 +10,14 @@ import (
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type UserProfileTestSuite struct {
 	suite.Suite
-	router *UserProfileRoute
-	caller *test.EchoReqResCaller
+	router    *UserProfileRoute
+	caller    *test.EchoReqResCaller
+	authCache common.AuthCache
 }
 
 func Test_UserProfile(t *testing.T) {
@@ -34,9 +36,10 @@ func (suite *UserProfileTestSuite) SetupTest() {
 	srv := common.Services{
 		Billing: mock.NewBillingServerOkMock(),
 	}
+	suite.authCache = common.NewMemoryAuthCache(100)
 	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
 		mw.Pre(test.PreAuthUserMiddleware(user))
-		suite.router = NewUserProfileRoute(set.HandlerSet, set.GlobalConfig)
+		suite.router = NewUserProfileRoute(set.HandlerSet, suite.authCache, set.GlobalConfig)
 		return common.Handlers{
 			suite.router,
 		}
@@ -134,6 +137,34 @@ func (suite *UserProfileTestSuite) TestUserProfile_SetUserProfile_Ok() {
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *UserProfileTestSuite) TestUserProfile_SetUserProfile_InvalidatesAuthCache() {
+	key := common.AuthCacheProfileKey("ffffffffffffffffffffffff")
+	suite.authCache.Set(key, "profile", time.Minute, common.AuthCacheUserTag("ffffffffffffffffffffffff"))
+
+	body := `{
+		"personal": {"first_name": "unit test", "last_name": "test-unit", "position": "Software Developer"},
+		"company": {
+			"company_name": "Unit Test.-444",
+			"website": "http://localhost",
+			"annual_income": {"from": 0, "to": 1000},
+			"number_of_employees": {"from": 1, "to": 10},
+			"kind_of_activity": "other"
+		}
+	}`
+
+	_, err := suite.caller.Builder().
+		Method(http.MethodPatch).
+		Path(common.AuthProjectGroupPath + userProfilePath).
+		Init(test.ReqInitJSON()).
+		BodyString(body).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+
+	_, ok := suite.authCache.Get(key)
+	assert.False(suite.T(), ok)
+}
+
 func (suite *UserProfileTestSuite) TestUserProfile_SetUserProfile_BindError() {
 	body := `{"help": {"product_promotion_and_development": "unit test"}}`
 
@@ -394,6 +425,11 @@ func (suite *UserProfileTestSuite) TestUserProfile_ConfirmEmail_EmptyToken_Error
 	httpErr, ok := err.(*echo.HTTPError)
 	assert.True(suite.T(), ok)
 	assert.Equal(suite.T(), http.StatusBadRequest, httpErr.Code)
+
+	msg, ok := httpErr.Message.(*billingpb.ResponseErrorMessage)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), common.ErrorValidationFailed.Code, msg.Code)
+	assert.Regexp(suite.T(), "Token", msg.Details)
 }
 
 func (suite *UserProfileTestSuite) TestUserProfile_ConfirmEmail_BillingServerSystemError() {
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"github.com/ProtocolONE/go-core/v2/pkg/logger"
+	"github.com/ProtocolONE/go-core/v2/pkg/provider"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"net/http"
+)
+
+const (
+	userSessionsPath   = "/user/sessions"
+	userSessionsIdPath = "/user/sessions/:session_id"
+)
+
+type UserSessionRoute struct {
+	dispatch common.HandlerSet
+	cfg      common.Config
+	sessions common.SessionStore
+	provider.LMT
+}
+
+func NewUserSessionRoute(set common.HandlerSet, sessions common.SessionStore, cfg *common.Config) *UserSessionRoute {
+	set.AwareSet.Logger = set.AwareSet.Logger.WithFields(logger.Fields{"router": "UserSessionRoute"})
+	return &UserSessionRoute{
+		dispatch: set,
+		LMT:      &set.AwareSet,
+		cfg:      *cfg,
+		sessions: sessions,
+	}
+}
+
+func (h *UserSessionRoute) Route(groups *common.Groups) {
+	groups.AuthUser.GET(userSessionsPath, h.listSessions)
+	groups.AuthUser.DELETE(userSessionsPath, h.revokeSessions)
+	groups.AuthUser.DELETE(userSessionsIdPath, h.revokeSession)
+}
+
+// @summary Get the active sessions
+// @desc Get the list of the active sessions of the current user
+// @id userSessionsPathListSessions
+// @tag User
+// @accept application/json
+// @produce application/json
+// @success 200 {object} []common.Session Returns the list of the sessions, the latest used first
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @router /admin/api/v1/user/sessions [get]
+func (h *UserSessionRoute) listSessions(ctx echo.Context) error {
+	sessions, err := h.sessions.List(common.ExtractUserContext(ctx).Id)
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	current := currentSessionId(ctx)
+
+	for _, session := range sessions {
+		session.Current = session.Id == current
+	}
+
+	return ctx.JSON(http.StatusOK, sessions)
+}
+
+// @summary Revoke the other sessions
+// @desc Revoke all sessions of the current user except the session of the request
+// @id userSessionsPathRevokeSessions
+// @tag User
+// @accept application/json
+// @produce application/json
+// @success 204 {string} Returns an empty response body if the sessions were successfully revoked
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @router /admin/api/v1/user/sessions [delete]
+func (h *UserSessionRoute) revokeSessions(ctx echo.Context) error {
+	current := currentSessionId(ctx)
+
+	// the current session must survive, otherwise all tokens issued before now would be rejected
+	if current == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessageAuthorizationTokenNotFound)
+	}
+
+	if err := h.sessions.RevokeUser(common.ExtractUserContext(ctx).Id, current); err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @summary Revoke the session
+// @desc Revoke the session of the current user, the access token of the session is rejected after that
+// @id userSessionsIdPathRevokeSession
+// @tag User
+// @accept application/json
+// @produce application/json
+// @success 204 {string} Returns an empty response body if the session was successfully revoked
+// @failure 401 {object} billingpb.ResponseErrorMessage Unauthorized request
+// @failure 404 {object} billingpb.ResponseErrorMessage The session not found
+// @failure 500 {object} billingpb.ResponseErrorMessage Internal Server Error
+// @param session_id path {string} true The unique identifier for the session.
+// @router /admin/api/v1/user/sessions/{session_id} [delete]
+func (h *UserSessionRoute) revokeSession(ctx echo.Context) error {
+	revoked, err := h.sessions.Revoke(common.ExtractUserContext(ctx).Id, ctx.Param(common.RequestParameterSessionId))
+
+	if err != nil {
+		common.RequestLogger(ctx, h.L()).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error()}))
+		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorUnknown)
+	}
+
+	if !revoked {
+		return echo.NewHTTPError(http.StatusNotFound, common.ErrorMessageSessionNotFound)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// currentSessionId returns the session of the request access token
+func currentSessionId(ctx echo.Context) string {
+	match := common.TokenRegex.FindStringSubmatch(ctx.Request().Header.Get(echo.HeaderAuthorization))
+
+	if len(match) < 2 {
+		return ""
+	}
+
+	return common.SessionId(match[1])
+}
+
+// revokeUserSessions logs out the user from all sessions, the failure is logged only
+// as the change of the user is already saved
+func revokeUserSessions(ctx echo.Context, l logger.Logger, sessions common.SessionStore, userId string) {
+	if userId == "" {
+		return
+	}
+
+	if err := sessions.RevokeUser(userId, ""); err != nil {
+		common.RequestLogger(ctx, l).Error(common.InternalErrorTemplate, logger.WithFields(logger.Fields{"err": err.Error(), "user_id": userId}))
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/paysuper/paysuper-management-api/internal/dispatcher/common"
+	"github.com/paysuper/paysuper-management-api/internal/mock"
+	"github.com/paysuper/paysuper-management-api/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	userSessionCurrentToken = "current_access_token"
+	userSessionOtherToken   = "other_access_token"
+)
+
+type UserSessionTestSuite struct {
+	suite.Suite
+	router   *UserSessionRoute
+	caller   *test.EchoReqResCaller
+	user     *common.AuthUser
+	sessions common.SessionStore
+}
+
+func Test_UserSession(t *testing.T) {
+	suite.Run(t, new(UserSessionTestSuite))
+}
+
+func (suite *UserSessionTestSuite) SetupTest() {
+	var e error
+	settings := test.DefaultSettings()
+	srv := common.Services{
+		Billing: mock.NewBillingServerOkMock(),
+	}
+	suite.user = &common.AuthUser{
+		Id:         "ffffffffffffffffffffffff",
+		Email:      "test@unit.test",
+		MerchantId: "ffffffffffffffffffffffff",
+	}
+	suite.sessions = common.NewMemorySessionStore(time.Hour)
+	suite.caller, e = test.SetUp(settings, srv, func(set *test.TestSet, mw test.Middleware) common.Handlers {
+		mw.Pre(test.PreAuthUserMiddleware(suite.user))
+		suite.router = NewUserSessionRoute(set.HandlerSet, suite.sessions, set.GlobalConfig)
+		return common.Handlers{
+			suite.router,
+		}
+	})
+	if e != nil {
+		panic(e)
+	}
+
+	for _, token := range []string{userSessionCurrentToken, userSessionOtherToken} {
+		_, err := suite.sessions.Touch(common.NewSession(token, suite.user.Id, "unit test", "127.0.0.1"))
+		assert.NoError(suite.T(), err)
+	}
+
+	_, err := suite.sessions.Touch(common.NewSession("foreign_access_token", "eeeeeeeeeeeeeeeeeeeeeeee", "unit test", "127.0.0.1"))
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *UserSessionTestSuite) TearDownTest() {}
+
+func (suite *UserSessionTestSuite) initAuth() func(*http.Request, test.Middleware) {
+	return func(req *http.Request, middleware test.Middleware) {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+userSessionCurrentToken)
+	}
+}
+
+func (suite *UserSessionTestSuite) TestUserSession_List_Ok() {
+	res, err := suite.caller.Builder().
+		Method(http.MethodGet).
+		Path(common.AuthUserGroupPath + userSessionsPath).
+		Init(suite.initAuth()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, res.Code)
+
+	var sessions []*common.Session
+	assert.NoError(suite.T(), json.Unmarshal(res.Body.Bytes(), &sessions))
+	assert.Len(suite.T(), sessions, 2)
+
+	for _, session := range sessions {
+		assert.Equal(suite.T(), session.Id == common.SessionId(userSessionCurrentToken), session.Current)
+	}
+}
+
+func (suite *UserSessionTestSuite) TestUserSession_RevokeAll_Ok() {
+	res, err := suite.caller.Builder().
+		Method(http.MethodDelete).
+		Path(common.AuthUserGroupPath + userSessionsPath).
+		Init(suite.initAuth()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusNoContent, res.Code)
+
+	revoked, err := suite.sessions.Touch(common.NewSession(userSessionOtherToken, suite.user.Id, "", ""))
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), revoked)
+
+	revoked, err = suite.sessions.Touch(common.NewSession(userSessionCurrentToken, suite.user.Id, "", ""))
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), revoked)
+}
+
+func (suite *UserSessionTestSuite) TestUserSession_Revoke_Ok() {
+	res, err := suite.caller.Builder().
+		Method(http.MethodDelete).
+		Params(":"+common.RequestParameterSessionId, common.SessionId(userSessionOtherToken)).
+		Path(common.AuthUserGroupPath + userSessionsIdPath).
+		Init(suite.initAuth()).
+		Exec(suite.T())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusNoContent, res.Code)
+
+	sessions, err := suite.sessions.List(suite.user.Id)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), sessions, 1)
+	assert.Equal(suite.T(), common.SessionId(userSessionCurrentToken), sessions[0].Id)
+}
+
+func (suite *UserSessionTestSuite) TestUserSession_Revoke_ForeignSession() {
+	_, err := suite.caller.Builder().
+		Method(http.MethodDelete).
+		Params(":"+common.RequestParameterSessionId, common.SessionId("foreign_access_token")).
+		Path(common.AuthUserGroupPath + userSessionsIdPath).
+		Init(suite.initAuth()).
+		Exec(suite.T())
+
+	assert.Error(suite.T(), err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), http.StatusNotFound, httpErr.Code)
+	assert.Equal(suite.T(), common.ErrorMessageSessionNotFound, httpErr.Message)
+}
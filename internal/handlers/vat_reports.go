@@ -92,7 +92,7 @@ func (h *VatReportsRoute) getVatReportsForCountry(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetVatReportsForCountry(ctx.Request().Context(), req)
@@ -159,7 +159,7 @@ func (h *VatReportsRoute) getVatReportTransactions(ctx echo.Context) error {
 
 	err = h.dispatch.Validate.Struct(req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.GetVatReportTransactions(ctx.Request().Context(), req)
@@ -223,7 +223,7 @@ func (h *VatReportsRoute) updateVatReportStatus(ctx echo.Context) error {
 	req.Id = ctx.Param(common.RequestParameterId)
 
 	if err = h.dispatch.Validate.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, common.GetValidationError(err))
+		return common.NewValidationHTTPError(err)
 	}
 
 	res, err := h.dispatch.Services.Billing.UpdateVatReportStatus(ctx.Request().Context(), req)
@@ -52,14 +52,14 @@ func (h *WebHookRoute) sendWebhookTest(ctx echo.Context) error {
 	}
 
 	if len(req.TestingCase) == 0 {
-		h.L().Error(common.BindingErrorTemplate, logger.PairArgs("err", "testing case is empty"))
+		common.RequestLogger(ctx, h.L()).Error(common.BindingErrorTemplate, logger.PairArgs("err", "testing case is empty"))
 		return echo.NewHTTPError(http.StatusBadRequest, common.ErrorRequestParamsIncorrect)
 	}
 
 	res, err := h.dispatch.Services.Billing.SendWebhookToMerchant(ctx.Request().Context(), req)
 
 	if err != nil {
-		common.LogSrvCallFailedGRPC(h.L(), err, billingpb.ServiceName, "SendWebhookToMerchant", req)
+		common.LogSrvCallFailedGRPC(common.RequestLogger(ctx, h.L()), err, billingpb.ServiceName, "SendWebhookToMerchant", req)
 		return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorInternal)
 	}
 
@@ -295,6 +295,11 @@ func NewBillingServerOkMock() billingpb.BillingService {
 	bill.On("GetPaymentChannelCostSystem", mock.Anything, mock.Anything).
 		Return(&billingpb.PaymentChannelCostSystemResponse{Status: billingpb.ResponseStatusOk}, nil)
 
+	bill.On("GetAllPaymentChannelCostMerchant", mock.Anything, mock.Anything).
+		Return(&billingpb.PaymentChannelCostMerchantListResponse{
+			Status: billingpb.ResponseStatusOk,
+		}, nil)
+
 	bill.On("GetAllPaymentChannelCostSystem", mock.Anything, mock.Anything).
 		Return(&billingpb.PaymentChannelCostSystemListResponse{
 			Status: billingpb.ResponseStatusOk,
@@ -25,6 +25,8 @@ type TestSet struct {
 	GlobalConfig *common.Config
 	HandlerSet   common.HandlerSet
 	Initial      config.Initial
+	// Approval store shared by the dispatcher middleware and the approvals route
+	ApprovalStore common.ApprovalStore
 }
 
 // ProviderTestSet
@@ -38,7 +40,8 @@ func ProviderTestSet(initial config.Initial, awareSet provider.AwareSet, srv com
 			Validate: validate,
 			Services: srv,
 		},
-		Initial: initial,
+		Initial:       initial,
+		ApprovalStore: common.NewMemoryApprovalStore(),
 	}
 	return t, func() {}, nil
 }
@@ -71,7 +74,7 @@ func BuildTestSet(ctx context.Context, settings config.Settings, srv common.Serv
 }
 
 // BuildDispatcher
-func BuildDispatcher(ctx context.Context, settings config.Settings, srv common.Services, handlers common.Handlers, observer invoker.Observer) (*dispatcher.Dispatcher, func(), error) {
+func BuildDispatcher(ctx context.Context, settings config.Settings, srv common.Services, handlers common.Handlers, approvals common.ApprovalStore, observer invoker.Observer) (*dispatcher.Dispatcher, func(), error) {
 	panic(
 		wire.Build(
 			ProviderTestInitial,
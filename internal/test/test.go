@@ -249,9 +249,10 @@ func (c *EchoReqResCaller) Request(method, target string, body io.Reader, init f
 		return
 	}
 	//
+	errorHandler := he.HTTPErrorHandler
 	he.HTTPErrorHandler = func(e error, context echo.Context) {
 		err = e
-		he.DefaultHTTPErrorHandler(e, context)
+		errorHandler(e, context)
 	}
 	//
 	he.ServeHTTP(resRec, req)
@@ -281,6 +282,7 @@ func DefaultSettings() map[string]interface{} {
 				"returnPaymentForm":            true,
 				"disableAuthMiddleware":        true,
 				"disableCasbinPolicy":          true,
+				"auditSink":                    "none",
 				"paymentFormJsLibraryUrl":      "unknown",
 				"awsAccessKeyIdAgreement":      "unknown",
 				"awsSecretAccessKeyAgreement":  "unknown",
@@ -294,6 +296,8 @@ func DefaultSettings() map[string]interface{} {
 				"customerTokenCookiesLifetime": "2592000s",
 				"CookieDomain":                 "localhost",
 				"orderInlineFormUrlMask":       "http://localhost",
+				"mongoDsn":                     "mongodb://localhost:27017/test",
+				"cursorSecret":                 "unknown",
 				"paylinkPaymentFormUrlMask":    "http://localhost/paylink=%s",
 				"auth1": map[string]interface{}{
 					"clientId":     "unknown",
@@ -322,6 +326,7 @@ func SetUp(settings map[string]interface{}, services common.Services, setUp func
 		settings,
 		services,
 		setUp(testSet, middlewareSetUp),
+		testSet.ApprovalStore,
 		nil,
 	)
 	if e != nil {
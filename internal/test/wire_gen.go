@@ -115,7 +115,7 @@ func BuildTestSet(ctx context.Context, settings config.Settings, srv common.Serv
 	}, nil
 }
 
-func BuildDispatcher(ctx context.Context, settings config.Settings, srv common.Services, handlers common.Handlers, observer invoker.Observer) (*dispatcher.Dispatcher, func(), error) {
+func BuildDispatcher(ctx context.Context, settings config.Settings, srv common.Services, handlers common.Handlers, approvals common.ApprovalStore, observer invoker.Observer) (*dispatcher.Dispatcher, func(), error) {
 	initial := ProviderTestInitial()
 	configurator, cleanup, err := config.ProviderTest(initial, observer, settings)
 	if err != nil {
@@ -162,11 +162,11 @@ func BuildDispatcher(ctx context.Context, settings config.Settings, srv common.S
 		return nil, nil, err
 	}
 	jwtVerifier := dispatcher.ProviderJwtVerifier(commonConfig)
-	appSet := dispatcher.AppSet{
-		Handlers:    handlers,
-		Services:    srv,
-		JwtVerifier: jwtVerifier,
-	}
+	idempotencyStore := dispatcher.ProviderTestIdempotencyStore(commonConfig)
+	nonceStore := dispatcher.ProviderTestNonceStore()
+	apiKeyStore := dispatcher.ProviderTestApiKeyStore()
+	authCache := dispatcher.ProviderAuthCache(commonConfig)
+	sessionStore := dispatcher.ProviderTestSessionStore(commonConfig)
 	dispatcherConfig, cleanup7, err := dispatcher.ProviderCfg(configurator)
 	if err != nil {
 		cleanup6()
@@ -200,6 +200,33 @@ func BuildDispatcher(ctx context.Context, settings config.Settings, srv common.S
 		cleanup()
 		return nil, nil, err
 	}
+	auditStore, err := dispatcher.ProviderTestAuditStore(commonConfig, microMicro)
+	if err != nil {
+		cleanup9()
+		cleanup8()
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	backgroundTasks := dispatcher.ProviderBackgroundTasks()
+	appSet := dispatcher.AppSet{
+		Handlers:         handlers,
+		Services:         srv,
+		JwtVerifier:      jwtVerifier,
+		IdempotencyStore: idempotencyStore,
+		NonceStore:       nonceStore,
+		ApiKeyStore:      apiKeyStore,
+		AuthCache:        authCache,
+		SessionStore:     sessionStore,
+		AuditStore:       auditStore,
+		ApprovalStore:    approvals,
+		BackgroundTasks:  backgroundTasks,
+	}
 	dispatcherDispatcher, cleanup10, err := dispatcher.ProviderDispatcher(ctx, awareSet, appSet, dispatcherConfig, commonConfig, microMicro)
 	if err != nil {
 		cleanup9()
@@ -235,6 +262,8 @@ type TestSet struct {
 	GlobalConfig *common.Config
 	HandlerSet   common.HandlerSet
 	Initial      config.Initial
+	// Approval store shared by the dispatcher middleware and the approvals route
+	ApprovalStore common.ApprovalStore
 }
 
 // ProviderTestSet
@@ -248,7 +277,8 @@ func ProviderTestSet(initial config.Initial, awareSet provider.AwareSet, srv com
 			Validate: validate,
 			Services: srv,
 		},
-		Initial: initial,
+		Initial:       initial,
+		ApprovalStore: common.NewMemoryApprovalStore(),
 	}
 	return t, func() {}, nil
 }
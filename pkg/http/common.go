@@ -1,14 +1,32 @@
 package http
 
-import "github.com/labstack/echo/v4"
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+)
 
 const (
 	Prefix           = "internal.http"
 	UnmarshalKey     = "http"
 	UnmarshalKeyBind = "http.bind"
+	// UnmarshalKeyMetricsBind is the address of the metrics server
+	UnmarshalKeyMetricsBind = "http.metricsBind"
+
+	MetricsPath = "/metrics"
 )
 
 // Dispatcher
 type Dispatcher interface {
 	Dispatch(http *echo.Echo) error
 }
+
+// Drainer is implemented by the dispatcher which should fail readiness before the shutdown
+type Drainer interface {
+	Drain()
+}
+
+// Waiter is implemented by the dispatcher which runs the background work, the work is waited for
+// after the server shutdown within the grace period
+type Waiter interface {
+	Wait(ctx context.Context) error
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/ProtocolONE/go-core/v2/pkg/logger"
 	"github.com/ProtocolONE/go-core/v2/pkg/provider"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
+	"time"
 )
 
 // HTTP
@@ -14,6 +16,7 @@ type HTTP struct {
 	ctx        context.Context
 	cfg        Config
 	dispatcher Dispatcher
+	inflight   *inflightTracker
 	provider.LMT
 }
 
@@ -24,18 +27,28 @@ func (h *HTTP) ListenAndServe() (err error) {
 	server.HideBanner = true
 	server.HidePort = true
 	server.Debug = h.cfg.Debug
+	server.Use(h.inflight.Middleware)
 
 	if err := h.dispatcher.Dispatch(server); err != nil {
 		return err
 	}
 
+	metrics := h.listenAndServeMetrics()
+
 	h.L().Info("start listen and serve http at %v", logger.Args(h.cfg.Bind))
 
+	done := make(chan struct{})
+
 	go func() {
+		defer close(done)
 		<-h.ctx.Done()
 		h.L().Info("context cancelled, shutdown is raised")
-		if e := server.Shutdown(context.Background()); e != nil {
-			h.L().Error("graceful shutdown error, %v", logger.Args(e))
+		h.shutdown(server)
+
+		if metrics != nil {
+			if e := metrics.Close(); e != nil {
+				h.L().Error("metrics server close error, %v", logger.Args(e))
+			}
 		}
 	}()
 
@@ -47,15 +60,97 @@ func (h *HTTP) ListenAndServe() (err error) {
 		}
 	}
 
+	<-done
 	h.L().Info("http server stopped successfully")
 	return nil
 }
 
+// listenAndServeMetrics serves the metrics on the separate address which isn't exposed by the ingress,
+// the metrics aren't served if the address isn't configured
+func (h *HTTP) listenAndServeMetrics() *echo.Echo {
+	if h.cfg.MetricsBind == "" {
+		return nil
+	}
+
+	server := echo.New()
+	server.HideBanner = true
+	server.HidePort = true
+	server.GET(MetricsPath, echo.WrapHandler(promhttp.Handler()))
+
+	h.L().Info("start listen and serve metrics at %v", logger.Args(h.cfg.MetricsBind))
+
+	go func() {
+		if err := server.Start(h.cfg.MetricsBind); err != nil && err != http.ErrServerClosed {
+			h.L().Error("metrics server error, %v", logger.Args(err))
+		}
+	}()
+
+	return server
+}
+
+// shutdown fails the readiness, waits for the load balancer to stop sending new requests
+// and drains in-flight requests and background tasks during the grace period. Requests which are
+// still in progress at the deadline are aborted and logged.
+func (h *HTTP) shutdown(server *echo.Echo) {
+	if drainer, ok := h.dispatcher.(Drainer); ok {
+		drainer.Drain()
+	}
+
+	if h.cfg.ShutdownDrainDelay > 0 {
+		h.L().Info("readiness failed, wait %v before drain", logger.Args(h.cfg.ShutdownDrainDelay))
+		time.Sleep(h.cfg.ShutdownDrainDelay)
+	}
+
+	ctx := context.Background()
+
+	if h.cfg.ShutdownGracePeriod > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.ShutdownGracePeriod)
+		defer cancel()
+	}
+
+	h.L().Info("drain %v in-flight requests", logger.Args(h.inflight.Count()))
+
+	e := server.Shutdown(ctx)
+
+	if waiter, ok := h.dispatcher.(Waiter); ok {
+		if we := waiter.Wait(ctx); we != nil {
+			h.L().Error("background tasks aren't completed at shutdown, %v", logger.Args(we))
+		}
+	}
+
+	if e == nil {
+		return
+	}
+
+	if e == context.DeadlineExceeded {
+		for _, route := range h.inflight.Summary() {
+			h.L().Error(
+				"request aborted at shutdown deadline",
+				logger.PairArgs("method", route.Method, "route", route.Route),
+				logger.WithFields(logger.Fields{"count": route.Count, "max_age": route.MaxAge.String()}),
+			)
+		}
+	} else {
+		h.L().Error("graceful shutdown error, %v", logger.Args(e))
+	}
+
+	if e = server.Close(); e != nil {
+		h.L().Error("server close error, %v", logger.Args(e))
+	}
+}
+
 // Config
 type Config struct {
-	Debug   bool   `fallback:"shared.debug"`
-	Bind    string `required:"true"`
-	invoker *invoker.Invoker
+	Debug bool   `fallback:"shared.debug"`
+	Bind  string `required:"true"`
+	// Address of the metrics server, the metrics aren't served if it's empty
+	MetricsBind string
+	// Time to wait after readiness is failed, so load balancer stops sending new requests
+	ShutdownDrainDelay time.Duration `default:"5s"`
+	// Time to complete in-flight requests, zero waits infinitely
+	ShutdownGracePeriod time.Duration `default:"30s"`
+	invoker             *invoker.Invoker
 }
 
 // OnReload
@@ -75,6 +170,7 @@ func New(ctx context.Context, set provider.AwareSet, dispatcher Dispatcher, cfg
 		ctx:        ctx,
 		cfg:        *cfg,
 		dispatcher: dispatcher,
+		inflight:   newInflightTracker(),
 		LMT:        &set,
 	}
 }
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"sort"
+	"sync"
+	"time"
+)
+
+type inflightRequest struct {
+	method string
+	route  string
+	start  time.Time
+}
+
+// InflightRoute is the summary of requests in progress for the route
+type InflightRoute struct {
+	Method string
+	Route  string
+	Count  int
+	// Age of the oldest request
+	MaxAge time.Duration
+}
+
+// inflightTracker keeps requests which are in progress
+type inflightTracker struct {
+	mx       sync.Mutex
+	seq      uint64
+	requests map[uint64]inflightRequest
+}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{requests: make(map[uint64]inflightRequest)}
+}
+
+// Middleware
+func (t *inflightTracker) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id := t.add(ctx.Request().Method, ctx.Path())
+		defer t.remove(id)
+		return next(ctx)
+	}
+}
+
+func (t *inflightTracker) add(method, route string) uint64 {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.seq++
+	t.requests[t.seq] = inflightRequest{method: method, route: route, start: time.Now()}
+	return t.seq
+}
+
+func (t *inflightTracker) remove(id uint64) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	delete(t.requests, id)
+}
+
+// Summary returns requests in progress grouped by route, the busiest routes first
+func (t *inflightTracker) Summary() []*InflightRoute {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	now := time.Now()
+	routes := make(map[string]*InflightRoute)
+
+	for _, req := range t.requests {
+		key := req.method + " " + req.route
+		route, ok := routes[key]
+
+		if !ok {
+			route = &InflightRoute{Method: req.method, Route: req.route}
+			routes[key] = route
+		}
+
+		route.Count++
+
+		if age := now.Sub(req.start); age > route.MaxAge {
+			route.MaxAge = age
+		}
+	}
+
+	summary := make([]*InflightRoute, 0, len(routes))
+
+	for _, route := range routes {
+		summary = append(summary, route)
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].Method+summary[i].Route < summary[j].Method+summary[j].Route
+	})
+
+	return summary
+}
+
+// Count returns the number of requests in progress
+func (t *inflightTracker) Count() int {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return len(t.requests)
+}
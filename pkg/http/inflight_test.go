@@ -0,0 +1,73 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInflightTracker_Middleware(t *testing.T) {
+	tracker := newInflightTracker()
+	e := echo.New()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/order/1", nil), httptest.NewRecorder())
+	ctx.SetPath("/api/v1/order/:order_id")
+
+	var summary []*InflightRoute
+	handler := tracker.Middleware(func(ctx echo.Context) error {
+		assert.Equal(t, 1, tracker.Count())
+		summary = tracker.Summary()
+		return nil
+	})
+
+	assert.NoError(t, handler(ctx))
+	assert.Equal(t, 0, tracker.Count())
+	assert.Empty(t, tracker.Summary())
+
+	assert.Len(t, summary, 1)
+	assert.Equal(t, http.MethodGet, summary[0].Method)
+	assert.Equal(t, "/api/v1/order/:order_id", summary[0].Route)
+	assert.Equal(t, 1, summary[0].Count)
+}
+
+func TestInflightTracker_Middleware_Error(t *testing.T) {
+	tracker := newInflightTracker()
+	e := echo.New()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/order", nil), httptest.NewRecorder())
+
+	handler := tracker.Middleware(func(ctx echo.Context) error {
+		return echo.ErrInternalServerError
+	})
+
+	assert.Equal(t, echo.ErrInternalServerError, handler(ctx))
+	assert.Equal(t, 0, tracker.Count())
+}
+
+func TestInflightTracker_Summary(t *testing.T) {
+	tracker := newInflightTracker()
+	tracker.add(http.MethodGet, "/api/v1/order")
+	tracker.add(http.MethodPost, "/api/v1/order/:order_id/refunds")
+	id := tracker.add(http.MethodPost, "/api/v1/order/:order_id/refunds")
+	tracker.add(http.MethodDelete, "/api/v1/order/:order_id")
+
+	assert.Equal(t, 4, tracker.Count())
+
+	summary := tracker.Summary()
+	assert.Len(t, summary, 3)
+
+	// the busiest route first, the routes with the same count by the method and the route
+	assert.Equal(t, "/api/v1/order/:order_id/refunds", summary[0].Route)
+	assert.Equal(t, 2, summary[0].Count)
+	assert.Equal(t, http.MethodDelete, summary[1].Method)
+	assert.Equal(t, http.MethodGet, summary[2].Method)
+
+	for _, route := range summary {
+		assert.True(t, route.MaxAge >= 0)
+	}
+
+	tracker.remove(id)
+	summary = tracker.Summary()
+	assert.Equal(t, 3, tracker.Count())
+	assert.Equal(t, 1, summary[0].Count)
+}
@@ -0,0 +1,75 @@
+package micro
+
+import (
+	"context"
+	"github.com/micro/go-micro/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strconv"
+	"time"
+)
+
+const (
+	metricsResultOk    = "ok"
+	metricsResultError = "error"
+)
+
+var (
+	clientCallDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "micro_client_call_duration_seconds",
+			Help:    "Latency of the go-micro client calls.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"service", "method", "result"},
+	)
+	clientCallErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "micro_client_call_errors_total",
+			Help: "Number of the go-micro client calls failed with transport error or not OK response status.",
+		},
+		[]string{"service", "method", "result"},
+	)
+)
+
+type statusResponse interface {
+	GetStatus() int32
+}
+
+type metricsWrapper struct {
+	client.Client
+}
+
+// Call
+func (w *metricsWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	start := time.Now()
+	err := w.Client.Call(ctx, req, rsp, opts...)
+	result := metricsResult(rsp, err)
+
+	clientCallDuration.WithLabelValues(req.Service(), req.Endpoint(), result).Observe(time.Since(start).Seconds())
+
+	if result != metricsResultOk {
+		clientCallErrors.WithLabelValues(req.Service(), req.Endpoint(), result).Inc()
+	}
+
+	return err
+}
+
+// NewMetricsClientWrapper collects latency and errors of every client call to the prometheus default registry
+func NewMetricsClientWrapper() client.Wrapper {
+	return func(c client.Client) client.Client {
+		return &metricsWrapper{Client: c}
+	}
+}
+
+func metricsResult(rsp interface{}, err error) string {
+	if err != nil {
+		return metricsResultError
+	}
+
+	if typed, ok := rsp.(statusResponse); ok && typed.GetStatus() != 0 && typed.GetStatus() != 200 {
+		return strconv.Itoa(int(typed.GetStatus()))
+	}
+
+	return metricsResultOk
+}
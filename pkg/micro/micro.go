@@ -7,6 +7,7 @@ import (
 	"github.com/ProtocolONE/go-core/v2/pkg/provider"
 	"github.com/ProtocolONE/go-micro-plugins/wrapper/select/version"
 	"github.com/micro/go-micro"
+	"github.com/micro/go-micro/broker"
 	"github.com/micro/go-micro/client"
 	"github.com/micro/go-plugins/client/selector/static"
 )
@@ -23,6 +24,8 @@ func (m *Micro) Client(serviceVersion, fallback string) client.Client {
 	options := []micro.Option{
 		micro.Name(m.cfg.Name),
 		micro.Version(m.cfg.Version),
+		micro.WrapClient(NewMetricsClientWrapper()),
+		micro.WrapClient(NewTracingClientWrapper(m.T())),
 	}
 
 	if len(serviceVersion) > 0 {
@@ -40,6 +43,14 @@ func (m *Micro) Client(serviceVersion, fallback string) client.Client {
 	return service.Client()
 }
 
+// Broker returns the message broker configured by the micro environment
+func (m *Micro) Broker() broker.Broker {
+	service := micro.NewService(micro.Name(m.cfg.Name), micro.Version(m.cfg.Version))
+	service.Init()
+
+	return service.Options().Broker
+}
+
 // Config
 type Config struct {
 	Debug                  bool `fallback:"shared.debug"`
@@ -0,0 +1,65 @@
+package micro
+
+import (
+	"context"
+	"github.com/micro/go-micro/client"
+	"github.com/micro/go-micro/metadata"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/paysuper/paysuper-management-api/pkg/traceparent"
+)
+
+type tracingWrapper struct {
+	client.Client
+	tracer opentracing.Tracer
+}
+
+// Call
+func (w *tracingWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var spanOpts []opentracing.StartSpanOption
+
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		spanOpts = append(spanOpts, opentracing.ChildOf(parent.Context()))
+	}
+
+	span := w.tracer.StartSpan(req.Service()+"."+req.Endpoint(), spanOpts...)
+	defer span.Finish()
+
+	ext.SpanKindRPCClient.Set(span)
+	ext.PeerService.Set(span, req.Service())
+
+	md := metadata.Metadata{}
+
+	if current, ok := metadata.FromContext(ctx); ok {
+		for k, v := range current {
+			md[k] = v
+		}
+	}
+
+	_ = w.tracer.Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(md))
+
+	if value := traceparent.Format(span.Context()); value != "" {
+		md[traceparent.Header] = value
+	}
+
+	ctx = opentracing.ContextWithSpan(metadata.NewContext(ctx, md), span)
+	err := w.Client.Call(ctx, req, rsp, opts...)
+	result := metricsResult(rsp, err)
+
+	span.SetTag("result", result)
+
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("event", "error", "message", err.Error())
+	}
+
+	return err
+}
+
+// NewTracingClientWrapper starts the child span of the request span for every client call and
+// propagates it to the called service in the call metadata
+func NewTracingClientWrapper(tracer opentracing.Tracer) client.Wrapper {
+	return func(c client.Client) client.Client {
+		return &tracingWrapper{Client: c, tracer: tracer}
+	}
+}
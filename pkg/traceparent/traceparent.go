@@ -0,0 +1,80 @@
+// Package traceparent implements W3C Trace Context "traceparent" header codec for jaeger spans.
+package traceparent
+
+import (
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Header is the name of W3C Trace Context header
+	Header = "traceparent"
+
+	version      = "00"
+	flagsSampled = 0x01
+)
+
+// Parse decodes the header value, ok is false when the value is malformed
+func Parse(value string) (sc jaeger.SpanContext, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+
+	// version "ff" is forbidden, version "00" has exactly four fields
+	if _, err := strconv.ParseUint(parts[0], 16, 8); err != nil || parts[0] == "ff" || (parts[0] == version && len(parts) != 4) {
+		return sc, false
+	}
+
+	traceId, err := jaeger.TraceIDFromString(parts[1])
+
+	if err != nil || !traceId.IsValid() {
+		return sc, false
+	}
+
+	spanId, err := jaeger.SpanIDFromString(parts[2])
+
+	if err != nil || spanId == 0 {
+		return sc, false
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+
+	if err != nil {
+		return sc, false
+	}
+
+	return jaeger.NewSpanContext(traceId, spanId, 0, flags&flagsSampled == flagsSampled, nil), true
+}
+
+// Format encodes the span context, returns empty string for non jaeger contexts
+func Format(sc opentracing.SpanContext) string {
+	jsc, ok := sc.(jaeger.SpanContext)
+
+	if !ok || !jsc.IsValid() {
+		return ""
+	}
+
+	flags := 0
+
+	if jsc.IsSampled() {
+		flags = flagsSampled
+	}
+
+	return fmt.Sprintf("%s-%s-%016x-%02x", version, TraceId(jsc), uint64(jsc.SpanID()), flags)
+}
+
+// TraceId returns 32 hex digits trace id of the span context, empty string for non jaeger contexts
+func TraceId(sc opentracing.SpanContext) string {
+	jsc, ok := sc.(jaeger.SpanContext)
+
+	if !ok || !jsc.TraceID().IsValid() {
+		return ""
+	}
+
+	return fmt.Sprintf("%016x%016x", jsc.TraceID().High, jsc.TraceID().Low)
+}
@@ -0,0 +1,108 @@
+package traceparent
+
+import (
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		ok      bool
+		traceId string
+		spanId  uint64
+		sampled bool
+	}{
+		{
+			name:    "sampled",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			ok:      true,
+			traceId: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanId:  0x00f067aa0ba902b7,
+			sampled: true,
+		},
+		{
+			name:    "not sampled",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			ok:      true,
+			traceId: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanId:  0x00f067aa0ba902b7,
+		},
+		{
+			name:    "unknown flags are ignored",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-fe",
+			ok:      true,
+			traceId: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanId:  0x00f067aa0ba902b7,
+		},
+		{
+			name:    "spaces are trimmed",
+			value:   " 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01 ",
+			ok:      true,
+			traceId: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanId:  0x00f067aa0ba902b7,
+			sampled: true,
+		},
+		{
+			name:    "future version with extra fields",
+			value:   "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			ok:      true,
+			traceId: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanId:  0x00f067aa0ba902b7,
+			sampled: true,
+		},
+		{name: "empty", value: ""},
+		{name: "forbidden version", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "version isn't hex", value: "zz-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "version 00 with extra fields", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "short version", value: "0-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "all zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "all zero span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "short trace id", value: "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01"},
+		{name: "trace id isn't hex", value: "00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01"},
+		{name: "span id isn't hex", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902bx-01"},
+		{name: "flags aren't hex", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x"},
+		{name: "short flags", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+		{name: "missing flags", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := Parse(tt.value)
+			assert.Equal(t, tt.ok, ok)
+
+			if !tt.ok {
+				return
+			}
+
+			assert.Equal(t, tt.traceId, TraceId(sc))
+			assert.Equal(t, jaeger.SpanID(tt.spanId), sc.SpanID())
+			assert.Equal(t, tt.sampled, sc.IsSampled())
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		"00-00000000000000000000000000000001-0000000000000001-01",
+	}
+
+	for _, value := range tests {
+		sc, ok := Parse(value)
+		assert.True(t, ok)
+		assert.Equal(t, value, Format(sc))
+	}
+}
+
+func TestFormat_NotJaeger(t *testing.T) {
+	span := mocktracer.New().StartSpan("test")
+
+	assert.Empty(t, Format(span.Context()))
+	assert.Empty(t, TraceId(span.Context()))
+	assert.Empty(t, Format(jaeger.SpanContext{}))
+}